@@ -0,0 +1,122 @@
+package gf
+
+import "fmt"
+
+// binaryField is a specialization of GF(p) for p = 2.
+// In GF(2) the modular operations reduce to single bit operations:
+// addition and subtraction are XOR, multiplication is AND, and the only
+// valid divisor is 1. GF(2^n) fields are built on GF(2), so every
+// coefficient operation in them goes through this fast path.
+type binaryField struct{}
+
+// Prime returns 2, the modulus of GF(2)
+func (f *binaryField) Prime() int16 {
+	return 2
+}
+
+func (f *binaryField) Add(e1, e2 Element) Element {
+	return e1.Add(e2)
+}
+
+func (f *binaryField) Sub(e1, e2 Element) Element {
+	return e1.Sub(e2)
+}
+
+func (f *binaryField) Mul(e1, e2 Element) Element {
+	return e1.Mul(e2)
+}
+
+func (f *binaryField) Div(e1, e2 Element) Element {
+	return e1.Div(e2)
+}
+
+// Element creates a new element with the given value reduced modulo 2.
+func (f *binaryField) Element(value int) Element {
+	return &binaryElement{
+		value: int16(value & 1),
+		field: f,
+	}
+}
+
+func (f *binaryField) Elements() []Element {
+	return []Element{
+		&binaryElement{value: 0, field: f},
+		&binaryElement{value: 1, field: f},
+	}
+}
+
+// binaryElement is an element of GF(2), holding the bit 0 or 1.
+type binaryElement struct {
+	value int16
+	field *binaryField
+}
+
+func (a *binaryElement) Field() Field {
+	return a.field
+}
+
+func (a *binaryElement) Value() int16 {
+	return a.value
+}
+
+// assertSameField performs a type assertion on an element of GF(2).
+// It panics if the element does not belong to a GF(2) field.
+func (a *binaryElement) assertSameField(e Element) *binaryElement {
+	b, ok := e.(*binaryElement)
+	if !ok {
+		panic(fmt.Sprintf("elements are from different fields: GF(2) and %T", e))
+	}
+	return b
+}
+
+// Add performs addition in GF(2): a XOR b.
+func (a *binaryElement) Add(e Element) Element {
+	b := a.assertSameField(e)
+	return &binaryElement{value: a.value ^ b.value, field: a.field}
+}
+
+// Sub performs subtraction in GF(2), which equals addition: a XOR b.
+func (a *binaryElement) Sub(e Element) Element {
+	b := a.assertSameField(e)
+	return &binaryElement{value: a.value ^ b.value, field: a.field}
+}
+
+// Mul performs multiplication in GF(2): a AND b.
+func (a *binaryElement) Mul(e Element) Element {
+	b := a.assertSameField(e)
+	return &binaryElement{value: a.value & b.value, field: a.field}
+}
+
+// Div performs division in GF(2). Since 1 is the only non-zero element
+// and its own inverse, a / 1 = a.
+// It panics if division by zero is attempted.
+func (a *binaryElement) Div(e Element) Element {
+	b := a.assertSameField(e)
+	if b.value == 0 {
+		panic("division by zero")
+	}
+	return &binaryElement{value: a.value, field: a.field}
+}
+
+// Inverse returns the multiplicative inverse in GF(2), which is 1 for 1.
+// It panics if the element is zero.
+func (a *binaryElement) Inverse() Element {
+	if a.value == 0 {
+		panic("zero has no multiplicative inverse in GF(2)")
+	}
+	return a
+}
+
+// Pow computes a^exp in GF(2): 1 to any power is 1, and 0^k is 0 for k > 0.
+// 0^0 = 1, and negative powers of zero panic.
+func (a *binaryElement) Pow(exp int) Element {
+	if a.value == 0 {
+		switch {
+		case exp == 0:
+			return &binaryElement{value: 1, field: a.field}
+		case exp < 0:
+			panic("zero has no multiplicative inverse in GF(2)")
+		}
+	}
+	return a
+}
@@ -4,12 +4,45 @@ import "fmt"
 
 // NewField creates and returns a new finite field GF(p).
 // It is the factory for creating fields. The prime p must be of type int16.
-// Note: This function assumes p is a prime number.
+// It panics if p is not prime; use NewFieldChecked to get an error instead.
+// For p = 2 a specialized field with bitwise operations is returned.
 func NewField(p int16) Field {
+	f, err := NewFieldChecked(p)
+	if err != nil {
+		panic(err.Error())
+	}
+	return f
+}
+
+// NewFieldChecked creates a new finite field GF(p), returning an error if p is
+// not a prime number. Without a prime modulus some non-zero elements have no
+// inverse and division is undefined, so the result would not be a field.
+func NewFieldChecked(p int16) (Field, error) {
 	if p <= 1 {
-		panic("p must be a prime number greater than 1")
+		return nil, fmt.Errorf("p must be a prime number greater than 1, got %d", p)
+	}
+	if !isPrime(p) {
+		return nil, fmt.Errorf("p must be prime, got %d", p)
+	}
+	if p == 2 {
+		return &binaryField{}, nil
+	}
+	return &field{p: p}, nil
+}
+
+// isPrime reports whether p is prime by trial division up to sqrt(p),
+// which takes at most ~90 steps in the int16 range.
+func isPrime(p int16) bool {
+	if p < 2 {
+		return false
+	}
+	n := int(p)
+	for d := 2; d*d <= n; d++ {
+		if n%d == 0 {
+			return false
+		}
 	}
-	return &field{p: p}
+	return true
 }
 
 // field represents the finite field GF(p).
@@ -18,6 +51,11 @@ type field struct {
 	p int16
 }
 
+// Prime returns the modulus p of the field
+func (f *field) Prime() int16 {
+	return f.p
+}
+
 func (f *field) Add(e1, e2 Element) Element {
 	return e1.Add(e2)
 }
@@ -88,25 +126,108 @@ func (a *fieldElement) assertSameField(e Element) *fieldElement {
 
 // Add performs addition of two field elements: (a + b) mod p.
 func (a *fieldElement) Add(e Element) Element {
-	// b := a.assertSameField(e)
-	panic("unimplemented")
+	b := a.assertSameField(e)
+	p := int(a.field.p)
+	return &fieldElement{
+		value: int16((int(a.value) + int(b.value)) % p),
+		field: a.field,
+	}
 }
 
 // Sub performs subtraction of two field elements: (a - b) mod p.
 func (a *fieldElement) Sub(e Element) Element {
-	//b := a.assertSameField(e)
-	panic("unimplemented")
+	b := a.assertSameField(e)
+	p := int(a.field.p)
+	// Adding p keeps the intermediate result non-negative
+	return &fieldElement{
+		value: int16((int(a.value) - int(b.value) + p) % p),
+		field: a.field,
+	}
 }
 
 // Mul performs multiplication of two field elements: (a * b) mod p.
+// The product of two int16 values can overflow int16, so it is computed in int.
 func (a *fieldElement) Mul(e Element) Element {
-	//b := a.assertSameField(e)
-	panic("unimplemented")
+	b := a.assertSameField(e)
+	p := int(a.field.p)
+	return &fieldElement{
+		value: int16((int(a.value) * int(b.value)) % p),
+		field: a.field,
+	}
 }
 
 // Div performs division of two field elements: (a * b^-1) mod p.
 // It panics if division by zero is attempted.
 func (a *fieldElement) Div(e Element) Element {
-	//b := a.assertSameField(e)
-	panic("unimplemented")
+	b := a.assertSameField(e)
+	if b.value == 0 {
+		panic(fmt.Sprintf("division by zero in GF(%d)", a.field.p))
+	}
+	p := int(a.field.p)
+	return &fieldElement{
+		value: int16((int(a.value) * modInverse(int(b.value), p)) % p),
+		field: a.field,
+	}
+}
+
+// Inverse returns the multiplicative inverse b^-1 mod p.
+// It panics if the element is zero.
+func (a *fieldElement) Inverse() Element {
+	if a.value == 0 {
+		panic(fmt.Sprintf("zero has no multiplicative inverse in GF(%d)", a.field.p))
+	}
+	return &fieldElement{
+		value: int16(modInverse(int(a.value), int(a.field.p))),
+		field: a.field,
+	}
+}
+
+// Pow computes a^exp mod p by square-and-multiply.
+//
+// By Fermat's little theorem a^(p-1) = 1 for every non-zero a, so the exponent
+// is first reduced modulo p-1. This also turns a negative exponent into the
+// equivalent positive one, since a^-k = a^(p-1-k). Zero has 0^0 = 1 and 0^k = 0
+// for k > 0; negative powers of zero panic.
+func (a *fieldElement) Pow(exp int) Element {
+	if a.value == 0 {
+		switch {
+		case exp == 0:
+			return &fieldElement{value: 1, field: a.field}
+		case exp > 0:
+			return &fieldElement{value: 0, field: a.field}
+		default:
+			panic(fmt.Sprintf("zero has no multiplicative inverse in GF(%d)", a.field.p))
+		}
+	}
+
+	p := int(a.field.p)
+	exp %= p - 1
+	if exp < 0 {
+		exp += p - 1
+	}
+
+	result, base := 1, int(a.value)
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			result = result * base % p
+		}
+		base = base * base % p
+	}
+	return &fieldElement{value: int16(result), field: a.field}
+}
+
+// modInverse returns b^-1 mod p for 0 < b < p, using the extended Euclidean algorithm.
+//
+// The algorithm maintains s such that s * b ≡ r (mod p) for every remainder r
+// of the Euclidean algorithm on (b, p). Since p is prime, the last non-zero
+// remainder is gcd(b, p) = 1, and its s is the inverse.
+func modInverse(b, p int) int {
+	oldR, r := b, p
+	oldS, s := 1, 0
+	for r != 0 {
+		quotient := oldR / r
+		oldR, r = r, oldR-quotient*r
+		oldS, s = s, oldS-quotient*s
+	}
+	return (oldS%p + p) % p
 }
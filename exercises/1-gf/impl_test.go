@@ -0,0 +1,238 @@
+package gf
+
+import (
+	"testing"
+)
+
+func TestBinaryFieldMatchesModular(t *testing.T) {
+	binary := NewField(2)
+	if _, ok := binary.(*binaryField); !ok {
+		t.Fatalf("NewField(2) returned %T, want the GF(2) specialization", binary)
+	}
+	modular := &field{p: 2}
+
+	for a := 0; a < 2; a++ {
+		for b := 0; b < 2; b++ {
+			ba, bb := binary.Element(a), binary.Element(b)
+			ma, mb := modular.Element(a), modular.Element(b)
+
+			if got, want := binary.Add(ba, bb).Value(), modular.Add(ma, mb).Value(); got != want {
+				t.Errorf("%d + %d = %d, want %d", a, b, got, want)
+			}
+			if got, want := binary.Sub(ba, bb).Value(), modular.Sub(ma, mb).Value(); got != want {
+				t.Errorf("%d - %d = %d, want %d", a, b, got, want)
+			}
+			if got, want := binary.Mul(ba, bb).Value(), modular.Mul(ma, mb).Value(); got != want {
+				t.Errorf("%d * %d = %d, want %d", a, b, got, want)
+			}
+			if b != 0 {
+				if got, want := binary.Div(ba, bb).Value(), modular.Div(ma, mb).Value(); got != want {
+					t.Errorf("%d / %d = %d, want %d", a, b, got, want)
+				}
+			}
+		}
+	}
+
+	// Element reduces its argument modulo 2 like the generic field
+	for _, v := range []int{-3, -1, 2, 5} {
+		if got, want := binary.Element(v).Value(), modular.Element(v).Value(); got != want {
+			t.Errorf("Element(%d) = %d, want %d", v, got, want)
+		}
+	}
+}
+
+func TestBinaryFieldDivByZero(t *testing.T) {
+	f := NewField(2)
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic on division by zero")
+		}
+	}()
+	f.Div(f.Element(1), f.Element(0))
+}
+
+func BenchmarkGF2Mul(b *testing.B) {
+	benchmarkMul(b, NewField(2))
+}
+
+func BenchmarkGF2MulModular(b *testing.B) {
+	benchmarkMul(b, &field{p: 2})
+}
+
+func benchmarkMul(b *testing.B, f Field) {
+	x, y := f.Element(1), f.Element(1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x = f.Mul(x, y)
+	}
+}
+
+func TestFieldArithmetic(t *testing.T) {
+	f := NewField(7)
+
+	tests := []struct {
+		name string
+		got  Element
+		want int16
+	}{
+		{"3 + 5", f.Add(f.Element(3), f.Element(5)), 1},
+		{"3 - 5", f.Sub(f.Element(3), f.Element(5)), 5},
+		{"3 * 5", f.Mul(f.Element(3), f.Element(5)), 1},
+		{"3 / 5", f.Div(f.Element(3), f.Element(5)), 2},
+		{"6 / 6", f.Div(f.Element(6), f.Element(6)), 1},
+		{"0 / 4", f.Div(f.Element(0), f.Element(4)), 0},
+	}
+	for _, tt := range tests {
+		if tt.got.Value() != tt.want {
+			t.Errorf("%s = %d, want %d", tt.name, tt.got.Value(), tt.want)
+		}
+	}
+
+	// Division undoes multiplication for every non-zero divisor, also where
+	// products overflow int16
+	for _, p := range []int16{5, 7, 251, 32749} {
+		f := NewField(p)
+		for _, a := range []int{1, 2, int(p) / 2, int(p) - 1} {
+			for _, b := range []int{1, 3, int(p) - 2, int(p) - 1} {
+				x, y := f.Element(a), f.Element(b)
+				if got := f.Div(f.Mul(x, y), y); got.Value() != x.Value() {
+					t.Errorf("GF(%d): (%d * %d) / %d = %d", p, a, b, b, got.Value())
+				}
+				if got := f.Add(f.Sub(x, y), y); got.Value() != x.Value() {
+					t.Errorf("GF(%d): (%d - %d) + %d = %d", p, a, b, b, got.Value())
+				}
+			}
+		}
+	}
+}
+
+func TestFieldDivByZero(t *testing.T) {
+	f := NewField(7)
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic on division by zero")
+		}
+	}()
+	f.Div(f.Element(3), f.Element(0))
+}
+
+func TestFieldMixedFields(t *testing.T) {
+	gf5, gf7 := NewField(5), NewField(7)
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatalf("expected panic when adding elements of GF(5) and GF(7)")
+		}
+		if msg, _ := r.(string); msg != "elements are from different fields: GF(5) and GF(7)" {
+			t.Errorf("panic message = %v", r)
+		}
+	}()
+	gf5.Add(gf5.Element(1), gf7.Element(1))
+}
+
+func TestInverseAndPow(t *testing.T) {
+	for _, p := range []int16{2, 7, 13, 32749} {
+		f := NewField(p)
+		order := int(p)
+		for _, v := range []int{1, (order + 1) / 2, order - 1} {
+			a := f.Element(v)
+			one := f.Element(1)
+
+			inverse := a.Inverse()
+			if got := a.Mul(inverse); got.Value() != 1 {
+				t.Errorf("GF(%d): %d * %d^-1 = %d, want 1", p, v, v, got.Value())
+			}
+			if got := a.Pow(-1); got.Value() != inverse.Value() {
+				t.Errorf("GF(%d): %d^-1 = %d, want Inverse() = %d", p, v, got.Value(), inverse.Value())
+			}
+			if got := a.Pow(0); got.Value() != 1 {
+				t.Errorf("GF(%d): %d^0 = %d, want 1", p, v, got.Value())
+			}
+
+			// Fermat: a^(p-1) = 1, so exponents wrap modulo p-1
+			if got := a.Pow(order - 1); got.Value() != 1 {
+				t.Errorf("GF(%d): %d^(p-1) = %d, want 1", p, v, got.Value())
+			}
+			if got, want := a.Pow(order), a; got.Value() != want.Value() {
+				t.Errorf("GF(%d): %d^p = %d, want %d", p, v, got.Value(), want.Value())
+			}
+			if got, want := a.Pow(order-2), inverse; got.Value() != want.Value() {
+				t.Errorf("GF(%d): %d^(p-2) = %d, want %d", p, v, got.Value(), want.Value())
+			}
+			if got, want := a.Pow(-(order - 1)), one; got.Value() != want.Value() {
+				t.Errorf("GF(%d): %d^-(p-1) = %d, want 1", p, v, got.Value())
+			}
+
+			// Square-and-multiply agrees with repeated multiplication
+			product := one
+			for k := 1; k <= 10; k++ {
+				product = product.Mul(a)
+				if got := a.Pow(k); got.Value() != product.Value() {
+					t.Errorf("GF(%d): %d^%d = %d, want %d", p, v, k, got.Value(), product.Value())
+				}
+			}
+
+			// Large exponents reduce without overflow
+			if got, want := a.Pow(1<<40+3), a.Pow((1<<40+3)%(order-1)); order > 2 && got.Value() != want.Value() {
+				t.Errorf("GF(%d): %d^(2^40+3) = %d, want %d", p, v, got.Value(), want.Value())
+			}
+		}
+
+		zero := f.Element(0)
+		if zero.Pow(0).Value() != 1 || zero.Pow(5).Value() != 0 {
+			t.Errorf("GF(%d): 0^0 = %d, 0^5 = %d, want 1, 0", p, zero.Pow(0).Value(), zero.Pow(5).Value())
+		}
+		for name, f := range map[string]func(){"Inverse": func() { zero.Inverse() }, "Pow(-1)": func() { zero.Pow(-1) }} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("GF(%d): 0.%s did not panic", p, name)
+					}
+				}()
+				f()
+			}()
+		}
+	}
+}
+
+func TestFieldPrime(t *testing.T) {
+	for _, p := range []int16{2, 3, 13, 32749} {
+		f := NewField(p)
+		if got := f.Prime(); got != p {
+			t.Errorf("NewField(%d).Prime() = %d, want %d", p, got, p)
+		}
+		if got := len(f.Elements()); got != int(p) {
+			t.Errorf("NewField(%d) has %d elements, want %d", p, got, p)
+		}
+	}
+}
+
+func TestNewFieldChecked(t *testing.T) {
+	for _, p := range []int16{2, 3, 251, 32749} {
+		f, err := NewFieldChecked(p)
+		if err != nil {
+			t.Errorf("NewFieldChecked(%d) returned error: %v", p, err)
+			continue
+		}
+		if f.Prime() != p {
+			t.Errorf("NewFieldChecked(%d).Prime() = %d", p, f.Prime())
+		}
+	}
+
+	for _, p := range []int16{-7, 0, 1, 4, 9, 15, 32767} {
+		if _, err := NewFieldChecked(p); err == nil {
+			t.Errorf("NewFieldChecked(%d) succeeded, want error", p)
+		}
+	}
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("NewField(4) did not panic")
+		}
+		if want := "p must be prime, got 4"; r != want {
+			t.Errorf("NewField(4) panicked with %q, want %q", r, want)
+		}
+	}()
+	NewField(4)
+}
@@ -1,6 +1,9 @@
 package gf
 
 type Field interface {
+	// Prime returns the prime p of GF(p), which is also its characteristic
+	Prime() int16
+
 	Elements() []Element
 	Element(int) Element
 	Add(e1, e2 Element) Element
@@ -11,7 +14,7 @@ type Field interface {
 
 // Element defines the interface for an element in a finite field GF(p).
 // It supports basic arithmetic operations: addition, subtraction,
-// multiplication, and division.
+// multiplication, and division, as well as inverses and powers.
 type Element interface {
 	Field() Field
 	Value() int16
@@ -19,4 +22,11 @@ type Element interface {
 	Sub(e Element) Element
 	Mul(e Element) Element
 	Div(e Element) Element
+
+	// Inverse returns the multiplicative inverse. It panics on zero.
+	Inverse() Element
+
+	// Pow raises the element to an integer power. Negative exponents are
+	// powers of the inverse, and any element to the power 0 is one.
+	Pow(exp int) Element
 }
@@ -1,6 +1,8 @@
 package arithpoly
 
 import (
+	"math/big"
+
 	"github.com/jalphad/abstract_algebra/exercises/1-gf"
 )
 
@@ -22,6 +24,164 @@ func PolyDiv(field gf.Field, dividend, divisor Polynomial) (quotient, remainder
 	panic("not implemented")
 }
 
+// PolyGCD returns the monic greatest common divisor of two polynomials over GF(p)
+// The gcd of two zero polynomials is the zero polynomial
+func PolyGCD(field gf.Field, a, b Polynomial) Polynomial {
+	g, _, _ := PolyExtendedGCD(field, a, b)
+	return g
+}
+
+// PolyExtendedGCD runs the extended Euclidean algorithm on two polynomials over GF(p)
+// Returns the monic gcd g together with Bézout coefficients s and t such that
+// s·a + t·b = g. If b is a unit modulo a (g = 1), t is the inverse of b mod a,
+// which is how inverses in GF(p^n) = GF(p)[x]/(f) are found without power tables.
+func PolyExtendedGCD(field gf.Field, a, b Polynomial) (g, s, t Polynomial) {
+	one := field.Element(1)
+
+	// Invariants: s0·a + t0·b = r0 and s1·a + t1·b = r1
+	r0, r1 := trimPoly(append(Polynomial{}, a...)), trimPoly(append(Polynomial{}, b...))
+	s0, s1 := Polynomial{one}, Polynomial{}
+	t0, t1 := Polynomial{}, Polynomial{one}
+
+	for !isZeroPoly(r1) {
+		quotient, remainder := PolyDiv(field, r0, r1)
+		r0, r1 = r1, trimPoly(remainder)
+		s0, s1 = s1, polySub(field, s0, polyMulOrZero(field, quotient, s1))
+		t0, t1 = t1, polySub(field, t0, polyMulOrZero(field, quotient, t1))
+	}
+
+	if isZeroPoly(r0) {
+		return Polynomial{}, Polynomial{}, Polynomial{}
+	}
+
+	// Scale by the inverse of the leading coefficient to make the gcd monic
+	scale := field.Div(one, r0[degree(r0)])
+	return polyScale(field, scale, r0), polyScale(field, scale, s0), polyScale(field, scale, t0)
+}
+
+// IsIrreducible reports whether p cannot be factored into polynomials of lower degree over GF(q)
+//
+// Rabin's test: p of degree n is irreducible iff
+//   - x^(q^n) ≡ x (mod p), i.e. p divides x^(q^n) - x, the product of all monic
+//     irreducibles whose degree divides n, and
+//   - gcd(x^(q^(n/d)) - x, p) = 1 for each prime d | n, so p has no factor of a
+//     degree dividing n/d.
+//
+// The powers x^(q^k) mod p are found by raising to the q-th power k times, so the
+// exponents stay small however large q^n is. Constants, including zero, are not
+// irreducible; every polynomial of degree 1 is.
+func IsIrreducible(field gf.Field, p Polynomial) bool {
+	p = trimPoly(p)
+	n := degree(p)
+	if n < 1 {
+		return false
+	}
+	if n == 1 {
+		return true
+	}
+
+	q := big.NewInt(int64(field.Prime()))
+	x := Polynomial{field.Element(0), field.Element(1)}
+
+	// frobenius[k] = x^(q^k) mod p
+	frobenius := make([]Polynomial, n+1)
+	frobenius[0] = x
+	for k := 1; k <= n; k++ {
+		frobenius[k] = PolyPowMod(field, frobenius[k-1], q, p)
+	}
+
+	if !isZeroPoly(polySub(field, frobenius[n], x)) {
+		return false
+	}
+	for _, d := range primeDivisors(n) {
+		g := PolyGCD(field, polySub(field, frobenius[n/d], x), p)
+		if degree(g) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// PolyPowMod computes base^exp mod modulus over GF(p) by square-and-multiply
+//
+// Every product is reduced modulo the modulus with PolyDiv straight away, so
+// intermediate results stay below the modulus' degree and exponents such as
+// x^(p^n) take O(log exp) multiplications instead of exp. exp must not be
+// negative; base^0 is 1 reduced modulo the modulus (0 if the modulus is constant).
+// Panics if the modulus is the zero polynomial.
+func PolyPowMod(field gf.Field, base Polynomial, exp *big.Int, modulus Polynomial) Polynomial {
+	if exp.Sign() < 0 {
+		panic("negative exponent")
+	}
+
+	reduce := func(a Polynomial) Polynomial {
+		_, remainder := PolyDiv(field, a, modulus)
+		return trimPoly(remainder)
+	}
+
+	result := reduce(Polynomial{field.Element(1)})
+	base = reduce(base)
+	for i := exp.BitLen() - 1; i >= 0; i-- {
+		result = reduce(polyMulOrZero(field, result, result))
+		if exp.Bit(i) == 1 {
+			result = reduce(polyMulOrZero(field, result, base))
+		}
+	}
+	return result
+}
+
+// primeDivisors returns the distinct prime divisors of n in increasing order
+func primeDivisors(n int) []int {
+	var divisors []int
+	for d := 2; d*d <= n; d++ {
+		if n%d == 0 {
+			divisors = append(divisors, d)
+			for n%d == 0 {
+				n /= d
+			}
+		}
+	}
+	if n > 1 {
+		divisors = append(divisors, n)
+	}
+	return divisors
+}
+
+// polySub returns p1 - p2, trimmed
+func polySub(field gf.Field, p1, p2 Polynomial) Polynomial {
+	n := max(len(p1), len(p2))
+	result := make(Polynomial, n)
+	for i := range result {
+		a, b := field.Element(0), field.Element(0)
+		if i < len(p1) {
+			a = p1[i]
+		}
+		if i < len(p2) {
+			b = p2[i]
+		}
+		result[i] = field.Sub(a, b)
+	}
+	return trimPoly(result)
+}
+
+// polyScale returns c·p, trimmed
+func polyScale(field gf.Field, c gf.Element, p Polynomial) Polynomial {
+	result := make(Polynomial, len(p))
+	for i, coeff := range p {
+		result[i] = field.Mul(c, coeff)
+	}
+	return trimPoly(result)
+}
+
+// polyMulOrZero multiplies two polynomials, returning the zero polynomial directly
+// if either factor is zero rather than relying on PolyMul to handle empty slices
+func polyMulOrZero(field gf.Field, p1, p2 Polynomial) Polynomial {
+	if isZeroPoly(p1) || isZeroPoly(p2) {
+		return Polynomial{}
+	}
+	return trimPoly(PolyMul(field, p1, p2))
+}
+
 // degree returns the degree of the polynomial (-1 for zero polynomial)
 func degree(p Polynomial) int {
 	for i := len(p) - 1; i >= 0; i-- {
@@ -0,0 +1,224 @@
+package arithpoly
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/jalphad/abstract_algebra/exercises/1-gf"
+)
+
+// poly builds a polynomial over field from integer coefficients, lowest degree first
+func poly(field gf.Field, coeffs ...int) Polynomial {
+	p := make(Polynomial, len(coeffs))
+	for i, c := range coeffs {
+		p[i] = field.Element(c)
+	}
+	return p
+}
+
+// values returns the integer coefficients of a polynomial, lowest degree first
+func values(p Polynomial) []int16 {
+	v := make([]int16, len(p))
+	for i, c := range p {
+		v[i] = c.Value()
+	}
+	return v
+}
+
+func TestPolyExtendedGCD(t *testing.T) {
+	gf2 := gf.NewField(2)
+	gf5 := gf.NewField(5)
+
+	tests := []struct {
+		name  string
+		field gf.Field
+		a, b  Polynomial
+		want  string
+	}{
+		{
+			// x^4 + x + 1 is irreducible, so it is coprime to any lower degree polynomial
+			name:  "coprime over GF(2)",
+			field: gf2,
+			a:     poly(gf2, 1, 1, 0, 0, 1),
+			b:     poly(gf2, 1, 1, 1),
+			want:  "[1]",
+		},
+		{
+			// (x + 1)(x^2 + x + 1) and (x + 1)^2 share the factor x + 1
+			name:  "shared factor over GF(2)",
+			field: gf2,
+			a:     poly(gf2, 1, 0, 0, 1),
+			b:     poly(gf2, 1, 0, 1),
+			want:  "[1 1]",
+		},
+		{
+			// 2(x - 1)(x - 2) and 3(x - 2)(x - 3): the gcd x - 2 is made monic
+			name:  "shared factor over GF(5)",
+			field: gf5,
+			a:     poly(gf5, 4, 4, 2),
+			b:     poly(gf5, 3, 0, 3),
+			want:  "[3 1]",
+		},
+		{
+			name:  "gcd with zero is the monic other polynomial",
+			field: gf5,
+			a:     poly(gf5, 2, 0, 4),
+			b:     Polynomial{},
+			want:  "[3 0 1]",
+		},
+		{
+			name:  "both zero",
+			field: gf5,
+			a:     poly(gf5, 0, 0),
+			b:     Polynomial{},
+			want:  "[]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, s, tc := PolyExtendedGCD(tt.field, tt.a, tt.b)
+			if got := fmt.Sprint(values(g)); got != tt.want {
+				t.Errorf("gcd = %s, want %s", got, tt.want)
+			}
+			if got := fmt.Sprint(values(PolyGCD(tt.field, tt.a, tt.b))); got != tt.want {
+				t.Errorf("PolyGCD = %s, want %s", got, tt.want)
+			}
+
+			// Bézout identity s·a + t·b = g
+			combination := polySub(tt.field,
+				polyMulOrZero(tt.field, s, tt.a),
+				polyScale(tt.field, tt.field.Element(-1), polyMulOrZero(tt.field, tc, tt.b)))
+			if fmt.Sprint(values(combination)) != fmt.Sprint(values(g)) {
+				t.Errorf("s·a + t·b = %v, want gcd %v (s = %v, t = %v)",
+					values(combination), values(g), values(s), values(tc))
+			}
+		})
+	}
+}
+
+func TestPolyExtendedGCD_InverseModIrreducible(t *testing.T) {
+	// In GF(16) = GF(2)[x]/(x^4 + x + 1) every non-zero b has inverse t from t·b ≡ 1
+	gf2 := gf.NewField(2)
+	modulus := poly(gf2, 1, 1, 0, 0, 1)
+	for n := 1; n < 16; n++ {
+		b := poly(gf2, n&1, n>>1&1, n>>2&1, n>>3&1)
+		g, _, inverse := PolyExtendedGCD(gf2, modulus, b)
+		if fmt.Sprint(values(g)) != "[1]" {
+			t.Fatalf("gcd(x^4 + x + 1, %v) = %v, want 1", values(b), values(g))
+		}
+		_, remainder := PolyDiv(gf2, polyMulOrZero(gf2, inverse, b), modulus)
+		if got := fmt.Sprint(values(trimPoly(remainder))); got != "[1]" {
+			t.Errorf("%v · %v mod (x^4 + x + 1) = %s, want 1", values(b), values(inverse), got)
+		}
+	}
+}
+
+func TestIsIrreducible(t *testing.T) {
+	gf2 := gf.NewField(2)
+	gf3 := gf.NewField(3)
+
+	tests := []struct {
+		name  string
+		field gf.Field
+		p     Polynomial
+		want  bool
+	}{
+		{"QR polynomial x^8+x^4+x^3+x^2+1", gf2, poly(gf2, 1, 0, 1, 1, 1, 0, 0, 0, 1), true},
+		{"AES polynomial x^8+x^4+x^3+x+1", gf2, poly(gf2, 1, 1, 0, 1, 1, 0, 0, 0, 1), true},
+		{"x^2+1 = (x+1)^2", gf2, poly(gf2, 1, 0, 1), false},
+		{"x^4+x^2+1 = (x^2+x+1)^2 has no roots", gf2, poly(gf2, 1, 0, 1, 0, 1), false},
+		{"x^4+x^3+x^2+x+1 is irreducible but not primitive", gf2, poly(gf2, 1, 1, 1, 1, 1), true},
+		{"x^2+1 over GF(3)", gf3, poly(gf3, 1, 0, 1), true},
+		{"x^2+2 = (x+1)(x+2) over GF(3)", gf3, poly(gf3, 2, 0, 1), false},
+		{"non-monic 2x^3+4x+2 = 2(x^3+2x+1) over GF(3)", gf3, poly(gf3, 2, 1, 0, 2), true},
+		{"linear", gf3, poly(gf3, 1, 2), true},
+		{"constant", gf3, poly(gf3, 2), false},
+		{"zero with padding", gf3, poly(gf3, 0, 0, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsIrreducible(tt.field, tt.p); got != tt.want {
+				t.Errorf("IsIrreducible(%v) = %v, want %v", values(tt.p), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsIrreducible_Counts(t *testing.T) {
+	// Number of monic irreducible polynomials of degree n over GF(q), by Gauss's formula
+	tests := []struct {
+		q    int16
+		n    int
+		want int
+	}{
+		{2, 4, 3},
+		{2, 6, 9},
+		{3, 3, 8},
+		{5, 2, 10},
+	}
+
+	for _, tt := range tests {
+		field := gf.NewField(tt.q)
+		total := 1
+		for i := 0; i < tt.n; i++ {
+			total *= int(tt.q)
+		}
+
+		count := 0
+		for c := 0; c < total; c++ {
+			p := make(Polynomial, tt.n+1)
+			for i, rest := 0, c; i < tt.n; i, rest = i+1, rest/int(tt.q) {
+				p[i] = field.Element(rest % int(tt.q))
+			}
+			p[tt.n] = field.Element(1)
+			if IsIrreducible(field, p) {
+				count++
+			}
+		}
+		if count != tt.want {
+			t.Errorf("GF(%d): %d monic irreducibles of degree %d, want %d", tt.q, count, tt.n, tt.want)
+		}
+	}
+}
+
+func TestPolyPowMod(t *testing.T) {
+	gf2 := gf.NewField(2)
+	qr := poly(gf2, 1, 0, 1, 1, 1, 0, 0, 0, 1)
+	x := poly(gf2, 0, 1)
+
+	// The Frobenius map has order 8 in GF(2^8), so x^(2^8) = x
+	if got := PolyPowMod(gf2, x, big.NewInt(256), qr); fmt.Sprint(values(got)) != "[0 1]" {
+		t.Errorf("x^256 mod QR polynomial = %v, want x", values(got))
+	}
+	// x is primitive, so x^255 = 1 and x^8 = x^4 + x^3 + x^2 + 1
+	if got := PolyPowMod(gf2, x, big.NewInt(255), qr); fmt.Sprint(values(got)) != "[1]" {
+		t.Errorf("x^255 mod QR polynomial = %v, want 1", values(got))
+	}
+	if got := PolyPowMod(gf2, x, big.NewInt(8), qr); fmt.Sprint(values(got)) != "[1 0 1 1 1]" {
+		t.Errorf("x^8 mod QR polynomial = %v, want x^4 + x^3 + x^2 + 1", values(got))
+	}
+
+	// An exponent far beyond uint64 reduces like its residue mod 255
+	huge := new(big.Int).Lsh(big.NewInt(1), 200)
+	residue := new(big.Int).Mod(huge, big.NewInt(255))
+	want := PolyPowMod(gf2, x, residue, qr)
+	if got := PolyPowMod(gf2, x, huge, qr); fmt.Sprint(values(got)) != fmt.Sprint(values(want)) {
+		t.Errorf("x^(2^200) = %v, want x^%s = %v", values(got), residue, values(want))
+	}
+
+	// Agrees with repeated multiplication over GF(5)
+	gf5 := gf.NewField(5)
+	modulus := poly(gf5, 2, 0, 1, 1)
+	base := poly(gf5, 3, 4, 1)
+	product := Polynomial{gf5.Element(1)}
+	for k := 0; k <= 20; k++ {
+		if got := PolyPowMod(gf5, base, big.NewInt(int64(k)), modulus); fmt.Sprint(values(got)) != fmt.Sprint(values(product)) {
+			t.Errorf("base^%d = %v, want %v", k, values(got), values(product))
+		}
+		_, product = PolyDiv(gf5, polyMulOrZero(gf5, product, base), modulus)
+		product = trimPoly(product)
+	}
+}
@@ -2,6 +2,7 @@ package gfpn
 
 import (
 	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/jalphad/abstract_algebra/exercises/1-gf"
@@ -39,13 +40,19 @@ func NewField(p int16, n int, irreducibleCoeffs []int) (Field, error) {
 		return nil, fmt.Errorf("irreducible polynomial must be monic (leading coefficient must be 1)")
 	}
 
-	baseField := gf.NewField(p)
+	baseField, err := gf.NewFieldChecked(p)
+	if err != nil {
+		return nil, err
+	}
 
 	// Convert irreducible coefficients to polynomial
 	irreducible := make(arithpoly.Polynomial, n+1)
 	for i, c := range irreducibleCoeffs {
 		irreducible[i] = baseField.Element(c)
 	}
+	if !arithpoly.IsIrreducible(baseField, irreducible) {
+		return nil, fmt.Errorf("polynomial %v is reducible over GF(%d)", irreducibleCoeffs, p)
+	}
 
 	// Calculate order
 	order := 1
@@ -103,6 +110,65 @@ func NewField(p int16, n int, irreducibleCoeffs []int) (Field, error) {
 	return f, nil
 }
 
+// NewFieldAuto creates GF(p^n) from a primitive polynomial it finds itself
+//
+// Candidates x^n + a(n-1)·x^(n-1) + ... + a0 are tried in increasing order of
+// their coefficients read as a base-p number with a0 as the least significant
+// digit, and the first one for which x generates the multiplicative group of
+// GF(p)[x]/(f) is used. Such a polynomial is necessarily irreducible: if f had a
+// factor, GF(p)[x]/(f) would contain zero divisors and fewer than p^n - 1 units,
+// so no element could have order p^n - 1. Primitive polynomials exist for every p
+// and n, so the search always succeeds, and it makes α = x, which keeps the
+// tables in the familiar form. For GF(2^8) the first match is
+// x^8 + x^4 + x^3 + x^2 + 1, the QR code polynomial.
+func NewFieldAuto(p int16, n int) (Field, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("n must be at least 1")
+	}
+	baseField, err := gf.NewFieldChecked(p)
+	if err != nil {
+		return nil, err
+	}
+
+	order := 1
+	for i := 0; i < n; i++ {
+		order *= int(p)
+	}
+
+	x := make(arithpoly.Polynomial, max(n, 2))
+	for i := range x {
+		x[i] = baseField.Element(0)
+	}
+	x[1] = baseField.Element(1)
+
+	// Candidates with a0 = 0 are divisible by x, so start from 1
+	coeffs := make([]int, n+1)
+	for c := 1; c < order; c++ {
+		for i, rest := 0, c; i < n; i, rest = i+1, rest/int(p) {
+			coeffs[i] = rest % int(p)
+		}
+		coeffs[n] = 1
+		if coeffs[0] == 0 {
+			continue
+		}
+
+		candidate := &field{
+			baseField:   baseField,
+			degree:      n,
+			order:       order,
+			irreducible: make(arithpoly.Polynomial, n+1),
+		}
+		for i, a := range coeffs {
+			candidate.irreducible[i] = baseField.Element(a)
+		}
+		if candidate.isPrimitivePoly(x) {
+			return NewField(p, n, coeffs)
+		}
+	}
+
+	return nil, fmt.Errorf("no primitive polynomial of degree %d found over GF(%d)", n, p)
+}
+
 // computeOrder computes the multiplicative order of a polynomial element
 // Returns the smallest positive integer k such that element^k ≡ 1 (mod irreducible)
 func (f *field) computeOrder(element arithpoly.Polynomial) (int, error) {
@@ -137,23 +203,84 @@ func (f *field) computeOrder(element arithpoly.Polynomial) (int, error) {
 	return 0, fmt.Errorf("failed to find order (element may be zero)")
 }
 
+// primeFactors returns the distinct prime factors of n in increasing order, by trial division
+func primeFactors(n int) []int {
+	var factors []int
+	for q := 2; q*q <= n; q++ {
+		if n%q == 0 {
+			factors = append(factors, q)
+			for n%q == 0 {
+				n /= q
+			}
+		}
+	}
+	if n > 1 {
+		factors = append(factors, n)
+	}
+	return factors
+}
+
+// powPoly computes element^k mod the irreducible polynomial by square-and-multiply
+// The result is padded to the field degree, as used for table keys
+func (f *field) powPoly(element arithpoly.Polynomial, k int) arithpoly.Polynomial {
+	power := arithpoly.PolyPowMod(f.baseField, element, big.NewInt(int64(k)), f.irreducible)
+	result := make(arithpoly.Polynomial, f.degree)
+	copy(result, power)
+	for i := len(power); i < f.degree; i++ {
+		result[i] = f.baseField.Element(0)
+	}
+	return result
+}
+
+// isPrimitivePoly reports whether a polynomial element generates the multiplicative group
+//
+// The order of a non-zero element divides p^n - 1, so it is exactly p^n - 1 unless
+// it divides (p^n - 1)/q for some prime q | p^n - 1. Checking e^((p^n-1)/q) ≠ 1 for
+// each such q takes O(log p^n) multiplications per prime instead of up to p^n - 1
+// for computeOrder.
+func (f *field) isPrimitivePoly(element arithpoly.Polynomial) bool {
+	one := f.powPoly(element, 0)
+	groupOrder := f.order - 1
+
+	// e^(p^n-1) = 1 holds for every non-zero element of a field; it fails for zero
+	// and when the modulus is not actually irreducible
+	if polyKey(f.powPoly(element, groupOrder)) != polyKey(one) {
+		return false
+	}
+
+	for _, q := range primeFactors(groupOrder) {
+		if polyKey(f.powPoly(element, groupOrder/q)) == polyKey(one) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPrimitive reports whether e generates the multiplicative group, i.e. has order p^n - 1
+// The test works on e's polynomial representation (see isPrimitivePoly), so it does
+// not rely on the power tables
+func (f *field) IsPrimitive(e Element) bool {
+	el := f.oneElement.assertSameField(e)
+	if el.IsZero() {
+		return false
+	}
+	return f.isPrimitivePoly(el.coeffs)
+}
+
 // findPrimitiveElement searches for a primitive element (generator) of the multiplicative group
 // A primitive element has order p^n - 1
 func (f *field) findPrimitiveElement() (arithpoly.Polynomial, error) {
-	targetOrder := f.order - 1
-
-	// Try various candidates
-	// For degree 1 (GF(p^1) = GF(p)), use 2 as generator (except for p=2, use 1)
+	// For degree 1 (GF(p^1) = GF(p)), α = x is not an element: the elements are the
+	// constants, and a primitive element is a primitive root modulo p. Take the
+	// smallest; 2 is one for p = 3, 5, 11, 13 but not for p = 7 or 17.
 	if f.degree == 1 {
-		elem := make(arithpoly.Polynomial, 1)
-		// For p=2, use 1 (only element in GF(2)*)
-		// For p>2, use 2 as the generator
-		if len(f.baseField.Elements()) == 2 {
-			elem[0] = f.baseField.Element(1)
-		} else {
-			elem[0] = f.baseField.Element(2)
+		for c := 1; c < f.order; c++ {
+			candidate := arithpoly.Polynomial{f.baseField.Element(c)}
+			if f.isPrimitivePoly(candidate) {
+				return candidate, nil
+			}
 		}
-		return elem, nil
+		return nil, fmt.Errorf("no primitive element found")
 	}
 
 	// Start with α (represented as [0, 1, 0, ..., 0])
@@ -172,7 +299,7 @@ func (f *field) findPrimitiveElement() (arithpoly.Polynomial, error) {
 	}
 
 	// Get the prime p from base field
-	p := int(f.baseField.Elements()[len(f.baseField.Elements())-1].Value()) + 1
+	p := int(f.baseField.Prime())
 
 	// Add more candidates: try all combinations of coefficients for lower degree terms
 	// Only need to try coefficients in [0, p-1]
@@ -195,11 +322,7 @@ func (f *field) findPrimitiveElement() (arithpoly.Polynomial, error) {
 	}
 
 	for _, candidate := range candidates {
-		order, err := f.computeOrder(candidate)
-		if err != nil {
-			continue
-		}
-		if order == targetOrder {
+		if f.isPrimitivePoly(candidate) {
 			return candidate, nil
 		}
 	}
@@ -279,6 +402,26 @@ func (f *field) Elements() []Element {
 	return elements
 }
 
+// MultiplicativeGroup returns the non-zero elements in generator (power) order:
+// [α^0, α^1, ..., α^(p^n-2)]
+//
+// Entry i is α^i, so each entry is the previous one times the primitive element
+// and the slice wraps around: α^(p^n-1) = α^0. Elements() lists the same
+// elements in the same order but prefixed with zero, so Elements()[i+1] is
+// MultiplicativeGroup()[i]; indexing this slice directly by the exponent avoids
+// the off-by-one.
+func (f *field) MultiplicativeGroup() []Element {
+	group := make([]Element, f.order-1)
+	for i := range group {
+		group[i] = &element{
+			field:  f,
+			power:  i,
+			coeffs: f.powerToPoly[i],
+		}
+	}
+	return group
+}
+
 func (f *field) Element(value int) Element {
 	// Use modulo arithmetic to handle values outside [0, p^n-1]
 	value = ((value % f.order) + f.order) % f.order
@@ -314,6 +457,21 @@ func (f *field) Primitive() Element {
 	return f.primitiveElement
 }
 
+// Log returns the discrete logarithm of e to the base α, read from the power tables
+// Zero is not a power of α and has no logarithm.
+func (f *field) Log(e Element) (int, error) {
+	el := f.oneElement.assertSameField(e)
+	if el.IsZero() {
+		return 0, fmt.Errorf("zero has no discrete logarithm")
+	}
+	return el.power, nil
+}
+
+// Exp returns α^power; Exp(Log(e)) = e for every non-zero e
+func (f *field) Exp(power int) Element {
+	return f.fromPower(power)
+}
+
 func (f *field) Add(e1, e2 Element) Element {
 	return e1.Add(e2)
 }
@@ -334,6 +492,204 @@ func (f *field) Order() int {
 	return f.order
 }
 
+// BasePrime returns the prime p of GF(p^n), the order of the base field
+func (f *field) BasePrime() int16 {
+	return f.baseField.Prime()
+}
+
+// Degree returns n, the degree of the extension GF(p^n) over GF(p)
+func (f *field) Degree() int {
+	return f.degree
+}
+
+// IrreduciblePolynomial returns the coefficients [a0, a1, ..., an] of the
+// irreducible polynomial the field was constructed with, as passed to NewField
+func (f *field) IrreduciblePolynomial() []int {
+	coeffs := make([]int, len(f.irreducible))
+	for i, c := range f.irreducible {
+		coeffs[i] = int(c.Value())
+	}
+	return coeffs
+}
+
+// Pow raises e to the power k, see PowBig
+func (f *field) Pow(e Element, k int) Element {
+	return f.PowBig(e, big.NewInt(int64(k)))
+}
+
+// PowBig raises e to the power k, where k may be negative or arbitrarily large
+//
+// Non-zero elements form a cyclic group of order p^n - 1, so with e = α^i:
+//
+//	e^k = α^(i·k mod (p^n - 1))
+//
+// k is reduced modulo p^n - 1 before multiplying, and the product i·k is taken in
+// big arithmetic, so exponents such as p^(n-1) or (p^n - 1)/(p - 1) used by the
+// Frobenius map, trace and norm never overflow, however large the field.
+// Zero follows Element.Pow: 0^k = 0 for k > 0, and zero to a non-positive
+// power, 0^0 included, panics.
+func (f *field) PowBig(e Element, k *big.Int) Element {
+	el := f.oneElement.assertSameField(e)
+
+	if el.IsZero() {
+		if k.Sign() <= 0 {
+			panic(fmt.Sprintf("zero cannot be raised to the non-positive power %s", k))
+		}
+		return f.zeroElement
+	}
+
+	groupOrder := big.NewInt(int64(f.order - 1))
+	power := new(big.Int).Mod(k, groupOrder)
+	power.Mul(power, big.NewInt(int64(el.power)))
+	power.Mod(power, groupOrder)
+
+	exponent := int(power.Int64())
+	return &element{
+		field:  f,
+		power:  exponent,
+		coeffs: f.powerToPoly[exponent],
+	}
+}
+
+// InverseFermat returns e^-1 computed as e^(p^n - 2), without the power tables
+//
+// Every non-zero element satisfies e^(p^n - 1) = 1 (Fermat's little theorem for
+// finite fields, i.e. Lagrange's theorem for the multiplicative group), so
+//
+//	e · e^(p^n - 2) = 1
+//
+// The power is computed by square-and-multiply on e's polynomial representation,
+// reducing modulo the irreducible polynomial after each step; only the final
+// result is looked up to turn it into an element. This makes it an independent
+// cross-check of Div, which subtracts exponents in the power tables. It panics
+// for zero, which has no inverse.
+func (f *field) InverseFermat(e Element) Element {
+	el := f.oneElement.assertSameField(e)
+	if el.IsZero() {
+		panic("zero has no multiplicative inverse")
+	}
+	return f.fromCoeffs(f.powPoly(el.coeffs, f.order-2))
+}
+
+// Frobenius returns e^p
+//
+// In characteristic p, (a + b)^p = a^p + b^p since the binomial coefficients in
+// between are multiples of p, so x -> x^p is a field automorphism. It fixes
+// exactly the prime subfield GF(p), and applying it n times gives the identity.
+func (f *field) Frobenius(e Element) Element {
+	return f.FrobeniusPow(e, 1)
+}
+
+// FrobeniusPow returns e^(p^k), the Frobenius map applied k times
+//
+// The Frobenius map has order n, so k is reduced modulo n first; a negative k
+// applies the inverse automorphism. After the reduction p^k < p^n, so the
+// exponent always fits in an int.
+func (f *field) FrobeniusPow(e Element, k int) Element {
+	el := f.oneElement.assertSameField(e)
+	if el.IsZero() {
+		return el
+	}
+
+	k = ((k % f.degree) + f.degree) % f.degree
+	exponent := 1
+	for i := 0; i < k; i++ {
+		exponent *= int(f.BasePrime())
+	}
+	return el.Pow(exponent)
+}
+
+// Trace returns Tr(e) = e + e^p + e^(p^2) + ... + e^(p^(n-1))
+//
+// The trace is the sum of e's conjugates under the Frobenius map x -> x^p. It is
+// fixed by that map, so the result always lies in the prime subfield GF(p) and is
+// returned as an element of the base field. Tr is GF(p)-linear:
+// Tr(c·a + b) = c·Tr(a) + Tr(b) for c in GF(p).
+func (f *field) Trace(e Element) gf.Element {
+	trace, conjugate := f.zeroElement.Add(e), e
+	for i := 1; i < f.degree; i++ {
+		conjugate = f.Frobenius(conjugate)
+		trace = trace.Add(conjugate)
+	}
+	return f.toBaseField(trace)
+}
+
+// Norm returns N(e) = e · e^p · ... · e^(p^(n-1)) = e^((p^n - 1)/(p - 1))
+//
+// Like the trace, the norm lies in the prime subfield GF(p) and is returned as an
+// element of the base field. It is multiplicative, N(a·b) = N(a)·N(b), and maps a
+// primitive element to a generator of GF(p)*.
+func (f *field) Norm(e Element) gf.Element {
+	p := int64(f.BasePrime())
+	exponent := big.NewInt(int64(f.order - 1))
+	exponent.Quo(exponent, big.NewInt(p-1))
+	return f.toBaseField(f.PowBig(e, exponent))
+}
+
+// MinimalPolynomial returns the minimal polynomial of e over GF(p)
+//
+// The conjugates e, e^p, e^(p^2), ... repeat after d steps, where d divides n
+// and GF(p^d) is the smallest subfield containing e. The minimal polynomial is
+//
+//	m(x) = (x - e)(x - e^p)···(x - e^(p^(d-1)))
+//
+// Its coefficients are symmetric functions of the conjugates, so the Frobenius
+// map permutes the factors and fixes every coefficient; they lie in GF(p) even
+// though the product is computed in GF(p^n). The result is monic of degree d,
+// with coefficients [m0, m1, ..., md] from lowest to highest degree. For example
+// the minimal polynomial of 1 is x - 1, and that of α = x is the irreducible
+// polynomial when it is primitive.
+func (f *field) MinimalPolynomial(e Element) arithpoly.Polynomial {
+	el := f.oneElement.assertSameField(e)
+
+	conjugates := []Element{el}
+	for next := f.Frobenius(el); !next.Equal(el); next = f.Frobenius(next) {
+		conjugates = append(conjugates, next)
+	}
+
+	// Multiply out the linear factors, product[i] being the coefficient of x^i
+	product := []Element{f.oneElement}
+	for _, c := range conjugates {
+		next := make([]Element, len(product)+1)
+		for i := range next {
+			next[i] = f.zeroElement
+		}
+		for i, a := range product {
+			next[i+1] = next[i+1].Add(a)
+			next[i] = next[i].Sub(a.Mul(c))
+		}
+		product = next
+	}
+
+	minimal := make(arithpoly.Polynomial, len(product))
+	for i, c := range product {
+		minimal[i] = f.toBaseField(c)
+	}
+	return minimal
+}
+
+// fromBaseField embeds an element c of GF(p) into GF(p^n) as the constant polynomial c
+func (f *field) fromBaseField(c gf.Element) *element {
+	coeffs := make([]gf.Element, f.degree)
+	coeffs[0] = f.baseField.Element(int(c.Value()))
+	for i := 1; i < f.degree; i++ {
+		coeffs[i] = f.baseField.Element(0)
+	}
+	return f.fromCoeffs(coeffs)
+}
+
+// toBaseField returns the constant coefficient of an element of the prime subfield
+// It panics if any higher coefficient is non-zero, as that means e is not in GF(p).
+func (f *field) toBaseField(e Element) gf.Element {
+	el := f.oneElement.assertSameField(e)
+	for i := 1; i < len(el.coeffs); i++ {
+		if el.coeffs[i].Value() != 0 {
+			panic(fmt.Sprintf("%s is not in the prime subfield GF(%d)", el, f.BasePrime()))
+		}
+	}
+	return el.coeffs[0]
+}
+
 // element implements the Element interface
 type element struct {
 	field  *field
@@ -347,6 +703,25 @@ func (e *element) Coefficients() []gf.Element {
 	return result
 }
 
+// Clone returns a deep copy of the element with its own coefficient slice
+//
+// Elements are immutable and usually share their coefficients with the field's
+// lookup tables, so algorithms that want to modify coefficients in place, or hand
+// an element to code that might, should work on a clone. The clone belongs to the
+// same field and compares equal to the original.
+func (e *element) Clone() Element {
+	var coeffs []gf.Element
+	if e.coeffs != nil {
+		coeffs = make([]gf.Element, len(e.coeffs))
+		copy(coeffs, e.coeffs)
+	}
+	return &element{
+		field:  e.field,
+		power:  e.power,
+		coeffs: coeffs,
+	}
+}
+
 func (e *element) Power() int {
 	return e.power
 }
@@ -369,6 +744,13 @@ func (e *element) String() string {
 	return coeffStrs
 }
 
+// Equal compares the exponents of α, which identify an element uniquely within its
+// field; zero has power -1, so any two zero elements are equal
+func (e *element) Equal(other Element) bool {
+	o := e.assertSameField(other)
+	return e.power == o.power
+}
+
 func (e *element) assertSameField(other Element) *element {
 	otherElem, ok := other.(*element)
 	if !ok {
@@ -380,22 +762,125 @@ func (e *element) assertSameField(other Element) *element {
 	return otherElem
 }
 
+// Inverse returns the multiplicative inverse from the power tables:
+// (α^k)^-1 = α^(p^n-1-k), since α^(p^n-1) = 1
+func (e *element) Inverse() Element {
+	if e.IsZero() {
+		panic("zero has no multiplicative inverse")
+	}
+	return e.field.fromPower(e.field.order - 1 - e.power)
+}
+
+// Pow computes e^exp from the power tables: (α^k)^exp = α^(k·exp mod (p^n-1))
+//
+// The exponent is reduced modulo p^n-1 before multiplying so the product cannot
+// overflow, and a negative exponent reduces to the equivalent positive one,
+// which is the same as raising the inverse. Any non-zero element to the power 0
+// is one. Zero to a non-positive power panics, as in Field.Pow and PowBig: 0^-k
+// has no inverse to raise, and 0^0 is left undefined rather than silently being one.
+func (e *element) Pow(exp int) Element {
+	if e.IsZero() {
+		if exp <= 0 {
+			panic(fmt.Sprintf("zero cannot be raised to the non-positive power %d", exp))
+		}
+		return e.field.zeroElement
+	}
+
+	groupOrder := e.field.order - 1
+	exp %= groupOrder
+	return e.field.fromPower(e.power * exp)
+}
+
+// Negate returns the additive inverse, computed coefficient-wise in the base field
+func (e *element) Negate() Element {
+	if e.IsZero() {
+		return e
+	}
+
+	zero := e.field.baseField.Element(0)
+	coeffs := make([]gf.Element, len(e.coeffs))
+	for i, c := range e.coeffs {
+		coeffs[i] = e.field.baseField.Sub(zero, c)
+	}
+	return e.field.fromCoeffs(coeffs)
+}
+
+// fromCoeffs looks up the element with the given polynomial representation
+func (f *field) fromCoeffs(coeffs []gf.Element) *element {
+	power, ok := f.polyToPower[polyKey(coeffs)]
+	if !ok {
+		return f.zeroElement
+	}
+	return &element{
+		field:  f,
+		power:  power,
+		coeffs: f.powerToPoly[power],
+	}
+}
+
+// fromPower returns α^power, reducing the exponent modulo the order of the
+// multiplicative group so that negative and overflowing powers wrap around
+func (f *field) fromPower(power int) *element {
+	groupOrder := f.order - 1
+	power = ((power % groupOrder) + groupOrder) % groupOrder
+	return &element{
+		field:  f,
+		power:  power,
+		coeffs: f.powerToPoly[power],
+	}
+}
+
+// Add adds the polynomial representations coefficient-wise in the base field.
+// No reduction is needed since the degree of the sum never exceeds n-1.
 func (e *element) Add(other Element) Element {
-	//o := e.assertSameField(other)
-	panic("not implemented")
+	o := e.assertSameField(other)
+	if e.IsZero() {
+		return o
+	}
+	if o.IsZero() {
+		return e
+	}
+
+	coeffs := make([]gf.Element, len(e.coeffs))
+	for i := range coeffs {
+		coeffs[i] = e.coeffs[i].Add(o.coeffs[i])
+	}
+	return e.field.fromCoeffs(coeffs)
 }
 
+// Sub subtracts the polynomial representations coefficient-wise in the base field
 func (e *element) Sub(other Element) Element {
-	//o := e.assertSameField(other)
-	panic("not implemented")
+	o := e.assertSameField(other)
+	if o.IsZero() {
+		return e
+	}
+
+	coeffs := make([]gf.Element, len(e.coeffs))
+	for i := range coeffs {
+		coeffs[i] = e.coeffs[i].Sub(o.coeffs[i])
+	}
+	return e.field.fromCoeffs(coeffs)
 }
 
+// Mul multiplies in the power representation: α^i · α^j = α^((i+j) mod (p^n-1)).
+// Zero has no power, so any product with zero is zero.
 func (e *element) Mul(other Element) Element {
-	//o := e.assertSameField(other)
-	panic("not implemented")
+	o := e.assertSameField(other)
+	if e.IsZero() || o.IsZero() {
+		return e.field.zeroElement
+	}
+	return e.field.fromPower(e.power + o.power)
 }
 
+// Div divides in the power representation: α^i / α^j = α^((i-j) mod (p^n-1)).
+// It panics on division by zero.
 func (e *element) Div(other Element) Element {
-	//o := e.assertSameField(other)
-	panic("not implemented")
+	o := e.assertSameField(other)
+	if o.IsZero() {
+		panic("division by zero")
+	}
+	if e.IsZero() {
+		return e.field.zeroElement
+	}
+	return e.field.fromPower(e.power - o.power)
 }
@@ -0,0 +1,808 @@
+package gfpn
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/jalphad/abstract_algebra/exercises/2-arithpoly"
+)
+
+// newTestField creates a GF(p^n) field or fails the test
+func newTestField(t testing.TB, p int16, n int, irreducible []int) Field {
+	t.Helper()
+	field, err := NewField(p, n, irreducible)
+	if err != nil {
+		t.Fatalf("Failed to create GF(%d^%d): %v", p, n, err)
+	}
+	return field
+}
+
+// qrField creates GF(256) with the QR code polynomial x^8 + x^4 + x^3 + x^2 + 1
+func qrField(t testing.TB) Field {
+	return newTestField(t, 2, 8, []int{1, 0, 1, 1, 1, 0, 0, 0, 1})
+}
+
+func TestNegate(t *testing.T) {
+	// GF(9) = GF(3)[x]/(x^2 + 1)
+	gf9 := newTestField(t, 3, 2, []int{1, 0, 1})
+	for _, a := range gf9.Elements() {
+		if sum := a.Add(a.Negate()); !sum.IsZero() {
+			t.Errorf("GF(9): %s + (-%s) = %s, want 0", a, a, sum)
+		}
+	}
+
+	// -1 = 2 in GF(3), so negation is not the identity in GF(9)
+	if minusOne := gf9.One().Negate(); minusOne.Equal(gf9.One()) {
+		t.Errorf("GF(9): -1 should differ from 1")
+	}
+
+	// In characteristic 2, negation is the identity
+	gf256 := qrField(t)
+	for _, a := range gf256.Elements() {
+		if neg := a.Negate(); !neg.Equal(a) {
+			t.Errorf("GF(256): -%s = %s, want %s", a, neg, a)
+		}
+	}
+}
+
+func TestFieldIntrospection(t *testing.T) {
+	f := qrField(t)
+
+	// 0x11D = x^8 + x^4 + x^3 + x^2 + 1
+	want := []int{1, 0, 1, 1, 1, 0, 0, 0, 1}
+	got := f.IrreduciblePolynomial()
+	if len(got) != len(want) {
+		t.Fatalf("IrreduciblePolynomial() = %v, want %v", got, want)
+	}
+	packed := 0
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("IrreduciblePolynomial() = %v, want %v", got, want)
+		}
+		packed |= got[i] << i
+	}
+	if packed != 0x11D {
+		t.Errorf("packed polynomial = %#x, want 0x11d", packed)
+	}
+
+	// The result is a copy
+	got[0] = 0
+	if f.IrreduciblePolynomial()[0] != 1 {
+		t.Error("modifying the returned slice changed the field")
+	}
+
+	if f.BasePrime() != 2 || f.Degree() != 8 {
+		t.Errorf("BasePrime(), Degree() = %d, %d, want 2, 8", f.BasePrime(), f.Degree())
+	}
+
+	gf9 := newTestField(t, 3, 2, []int{2, 2, 1})
+	if gf9.BasePrime() != 3 || gf9.Degree() != 2 {
+		t.Errorf("GF(9): BasePrime(), Degree() = %d, %d, want 3, 2", gf9.BasePrime(), gf9.Degree())
+	}
+}
+
+func TestMultiplicativeGroup(t *testing.T) {
+	fields := map[string]Field{
+		"GF(9)":   newTestField(t, 3, 2, []int{2, 2, 1}),
+		"GF(256)": qrField(t),
+	}
+
+	for name, f := range fields {
+		group := f.MultiplicativeGroup()
+		if len(group) != f.Order()-1 {
+			t.Fatalf("%s: len = %d, want %d", name, len(group), f.Order()-1)
+		}
+
+		if !group[0].Equal(f.One()) {
+			t.Errorf("%s: group[0] = %s, want 1", name, group[0])
+		}
+		for i := 1; i < len(group); i++ {
+			if want := f.Mul(group[i-1], f.Primitive()); !group[i].Equal(want) {
+				t.Errorf("%s: group[%d] = %s, want group[%d]·α = %s", name, i, group[i], i-1, want)
+			}
+			if group[i].IsZero() {
+				t.Errorf("%s: group[%d] is zero", name, i)
+			}
+		}
+
+		// The cycle closes: α^(order-2) · α = α^0
+		if last := f.Mul(group[len(group)-1], f.Primitive()); !last.Equal(f.One()) {
+			t.Errorf("%s: α^(order-1) = %s, want 1", name, last)
+		}
+	}
+}
+
+func TestPow(t *testing.T) {
+	f := qrField(t)
+	alpha := f.Primitive()
+
+	tests := []struct {
+		name string
+		k    int
+		want Element
+	}{
+		{name: "α^0", k: 0, want: f.One()},
+		{name: "α^1", k: 1, want: alpha},
+		{name: "α^255 wraps to 1", k: 255, want: f.One()},
+		{name: "α^-1 is α^254", k: -1, want: f.Element(255)},
+		{name: "α^1000 is α^235", k: 1000, want: f.Element(236)},
+	}
+	for _, tt := range tests {
+		if got := f.Pow(alpha, tt.k); !got.Equal(tt.want) {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	// 2^100 ≡ 2^4 (mod 255), since 2^8 = 256 ≡ 1
+	k := new(big.Int).Lsh(big.NewInt(1), 100)
+	if got, want := f.PowBig(alpha, k), f.Pow(alpha, 16); !got.Equal(want) {
+		t.Errorf("α^(2^100) = %s, want α^16 = %s", got, want)
+	}
+
+	if got := f.Pow(f.Zero(), 5); !got.IsZero() {
+		t.Errorf("0^5 = %s, want 0", got)
+	}
+}
+
+func TestPowZeroToZero(t *testing.T) {
+	f, err := NewField(2, 4, []int{1, 1, 0, 0, 1})
+	if err != nil {
+		t.Fatalf("NewField() error = %v", err)
+	}
+
+	// Field.Pow, PowBig and Element.Pow agree: 0^0 and negative powers of zero panic
+	cases := map[string]func(){
+		"Field.Pow(0, 0)":    func() { f.Pow(f.Zero(), 0) },
+		"Field.Pow(0, -1)":   func() { f.Pow(f.Zero(), -1) },
+		"PowBig(0, 0)":       func() { f.PowBig(f.Zero(), big.NewInt(0)) },
+		"PowBig(0, -3)":      func() { f.PowBig(f.Zero(), big.NewInt(-3)) },
+		"Element.Pow(0, 0)":  func() { f.Zero().Pow(0) },
+		"Element.Pow(0, -1)": func() { f.Zero().Pow(-1) },
+	}
+	for name, call := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			call()
+		}()
+	}
+
+	// Zero to a positive power and non-zero elements to the power 0 are fine
+	if !f.Pow(f.Zero(), 3).IsZero() || !f.PowBig(f.Zero(), big.NewInt(3)).IsZero() || !f.Zero().Pow(3).IsZero() {
+		t.Error("0^3 is not zero")
+	}
+	if x := f.Element(7); !f.Pow(x, 0).Equal(f.One()) || !x.Pow(0).Equal(f.One()) {
+		t.Error("x^0 is not one")
+	}
+}
+
+func TestTraceAndNorm(t *testing.T) {
+	// GF(3^10) = GF(3)[x]/(x^10 + x^3 + x + 2) has 59049 elements. The norm exponent
+	// (3^10 - 1)/2 = 29524 times an element's power overflows int32, and the
+	// Frobenius exponents 3^20 and 3^40 overflow int32 and int64 respectively.
+	f := newTestField(t, 3, 10, []int{2, 1, 0, 1, 0, 0, 0, 0, 0, 0, 1})
+	alpha := f.Primitive()
+	three := big.NewInt(3)
+
+	samples := []Element{alpha, f.Pow(alpha, 12345), f.Pow(alpha, 59000), f.Element(2000)}
+	for _, a := range samples {
+		// The Frobenius map x -> x^3 has order 10, so x^(3^20) = x^(3^40) = x
+		for _, n := range []int64{20, 40} {
+			k := new(big.Int).Exp(three, big.NewInt(n), nil)
+			if got := f.PowBig(a, k); !got.Equal(a) {
+				t.Errorf("%s^(3^%d) = %s, want %s", a, n, got, a)
+			}
+		}
+
+		// The norm is the product of the conjugates a^(3^i)
+		product := f.One()
+		exponent := big.NewInt(1)
+		for i := 0; i < 10; i++ {
+			product = f.Mul(product, f.PowBig(a, exponent))
+			exponent.Mul(exponent, three)
+		}
+		// The product is fixed by x -> x^3, so it is the constant N(a) in GF(3)
+		norm := f.Norm(a)
+		if cubed := f.Pow(product, 3); !cubed.Equal(product) {
+			t.Errorf("product of conjugates of %s = %s is not in GF(3)", a, product)
+		}
+		if want := "000000000" + fmt.Sprint(norm.Value()); product.String() != want {
+			t.Errorf("N(%s) = %d, want product of conjugates %s", a, norm.Value(), product)
+		}
+
+		trace := f.Trace(a)
+		if got := f.Trace(f.Pow(a, 3)); got.Value() != trace.Value() {
+			t.Errorf("Tr(%s^3) = %d, want Tr(%s) = %d", a, got.Value(), a, trace.Value())
+		}
+	}
+
+	// A primitive element's norm generates GF(3)* = {1, 2}, so it is 2 = -1
+	if norm := f.Norm(alpha); norm.Value() != 2 {
+		t.Errorf("N(α) = %d, want -1 = 2", norm.Value())
+	}
+}
+
+func TestTraceLinearNormMultiplicative(t *testing.T) {
+	f := qrField(t)
+	elements := f.Elements()
+
+	// Tr maps GF(256) onto GF(2), half of the elements to each value
+	ones := 0
+	for _, a := range elements {
+		ones += int(f.Trace(a).Value())
+	}
+	if ones != 128 {
+		t.Errorf("Tr is 1 on %d elements, want 128", ones)
+	}
+
+	for i, a := range elements {
+		b := elements[(37*i+11)%len(elements)]
+
+		// GF(2)-linearity: the only scalars are 0 and 1, so this is additivity
+		if got, want := f.Trace(f.Add(a, b)), f.Trace(a).Add(f.Trace(b)); got.Value() != want.Value() {
+			t.Errorf("Tr(%s + %s) = %d, want %d", a, b, got.Value(), want.Value())
+		}
+		if got, want := f.Norm(f.Mul(a, b)), f.Norm(a).Mul(f.Norm(b)); got.Value() != want.Value() {
+			t.Errorf("N(%s · %s) = %d, want %d", a, b, got.Value(), want.Value())
+		}
+	}
+
+	// Over GF(3^2) the scalars include 2, so check Tr(c·a + b) = c·Tr(a) + Tr(b) fully
+	gf9 := newTestField(t, 3, 2, []int{2, 2, 1})
+	for _, c := range []int{0, 1, 2} {
+		scalar := gf9.Zero()
+		for k := 0; k < c; k++ {
+			scalar = gf9.Add(scalar, gf9.One())
+		}
+		for _, a := range gf9.Elements() {
+			for _, b := range gf9.Elements() {
+				got := gf9.Trace(gf9.Add(gf9.Mul(scalar, a), b))
+				want := gf9.Trace(a).Mul(got.Field().Element(c)).Add(gf9.Trace(b))
+				if got.Value() != want.Value() {
+					t.Errorf("GF(9): Tr(%d·%s + %s) = %d, want %d", c, a, b, got.Value(), want.Value())
+				}
+			}
+		}
+	}
+}
+
+func TestIsPrimitive(t *testing.T) {
+	// GF(16) = GF(2)[x]/(x^4 + x + 1); 15 = 3·5, so φ(15) = 8 elements are primitive
+	f := newTestField(t, 2, 4, []int{1, 1, 0, 0, 1}).(*field)
+
+	primitive := 0
+	for _, e := range f.Elements() {
+		got := f.IsPrimitive(e)
+		want := false
+		if !e.IsZero() {
+			order, err := f.computeOrder(e.(*element).coeffs)
+			if err != nil {
+				t.Fatalf("computeOrder(%s): %v", e, err)
+			}
+			want = order == f.Order()-1
+		}
+		if got != want {
+			t.Errorf("IsPrimitive(%s) = %v, want %v", e, got, want)
+		}
+		if got {
+			primitive++
+		}
+	}
+	if primitive != 8 {
+		t.Errorf("found %d primitive elements, want 8", primitive)
+	}
+
+	if !f.IsPrimitive(f.Primitive()) {
+		t.Errorf("IsPrimitive(α) = false")
+	}
+}
+
+func TestPrimeFactors(t *testing.T) {
+	tests := map[int][]int{
+		1:     nil,
+		15:    {3, 5},
+		255:   {3, 5, 17},
+		256:   {2},
+		59048: {2, 11, 61},
+		65535: {3, 5, 17, 257},
+	}
+	for n, want := range tests {
+		got := primeFactors(n)
+		if len(got) != len(want) {
+			t.Errorf("primeFactors(%d) = %v, want %v", n, got, want)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("primeFactors(%d) = %v, want %v", n, got, want)
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkNewFieldGF256(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewField(2, 8, []int{1, 0, 1, 1, 1, 0, 0, 0, 1}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	f := qrField(t)
+
+	for _, e := range []Element{f.Zero(), f.One(), f.Primitive(), f.Element(200)} {
+		clone := e.Clone()
+		original, copied := e.(*element), clone.(*element)
+
+		// The clone equals the original
+		if copied.field != original.field || copied.power != original.power || !clone.Equal(e) {
+			t.Errorf("Clone(%s) = %s (power %d), want power %d", e, clone, copied.power, original.power)
+		}
+
+		// but its coefficients live in a distinct backing array
+		if len(original.coeffs) > 0 && &copied.coeffs[0] == &original.coeffs[0] {
+			t.Errorf("Clone(%s) shares its coefficient slice", e)
+		}
+		if len(copied.coeffs) > 0 {
+			before := original.coeffs[0].Value()
+			copied.coeffs[0] = f.(*field).baseField.Element(1 - int(before))
+			if original.coeffs[0].Value() != before {
+				t.Errorf("modifying the clone of %s changed the original", e)
+			}
+		}
+	}
+}
+
+func TestInverseFermat(t *testing.T) {
+	for name, f := range map[string]Field{
+		"GF(9)":   newTestField(t, 3, 2, []int{2, 2, 1}),
+		"GF(256)": qrField(t),
+	} {
+		for _, a := range f.MultiplicativeGroup() {
+			inverse := f.InverseFermat(a)
+			if want := f.Div(f.One(), a); !inverse.Equal(want) {
+				t.Errorf("%s: InverseFermat(%s) = %s, want 1/%s = %s", name, a, inverse, a, want)
+			}
+			if product := f.Mul(a, inverse); !product.Equal(f.One()) {
+				t.Errorf("%s: %s · InverseFermat(%s) = %s, want 1", name, a, a, product)
+			}
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("InverseFermat(0) did not panic")
+		}
+	}()
+	qrField(t).InverseFermat(qrField(t).Zero())
+}
+
+func TestPrimeFieldPrimitive(t *testing.T) {
+	// Degree 1 fields GF(p) = GF(p)[x]/(x + 1); 2 is not a primitive root mod 7 or 17
+	for _, p := range []int16{2, 3, 5, 7, 17} {
+		f := newTestField(t, p, 1, []int{1, 1})
+		if !f.IsPrimitive(f.Primitive()) {
+			t.Errorf("GF(%d): α = %s is not primitive", p, f.Primitive())
+		}
+		if group := f.MultiplicativeGroup(); len(group) != int(p)-1 {
+			t.Errorf("GF(%d): multiplicative group has %d elements, want %d", p, len(group), p-1)
+		}
+	}
+
+	if got := newTestField(t, 7, 1, []int{1, 1}).Primitive().String(); got != "3" {
+		t.Errorf("GF(7): α = %s, want the smallest primitive root 3", got)
+	}
+}
+
+func TestElementArithmetic(t *testing.T) {
+	gf9 := newTestField(t, 3, 2, []int{2, 2, 1})
+	elements := gf9.Elements()
+	zero, one := gf9.Zero(), gf9.One()
+
+	for _, a := range elements {
+		if got := gf9.Add(a, zero); !got.Equal(a) {
+			t.Errorf("GF(9): %s + 0 = %s", a, got)
+		}
+		if got := gf9.Sub(a, a); !got.IsZero() {
+			t.Errorf("GF(9): %s - %s = %s, want 0", a, a, got)
+		}
+		if got := gf9.Mul(a, one); !got.Equal(a) {
+			t.Errorf("GF(9): %s · 1 = %s", a, got)
+		}
+		if got := gf9.Mul(a, zero); !got.IsZero() {
+			t.Errorf("GF(9): %s · 0 = %s, want 0", a, got)
+		}
+		if got := gf9.Add(a, a.Negate()); !got.IsZero() {
+			t.Errorf("GF(9): %s + (-%s) = %s, want 0", a, a, got)
+		}
+
+		for _, b := range elements {
+			sum := gf9.Add(a, b)
+			if got := gf9.Sub(sum, b); !got.Equal(a) {
+				t.Errorf("GF(9): (%s + %s) - %s = %s", a, b, b, got)
+			}
+			if other := gf9.Add(b, a); !other.Equal(sum) {
+				t.Errorf("GF(9): %s + %s = %s but %s + %s = %s", a, b, sum, b, a, other)
+			}
+			if !b.IsZero() {
+				if got := gf9.Mul(gf9.Div(a, b), b); !got.Equal(a) {
+					t.Errorf("GF(9): (%s / %s) · %s = %s", a, b, b, got)
+				}
+			}
+
+			// Distributivity ties the coefficient-wise sum to the power-table product
+			for _, c := range elements {
+				left := gf9.Mul(a, gf9.Add(b, c))
+				right := gf9.Add(gf9.Mul(a, b), gf9.Mul(a, c))
+				if !left.Equal(right) {
+					t.Errorf("GF(9): %s(%s + %s) = %s, want %s", a, b, c, left, right)
+				}
+			}
+		}
+	}
+
+	// In the QR field α^8 = α^4 + α^3 + α^2 + 1, and α^254 · α = 1
+	gf256 := qrField(t)
+	alpha := gf256.Primitive()
+	if got := gf256.Pow(alpha, 8); got.String() != "00011101" {
+		t.Errorf("GF(256): α^8 = %s, want 00011101", got)
+	}
+	alpha4 := gf256.Pow(alpha, 4)
+	sum := gf256.Add(gf256.Add(alpha4, gf256.Pow(alpha, 3)), gf256.Add(gf256.Pow(alpha, 2), gf256.One()))
+	if got := gf256.Mul(alpha4, alpha4); !got.Equal(sum) {
+		t.Errorf("GF(256): α^4 · α^4 = %s, want %s", got, sum)
+	}
+	if got := gf256.Mul(gf256.Pow(alpha, 254), alpha); !got.Equal(gf256.One()) {
+		t.Errorf("GF(256): α^254 · α = %s, want 1", got)
+	}
+	if got := gf256.Div(gf256.One(), alpha); !got.Equal(gf256.Pow(alpha, 254)) {
+		t.Errorf("GF(256): 1 / α = %s, want α^254", got)
+	}
+	if got := gf256.Div(gf256.Zero(), alpha); !got.IsZero() {
+		t.Errorf("GF(256): 0 / α = %s, want 0", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("division by zero did not panic")
+		}
+	}()
+	gf256.Div(alpha, gf256.Zero())
+}
+
+func TestInverse(t *testing.T) {
+	for name, f := range map[string]Field{
+		"GF(27)":  newTestField(t, 3, 3, []int{1, 2, 0, 1}),
+		"GF(256)": qrField(t),
+	} {
+		for _, x := range f.MultiplicativeGroup() {
+			inverse := x.Inverse()
+			if product := x.Mul(inverse); !product.Equal(f.One()) {
+				t.Errorf("%s: %s · %s^-1 = %s, want 1", name, x, x, product)
+			}
+			if want := f.InverseFermat(x); !inverse.Equal(want) {
+				t.Errorf("%s: %s^-1 = %s, want %s", name, x, inverse, want)
+			}
+		}
+		if got := f.One().Inverse(); !got.Equal(f.One()) {
+			t.Errorf("%s: 1^-1 = %s, want 1", name, got)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Inverse of zero did not panic")
+		}
+	}()
+	qrField(t).Zero().Inverse()
+}
+
+func TestElementPow(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for name, f := range map[string]Field{
+		"GF(27)":  newTestField(t, 3, 3, []int{1, 2, 0, 1}),
+		"GF(256)": qrField(t),
+	} {
+		one := f.One()
+		alpha := f.Primitive()
+		if got := alpha.Pow(f.Order() - 1); !got.Equal(one) {
+			t.Errorf("%s: α^(order-1) = %s, want 1", name, got)
+		}
+
+		for _, x := range f.MultiplicativeGroup() {
+			if got := x.Pow(0); !got.Equal(one) {
+				t.Errorf("%s: %s^0 = %s, want 1", name, x, got)
+			}
+			if got := x.Pow(-1); !got.Equal(x.Inverse()) {
+				t.Errorf("%s: %s^-1 = %s, want %s", name, x, got, x.Inverse())
+			}
+
+			exp := rng.Intn(3 * f.Order())
+			want := one
+			for k := 0; k < exp; k++ {
+				want = want.Mul(x)
+			}
+			if got := x.Pow(exp); !got.Equal(want) {
+				t.Errorf("%s: %s^%d = %s, want %s", name, x, exp, got, want)
+			}
+			if got := x.Pow(-exp); !got.Equal(want.Inverse()) {
+				t.Errorf("%s: %s^-%d = %s, want %s", name, x, exp, got, want.Inverse())
+			}
+			if got, want := x.Pow(exp), f.Pow(x, exp); !got.Equal(want) {
+				t.Errorf("%s: %s.Pow(%d) = %s, but Field.Pow gives %s", name, x, exp, got, want)
+			}
+		}
+
+		if got := f.Zero().Pow(3); !got.IsZero() {
+			t.Errorf("%s: 0^3 = %s, want 0", name, got)
+		}
+		for _, exp := range []int{0, -1} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%s: 0^%d did not panic", name, exp)
+					}
+				}()
+				f.Zero().Pow(exp)
+			}()
+		}
+	}
+}
+
+func TestNewFieldAuto(t *testing.T) {
+	tests := []struct {
+		p           int16
+		n           int
+		irreducible []int
+	}{
+		{2, 8, []int{1, 0, 1, 1, 1, 0, 0, 0, 1}}, // the QR code polynomial
+		{3, 3, nil},
+		{3, 4, nil},
+		{5, 3, nil},
+		{7, 1, nil},
+	}
+
+	for _, tt := range tests {
+		f, err := NewFieldAuto(tt.p, tt.n)
+		if err != nil {
+			t.Errorf("NewFieldAuto(%d, %d) returned error: %v", tt.p, tt.n, err)
+			continue
+		}
+		if f.BasePrime() != tt.p || f.Degree() != tt.n {
+			t.Errorf("NewFieldAuto(%d, %d) built GF(%d^%d)", tt.p, tt.n, f.BasePrime(), f.Degree())
+		}
+
+		irreducible := f.IrreduciblePolynomial()
+		if len(irreducible) != tt.n+1 || irreducible[tt.n] != 1 {
+			t.Errorf("GF(%d^%d): irreducible %v is not monic of degree %d", tt.p, tt.n, irreducible, tt.n)
+		}
+		if tt.irreducible != nil && fmt.Sprint(irreducible) != fmt.Sprint(tt.irreducible) {
+			t.Errorf("GF(%d^%d): irreducible = %v, want %v", tt.p, tt.n, irreducible, tt.irreducible)
+		}
+
+		// The root α = x of a primitive polynomial generates the field
+		if tt.n > 1 {
+			x := make([]byte, tt.n)
+			for i := range x {
+				x[i] = '0'
+			}
+			x[tt.n-2] = '1'
+			if got := f.Primitive().String(); got != string(x) {
+				t.Errorf("GF(%d^%d): α = %s, want x = %s", tt.p, tt.n, got, x)
+			}
+		}
+
+		for _, a := range f.MultiplicativeGroup() {
+			if got := f.Mul(a, a.Inverse()); !got.Equal(f.One()) {
+				t.Errorf("GF(%d^%d): %s · %s^-1 = %s, want 1", tt.p, tt.n, a, a, got)
+			}
+		}
+	}
+
+	for _, tt := range []struct {
+		p int16
+		n int
+	}{{4, 2}, {1, 3}, {2, 0}} {
+		if _, err := NewFieldAuto(tt.p, tt.n); err == nil {
+			t.Errorf("NewFieldAuto(%d, %d) succeeded, want error", tt.p, tt.n)
+		}
+	}
+}
+
+func TestFrobenius(t *testing.T) {
+	for name, f := range map[string]Field{
+		"GF(9)":   newTestField(t, 3, 2, []int{2, 2, 1}),
+		"GF(27)":  newTestField(t, 3, 3, []int{1, 2, 0, 1}),
+		"GF(256)": qrField(t),
+	} {
+		p, n := int(f.BasePrime()), f.Degree()
+		elements := f.Elements()
+
+		for _, a := range elements {
+			if got, want := f.Frobenius(a), f.Pow(a, p); !got.Equal(want) {
+				t.Errorf("%s: Frobenius(%s) = %s, want %s^%d = %s", name, a, got, a, p, want)
+			}
+
+			// Applying the map n times is the identity
+			iterated := a
+			for i := 0; i < n; i++ {
+				if got := f.FrobeniusPow(a, i); !got.Equal(iterated) {
+					t.Errorf("%s: FrobeniusPow(%s, %d) = %s, want %s", name, a, i, got, iterated)
+				}
+				iterated = f.Frobenius(iterated)
+			}
+			if !iterated.Equal(a) {
+				t.Errorf("%s: Frobenius^%d(%s) = %s, want %s", name, n, a, iterated, a)
+			}
+			if got := f.FrobeniusPow(f.FrobeniusPow(a, 1), -1); !got.Equal(a) {
+				t.Errorf("%s: FrobeniusPow(Frobenius(%s), -1) = %s, want %s", name, a, got, a)
+			}
+		}
+
+		// The map is additive and multiplicative
+		for i, a := range elements {
+			b := elements[(7*i+3)%len(elements)]
+			if got, want := f.Frobenius(f.Add(a, b)), f.Add(f.Frobenius(a), f.Frobenius(b)); !got.Equal(want) {
+				t.Errorf("%s: Frobenius(%s + %s) = %s, want %s", name, a, b, got, want)
+			}
+			if got, want := f.Frobenius(f.Mul(a, b)), f.Mul(f.Frobenius(a), f.Frobenius(b)); !got.Equal(want) {
+				t.Errorf("%s: Frobenius(%s · %s) = %s, want %s", name, a, b, got, want)
+			}
+		}
+	}
+}
+
+func TestMinimalPolynomial(t *testing.T) {
+	gf16 := newTestField(t, 2, 4, []int{1, 1, 0, 0, 1})
+	if got := polyValues(gf16.MinimalPolynomial(gf16.Primitive())); fmt.Sprint(got) != "[1 1 0 0 1]" {
+		t.Errorf("GF(16): minimal polynomial of α = %v, want x^4 + x + 1", got)
+	}
+	if got := polyValues(gf16.MinimalPolynomial(gf16.One())); fmt.Sprint(got) != "[1 1]" {
+		t.Errorf("GF(16): minimal polynomial of 1 = %v, want x + 1", got)
+	}
+	if got := polyValues(gf16.MinimalPolynomial(gf16.Zero())); fmt.Sprint(got) != "[0 1]" {
+		t.Errorf("GF(16): minimal polynomial of 0 = %v, want x", got)
+	}
+
+	gf9 := newTestField(t, 3, 2, []int{2, 2, 1})
+	if got := polyValues(gf9.MinimalPolynomial(gf9.One())); fmt.Sprint(got) != "[2 1]" {
+		t.Errorf("GF(9): minimal polynomial of 1 = %v, want x - 1", got)
+	}
+
+	for name, f := range map[string]Field{
+		"GF(9)":  gf9,
+		"GF(16)": gf16,
+		"GF(27)": newTestField(t, 3, 3, []int{1, 2, 0, 1}),
+	} {
+		degrees := map[int]int{}
+		for _, a := range f.Elements() {
+			m := f.MinimalPolynomial(a)
+			d := len(m) - 1
+			degrees[d]++
+			if m[d].Value() != 1 {
+				t.Errorf("%s: minimal polynomial %v of %s is not monic", name, polyValues(m), a)
+			}
+			if f.Degree()%d != 0 {
+				t.Errorf("%s: minimal polynomial %v of %s has degree %d not dividing %d",
+					name, polyValues(m), a, d, f.Degree())
+			}
+
+			// m(a) = 0, with the coefficients embedded into GF(p^n)
+			fi := f.(*field)
+			value := f.Zero()
+			for i := d; i >= 0; i-- {
+				value = f.Add(f.Mul(value, a), fi.fromBaseField(m[i]))
+			}
+			if !value.IsZero() {
+				t.Errorf("%s: minimal polynomial %v of %s evaluates to %s at it", name, polyValues(m), a, value)
+			}
+		}
+
+		// The elements of degree 1 are exactly the prime subfield GF(p)
+		if got := degrees[1]; got != int(f.BasePrime()) {
+			t.Errorf("%s: %d elements have a linear minimal polynomial, want %d", name, got, f.BasePrime())
+		}
+	}
+
+	// GF(16) contains the subfield GF(4), whose 2 elements outside GF(2) have degree 2
+	degree2 := 0
+	for _, a := range gf16.Elements() {
+		if len(gf16.MinimalPolynomial(a)) == 3 {
+			degree2++
+		}
+	}
+	if degree2 != 2 {
+		t.Errorf("GF(16): %d elements of degree 2, want 2", degree2)
+	}
+}
+
+// polyValues returns the integer values of a polynomial's coefficients
+func polyValues(p arithpoly.Polynomial) []int16 {
+	values := make([]int16, len(p))
+	for i, c := range p {
+		values[i] = c.Value()
+	}
+	return values
+}
+
+func TestLogExp(t *testing.T) {
+	for name, f := range map[string]Field{
+		"GF(27)":  newTestField(t, 3, 3, []int{1, 2, 0, 1}),
+		"GF(256)": qrField(t),
+	} {
+		groupOrder := f.Order() - 1
+		for k, x := range f.MultiplicativeGroup() {
+			power, err := f.Log(x)
+			if err != nil {
+				t.Errorf("%s: Log(%s) returned error: %v", name, x, err)
+				continue
+			}
+			if power != k {
+				t.Errorf("%s: Log(%s) = %d, want %d", name, x, power, k)
+			}
+			if got := f.Exp(power); !got.Equal(x) {
+				t.Errorf("%s: Exp(Log(%s)) = %s", name, x, got)
+			}
+			for _, shifted := range []int{power + groupOrder, power - groupOrder, power - 5*groupOrder} {
+				if got := f.Exp(shifted); !got.Equal(x) {
+					t.Errorf("%s: Exp(%d) = %s, want %s", name, shifted, got, x)
+				}
+			}
+		}
+
+		if _, err := f.Log(f.Zero()); err == nil {
+			t.Errorf("%s: Log(0) succeeded, want error", name)
+		}
+		if got := f.Exp(1); !got.Equal(f.Primitive()) {
+			t.Errorf("%s: Exp(1) = %s, want α = %s", name, got, f.Primitive())
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	f := newTestField(t, 3, 2, []int{2, 2, 1})
+	elements := f.Elements()
+	for i, a := range elements {
+		if !a.Equal(a) || !a.Equal(a.Clone()) {
+			t.Errorf("%s is not equal to itself", a)
+		}
+		for j, b := range elements {
+			if i != j && a.Equal(b) {
+				t.Errorf("distinct elements %s and %s compare equal", a, b)
+			}
+		}
+	}
+
+	// Zero reached through arithmetic equals the field's zero
+	one := f.One()
+	if zero := f.Sub(one, one); !zero.Equal(f.Zero()) {
+		t.Errorf("1 - 1 = %s is not equal to zero", zero)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("comparing elements of different fields did not panic")
+		}
+	}()
+	one.Equal(newTestField(t, 3, 2, []int{2, 2, 1}).One())
+}
+
+func TestNewFieldRejectsReducible(t *testing.T) {
+	// x^4 + x^2 + 1 = (x^2 + x + 1)^2 has no roots in GF(2) but is not irreducible
+	if _, err := NewField(2, 4, []int{1, 0, 1, 0, 1}); err == nil {
+		t.Error("NewField accepted the reducible x^4 + x^2 + 1")
+	}
+	// x^4 + x^3 + x^2 + x + 1 is irreducible but x is not primitive; another generator is found
+	f := newTestField(t, 2, 4, []int{1, 1, 1, 1, 1})
+	if !f.IsPrimitive(f.Primitive()) {
+		t.Errorf("α = %s is not primitive", f.Primitive())
+	}
+}
@@ -1,5 +1,12 @@
 package gfpn
 
+import (
+	"math/big"
+
+	"github.com/jalphad/abstract_algebra/exercises/1-gf"
+	"github.com/jalphad/abstract_algebra/exercises/2-arithpoly"
+)
+
 // Field represents a finite field GF(p^n)
 type Field interface {
 	// Elements returns all elements in the field
@@ -11,6 +18,10 @@ type Field interface {
 	// i > 1 maps to α^(i-1) for i = 2, 3, ..., p^n-1
 	Element(value int) Element
 
+	// MultiplicativeGroup returns the non-zero elements in generator order
+	// [α^0, α^1, ..., α^(p^n-2)], where α is the primitive element
+	MultiplicativeGroup() []Element
+
 	// Zero returns the additive identity (zero element)
 	Zero() Element
 
@@ -20,6 +31,15 @@ type Field interface {
 	// Primitive returns the primitive element that generates the multiplicative group
 	Primitive() Element
 
+	// IsPrimitive reports whether an element generates the multiplicative group
+	IsPrimitive(e Element) bool
+
+	// Log returns the discrete logarithm k in [0, p^n-2] with α^k = e, or an error for zero
+	Log(e Element) (int, error)
+
+	// Exp returns α^power, reducing the power modulo p^n - 1
+	Exp(power int) Element
+
 	// Add performs addition of two field elements
 	Add(e1, e2 Element) Element
 
@@ -34,6 +54,42 @@ type Field interface {
 
 	// Order returns p^n (the number of elements in the field)
 	Order() int
+
+	// BasePrime returns p, the characteristic of the field
+	BasePrime() int16
+
+	// Degree returns n, the degree of the extension over GF(p)
+	Degree() int
+
+	// IrreduciblePolynomial returns the coefficients [a0, a1, ..., an] of the
+	// polynomial the field was constructed with, lowest degree first
+	IrreduciblePolynomial() []int
+
+	// Pow raises an element to the power k (negative k gives powers of the inverse)
+	// Like Element.Pow, it panics for zero to a non-positive power, 0^0 included
+	Pow(e Element, k int) Element
+
+	// PowBig raises an element to a big power k, reducing k modulo p^n - 1
+	// Zero is handled as in Pow
+	PowBig(e Element, k *big.Int) Element
+
+	// InverseFermat returns e^(p^n - 2) = e^-1, computed without the power tables
+	InverseFermat(e Element) Element
+
+	// Frobenius returns e^p, the image of e under the Frobenius automorphism
+	Frobenius(e Element) Element
+
+	// FrobeniusPow returns e^(p^k), the Frobenius map applied k times
+	FrobeniusPow(e Element, k int) Element
+
+	// Trace returns e + e^p + ... + e^(p^(n-1)) as an element of the base field GF(p)
+	Trace(e Element) gf.Element
+
+	// Norm returns e^((p^n - 1)/(p - 1)) as an element of the base field GF(p)
+	Norm(e Element) gf.Element
+
+	// MinimalPolynomial returns the monic polynomial of least degree over GF(p) with e as a root
+	MinimalPolynomial(e Element) arithpoly.Polynomial
 }
 
 // Element represents an element in GF(p^n)
@@ -44,6 +100,13 @@ type Element interface {
 	// String returns a pretty-printed representation of the element
 	String() string
 
+	// Equal reports whether two elements of the same field are the same element
+	// It panics if the elements are from different fields
+	Equal(e Element) bool
+
+	// Clone returns a deep copy that does not share its coefficients with the field tables
+	Clone() Element
+
 	// Add performs addition with another element
 	Add(e Element) Element
 
@@ -55,4 +118,16 @@ type Element interface {
 
 	// Div performs division by another element
 	Div(e Element) Element
+
+	// Inverse returns the multiplicative inverse e^-1, so that e.Mul(e.Inverse()) is one
+	// It panics on the zero element
+	Inverse() Element
+
+	// Pow raises the element to an integer power, negative exponents being powers of the inverse
+	// Zero to a positive power is zero; zero to a non-positive power panics
+	Pow(exp int) Element
+
+	// Negate returns the additive inverse -e, so that e.Add(e.Negate()) is zero
+	// In characteristic 2 every element is its own additive inverse
+	Negate() Element
 }
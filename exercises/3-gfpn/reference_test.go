@@ -0,0 +1,158 @@
+package gfpn
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jalphad/abstract_algebra/exercises/1-gf"
+	"github.com/jalphad/abstract_algebra/exercises/2-arithpoly"
+)
+
+// ReferenceMul multiplies two elements by direct polynomial arithmetic
+//
+// The product is computed as (a(x) · b(x)) mod m(x) with arithpoly.PolyMul and
+// arithpoly.PolyDiv, where m(x) is the field's irreducible polynomial. It never
+// touches the power tables, so it serves as an independent oracle for Mul.
+func ReferenceMul(f Field, a, b Element) Element {
+	fld := f.(*field)
+	if a.IsZero() || b.IsZero() {
+		return fld.Zero()
+	}
+
+	product := arithpoly.PolyMul(fld.baseField, a.(*element).coeffs, b.(*element).coeffs)
+	_, remainder := arithpoly.PolyDiv(fld.baseField, product, fld.irreducible)
+
+	// The remainder may be trimmed; pad it to the n coefficients used as table keys
+	coeffs := make([]gf.Element, fld.degree)
+	for i := range coeffs {
+		coeffs[i] = fld.baseField.Element(0)
+	}
+	copy(coeffs, remainder)
+
+	return fld.fromCoeffs(coeffs)
+}
+
+func TestMulMatchesReference(t *testing.T) {
+	fields := map[string]Field{
+		// GF(16) = GF(2)[x]/(x^4 + x + 1)
+		"GF(16)":  newTestField(t, 2, 4, []int{1, 1, 0, 0, 1}),
+		"GF(256)": qrField(t),
+	}
+
+	for name, f := range fields {
+		elements := f.Elements()
+		for _, a := range elements {
+			for _, b := range elements {
+				got, want := f.Mul(a, b), ReferenceMul(f, a, b)
+				if !got.Equal(want) {
+					t.Fatalf("%s: %s · %s = %s, reference gives %s", name, a, b, got, want)
+				}
+			}
+		}
+	}
+}
+
+// qrPolynomialLow is the QR code polynomial x^8 + x^4 + x^3 + x^2 + 1 without its x^8 term
+const qrPolynomialLow = 0x1D
+
+// mulBytes multiplies two GF(256) elements packed into bytes (bit i is the
+// coefficient of x^i) by shift-and-add with reduction by the QR code polynomial
+//
+// This is the byte-backed baseline for the benchmarks below, standing in until
+// the package has its own GF(256) byte type.
+func mulBytes(a, b byte) byte {
+	var product byte
+	for b != 0 {
+		if b&1 != 0 {
+			product ^= a
+		}
+		carry := a&0x80 != 0
+		a <<= 1
+		if carry {
+			a ^= qrPolynomialLow
+		}
+		b >>= 1
+	}
+	return product
+}
+
+// elementToByte packs the coefficients of a GF(2^8) element into a byte
+func elementToByte(e Element) byte {
+	var packed byte
+	for i, c := range e.(*element).coeffs {
+		if c.Value() != 0 {
+			packed |= 1 << i
+		}
+	}
+	return packed
+}
+
+// randomPairs returns n pseudo-random element pairs of f, including zeros
+func randomPairs(f Field, n int) [][2]Element {
+	rng := rand.New(rand.NewSource(1))
+	pairs := make([][2]Element, n)
+	for i := range pairs {
+		pairs[i] = [2]Element{f.Element(rng.Intn(f.Order())), f.Element(rng.Intn(f.Order()))}
+	}
+	return pairs
+}
+
+func TestMulPathsAgree(t *testing.T) {
+	f := qrField(t)
+	for _, pair := range randomPairs(f, 1000) {
+		a, b := pair[0], pair[1]
+		table := f.Mul(a, b)
+		reference := ReferenceMul(f, a, b)
+		packed := mulBytes(elementToByte(a), elementToByte(b))
+
+		if !table.Equal(reference) {
+			t.Errorf("%s · %s: Mul = %s, ReferenceMul = %s", a, b, table, reference)
+		}
+		if elementToByte(table) != packed {
+			t.Errorf("%s · %s: Mul = %08b, byte multiply = %08b", a, b, elementToByte(table), packed)
+		}
+	}
+}
+
+// The GF(256) multiplication benchmarks compare the power-table Mul with the
+// polynomial arithmetic reference and a byte multiply, over the same operands.
+// Run with: go test -bench Mul -benchmem ./exercises/3-gfpn
+
+func BenchmarkGF256MulTable(b *testing.B) {
+	f := qrField(b)
+	pairs := randomPairs(f, 256)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pair := pairs[i%len(pairs)]
+		f.Mul(pair[0], pair[1])
+	}
+}
+
+func BenchmarkGF256MulReference(b *testing.B) {
+	f := qrField(b)
+	pairs := randomPairs(f, 256)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pair := pairs[i%len(pairs)]
+		ReferenceMul(f, pair[0], pair[1])
+	}
+}
+
+func BenchmarkGF256MulBytes(b *testing.B) {
+	f := qrField(b)
+	pairs := randomPairs(f, 256)
+	packed := make([][2]byte, len(pairs))
+	for i, pair := range pairs {
+		packed[i] = [2]byte{elementToByte(pair[0]), elementToByte(pair[1])}
+	}
+	var sink byte
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pair := packed[i%len(packed)]
+		sink ^= mulBytes(pair[0], pair[1])
+	}
+	_ = sink
+}
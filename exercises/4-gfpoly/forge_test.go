@@ -8,6 +8,7 @@ import (
 
 	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
 	"github.com/jalphad/abstract_algebra/testcases/gfpoly"
+	"github.com/jalphad/abstract_algebra/testcases/testutil"
 
 	v1 "github.com/jalphad/testforge/proto"
 	"google.golang.org/grpc"
@@ -54,37 +55,37 @@ func TestGFPolyImplementation(t *testing.T) {
 	results := make([]gfpoly.PolyResult, len(input.Operations))
 	for i, op := range input.Operations {
 		// Convert coefficient indices to elements
-		poly1 := indicesToPoly(field, op.Poly1)
-		poly2 := indicesToPoly(field, op.Poly2)
+		poly1 := NewPolynomialFromIndices(field, op.Poly1)
+		poly2 := NewPolynomialFromIndices(field, op.Poly2)
 
 		var result gfpoly.PolyResult
 
 		switch op.Op {
 		case "add":
 			p := Add(poly1, poly2)
-			result.Polynomial = polyToStrings(p)
+			result.Polynomial = testutil.ElementsToStrings(p.Coefficients())
 
 		case "sub":
 			p := Subtract(poly1, poly2)
-			result.Polynomial = polyToStrings(p)
+			result.Polynomial = testutil.ElementsToStrings(p.Coefficients())
 
 		case "mul":
 			p := Multiply(poly1, poly2)
-			result.Polynomial = polyToStrings(p)
+			result.Polynomial = testutil.ElementsToStrings(p.Coefficients())
 
 		case "scalar_mul":
 			scalar := field.Element(op.Scalar)
 			p := ScalarMultiply(scalar, poly1)
-			result.Polynomial = polyToStrings(p)
+			result.Polynomial = testutil.ElementsToStrings(p.Coefficients())
 
 		case "derivative":
 			p := FormalDerivative(poly1)
-			result.Polynomial = polyToStrings(p)
+			result.Polynomial = testutil.ElementsToStrings(p.Coefficients())
 
 		case "divide":
 			q, r := Divide(poly1, poly2)
-			result.Quotient = polyToStrings(q)
-			result.Remainder = polyToStrings(r)
+			result.Quotient = testutil.ElementsToStrings(q.Coefficients())
+			result.Remainder = testutil.ElementsToStrings(r.Coefficients())
 
 		case "eval":
 			point := field.Element(op.Point)
@@ -130,33 +131,3 @@ func TestGFPolyImplementation(t *testing.T) {
 		t.Errorf("GF(p^n) polynomial implementation validation failed: %s", submitResp.Message)
 	}
 }
-
-// Helper functions
-
-// indicesToPoly converts a slice of element indices to a polynomial
-func indicesToPoly(field gfpn.Field, indices []int) Polynomial {
-	if len(indices) == 0 {
-		return NewPolynomial(field, []gfpn.Element{})
-	}
-
-	elements := make([]gfpn.Element, len(indices))
-	for i, idx := range indices {
-		elements[i] = field.Element(idx)
-	}
-
-	return NewPolynomial(field, elements)
-}
-
-// polyToStrings converts a polynomial to a slice of coefficient strings
-func polyToStrings(p Polynomial) []string {
-	coeffs := p.Coefficients()
-	if len(coeffs) == 0 {
-		return []string{}
-	}
-
-	result := make([]string, len(coeffs))
-	for i, c := range coeffs {
-		result[i] = c.String()
-	}
-	return result
-}
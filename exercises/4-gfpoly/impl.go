@@ -1,6 +1,11 @@
 package gfpoly
 
-import "github.com/jalphad/abstract_algebra/exercises/3-gfpn"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
+)
 
 // polynomial is a concrete implementation of the Polynomial interface
 type polynomial struct {
@@ -50,6 +55,17 @@ func NewPolynomial(field gfpn.Field, coeffs []gfpn.Element) Polynomial {
 	}
 }
 
+// NewPolynomialFromIndices creates a new polynomial from element indices
+// indices are from lowest degree to highest degree, each mapped through field.Element
+// (0 is zero, 1 is one, i > 1 is α^(i-1))
+func NewPolynomialFromIndices(field gfpn.Field, indices []int) Polynomial {
+	coeffs := make([]gfpn.Element, len(indices))
+	for i, idx := range indices {
+		coeffs[i] = field.Element(idx)
+	}
+	return NewPolynomial(field, coeffs)
+}
+
 // Coefficients returns the polynomial coefficients from lowest to highest degree
 func (p *polynomial) Coefficients() []gfpn.Element {
 	// Return a copy to prevent external modification
@@ -58,6 +74,22 @@ func (p *polynomial) Coefficients() []gfpn.Element {
 	return result
 }
 
+// CoefficientsPadded returns the coefficients zero-padded at the high end to exactly length entries
+// Coefficients() strips trailing zeros, so use this when comparing against fixed-width
+// expected outputs. Panics if the degree exceeds length-1.
+func (p *polynomial) CoefficientsPadded(length int) []gfpn.Element {
+	if len(p.coeffs) > length {
+		panic("polynomial degree exceeds padded length")
+	}
+
+	result := make([]gfpn.Element, length)
+	copy(result, p.coeffs)
+	for i := len(p.coeffs); i < length; i++ {
+		result[i] = p.field.Zero()
+	}
+	return result
+}
+
 // Degree returns the degree of the polynomial (-1 for zero polynomial)
 func (p *polynomial) Degree() int {
 	if len(p.coeffs) == 0 {
@@ -80,6 +112,28 @@ func (p *polynomial) Evaluate(x gfpn.Element) gfpn.Element {
 	return result
 }
 
+// EvaluateWithDerivative evaluates the polynomial and its formal derivative at a given point
+// This uses the extended Horner scheme, computing both values in a single pass:
+//
+//	value      = value·x + a_i
+//	derivative = derivative·x + value (using value before its update)
+//
+// The result matches FormalDerivative(p).Evaluate(x) without building the derivative polynomial
+func (p *polynomial) EvaluateWithDerivative(x gfpn.Element) (value, derivative gfpn.Element) {
+	if len(p.coeffs) == 0 {
+		return p.field.Zero(), p.field.Zero()
+	}
+
+	value = p.coeffs[len(p.coeffs)-1]
+	derivative = p.field.Zero()
+	for i := len(p.coeffs) - 2; i >= 0; i-- {
+		derivative = p.field.Add(p.field.Mul(derivative, x), value)
+		value = p.field.Add(p.field.Mul(value, x), p.coeffs[i])
+	}
+
+	return value, derivative
+}
+
 // IsZero returns true if this is the zero polynomial
 func (p *polynomial) IsZero() bool {
 	return len(p.coeffs) == 0
@@ -90,13 +144,145 @@ func (p *polynomial) Field() gfpn.Field {
 	return p.field
 }
 
+// Shift multiplies the polynomial by x^k by prepending k zero coefficients
+// For p(x) = a0 + a1*x, Shift(2) gives a0*x^2 + a1*x^3
+func (p *polynomial) Shift(k int) Polynomial {
+	if k < 0 {
+		panic("shift amount must be non-negative")
+	}
+
+	// Shifting the zero polynomial gives the zero polynomial
+	if len(p.coeffs) == 0 {
+		return NewPolynomial(p.field, []gfpn.Element{})
+	}
+
+	result := make([]gfpn.Element, k+len(p.coeffs))
+	for i := 0; i < k; i++ {
+		result[i] = p.field.Zero()
+	}
+	copy(result[k:], p.coeffs)
+
+	return NewPolynomial(p.field, result)
+}
+
+// Truncate drops all terms of degree greater than maxDegree
+// This computes p(x) mod x^(maxDegree+1), e.g. the mod x^2t step of the key equation
+func (p *polynomial) Truncate(maxDegree int) Polynomial {
+	if maxDegree < 0 {
+		return NewPolynomial(p.field, []gfpn.Element{})
+	}
+
+	if maxDegree >= len(p.coeffs) {
+		return p
+	}
+
+	result := make([]gfpn.Element, maxDegree+1)
+	copy(result, p.coeffs[:maxDegree+1])
+
+	return NewPolynomial(p.field, result)
+}
+
+// LeadingCoefficient returns the coefficient of x^deg, or zero for the zero polynomial
+func (p *polynomial) LeadingCoefficient() gfpn.Element {
+	if p.IsZero() {
+		return p.field.Zero()
+	}
+	return p.coeffs[len(p.coeffs)-1]
+}
+
+// Monic returns p divided by its leading coefficient, so the result has leading coefficient one
+// The zero polynomial has no leading coefficient to divide by and is returned as is.
+func (p *polynomial) Monic() Polynomial {
+	if p.IsZero() {
+		return p
+	}
+	return ScalarMultiply(p.LeadingCoefficient().Inverse(), p)
+}
+
+// Equal reports whether two polynomials are equal
+// Coefficients are compared after normalization, so trailing zero coefficients
+// do not matter and every representation of zero is equal. Polynomials over
+// different fields are never equal; their coefficients cannot be compared.
+func (p *polynomial) Equal(other Polynomial) bool {
+	if p.field != other.Field() {
+		return false
+	}
+
+	coeffs := other.Coefficients()
+	if len(p.coeffs) != len(coeffs) {
+		return false
+	}
+	for i, c := range p.coeffs {
+		if !c.Equal(coeffs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// String renders the polynomial in descending degree, e.g. "011·x^2 + x + 111"
+// Each coefficient is printed with its element's String(); zero terms are skipped,
+// a coefficient of one is left out in front of a power of x, and the zero
+// polynomial is "0".
+func (p *polynomial) String() string {
+	if p.IsZero() {
+		return "0"
+	}
+
+	one := p.field.One()
+	var terms []string
+	for i := len(p.coeffs) - 1; i >= 0; i-- {
+		c := p.coeffs[i]
+		if c.IsZero() {
+			continue
+		}
+
+		var power string
+		switch i {
+		case 0:
+			terms = append(terms, c.String())
+			continue
+		case 1:
+			power = "x"
+		default:
+			power = fmt.Sprintf("x^%d", i)
+		}
+
+		if c.Equal(one) {
+			terms = append(terms, power)
+		} else {
+			terms = append(terms, c.String()+"·"+power)
+		}
+	}
+	return strings.Join(terms, " + ")
+}
+
 // Add adds two polynomials
 func Add(p1, p2 Polynomial) Polynomial {
 	if p1.Field() != p2.Field() {
 		panic("polynomials must be over the same field")
 	}
 
-	panic("not implemented")
+	field := p1.Field()
+	coeffs1 := p1.Coefficients()
+	coeffs2 := p2.Coefficients()
+
+	// Pad the shorter polynomial with zeros so both have the same length
+	resultLen := max(len(coeffs1), len(coeffs2))
+	result := make([]gfpn.Element, resultLen)
+	for i := range result {
+		a, b := field.Zero(), field.Zero()
+		if i < len(coeffs1) {
+			a = coeffs1[i]
+		}
+		if i < len(coeffs2) {
+			b = coeffs2[i]
+		}
+		result[i] = field.Add(a, b)
+	}
+
+	// Leading terms may cancel, NewPolynomial drops the resulting zeros
+	return NewPolynomial(field, result)
 }
 
 // Subtract subtracts two polynomials
@@ -105,7 +291,26 @@ func Subtract(p1, p2 Polynomial) Polynomial {
 		panic("polynomials must be over the same field")
 	}
 
-	panic("not implemented")
+	field := p1.Field()
+	coeffs1 := p1.Coefficients()
+	coeffs2 := p2.Coefficients()
+
+	// Pad the shorter polynomial with zeros so both have the same length
+	resultLen := max(len(coeffs1), len(coeffs2))
+	result := make([]gfpn.Element, resultLen)
+	for i := range result {
+		a, b := field.Zero(), field.Zero()
+		if i < len(coeffs1) {
+			a = coeffs1[i]
+		}
+		if i < len(coeffs2) {
+			b = coeffs2[i]
+		}
+		result[i] = field.Sub(a, b)
+	}
+
+	// Leading terms may cancel, NewPolynomial drops the resulting zeros
+	return NewPolynomial(field, result)
 }
 
 // Multiply multiplies two polynomials
@@ -158,6 +363,42 @@ func ScalarMultiply(scalar gfpn.Element, p Polynomial) Polynomial {
 	return NewPolynomial(field, result)
 }
 
+// ScaleShiftSubtract computes target(x) - scalar·x^shift·source(x) in a single pass
+// This is the Berlekamp-Massey update Λ(x) ← Λ(x) - (d/b)·x^m·B(x) without the
+// intermediate polynomials of ScalarMultiply, Shift and Subtract
+func ScaleShiftSubtract(target, source Polynomial, scalar gfpn.Element, shift int) Polynomial {
+	if target.Field() != source.Field() {
+		panic("polynomials must be over the same field")
+	}
+	if shift < 0 {
+		panic("shift must be non-negative")
+	}
+
+	field := target.Field()
+	targetCoeffs := target.(*polynomial).coeffs
+	sourceCoeffs := source.(*polynomial).coeffs
+
+	length := len(targetCoeffs)
+	if len(sourceCoeffs) > 0 && len(sourceCoeffs)+shift > length {
+		length = len(sourceCoeffs) + shift
+	}
+
+	result := make([]gfpn.Element, length)
+	for i := range result {
+		if i < len(targetCoeffs) {
+			result[i] = targetCoeffs[i]
+		} else {
+			result[i] = field.Zero()
+		}
+
+		if j := i - shift; j >= 0 && j < len(sourceCoeffs) {
+			result[i] = field.Sub(result[i], field.Mul(scalar, sourceCoeffs[j]))
+		}
+	}
+
+	return NewPolynomial(field, result)
+}
+
 // FormalDerivative computes the formal derivative of a polynomial
 // For p(x) = a0 + a1*x + a2*x^2 + ... + an*x^n
 // p'(x) = a1 + 2*a2*x + 3*a3*x^2 + ... + n*an*x^(n-1)
@@ -277,3 +518,212 @@ func Divide(dividend, divisor Polynomial) (quotient, remainder Polynomial) {
 	remainder = NewPolynomial(field, remCoeffs)
 	return quotient, remainder
 }
+
+// Mod returns the remainder of dividing dividend by divisor
+// This is the remainder from Divide without allocating the quotient, for callers
+// such as the key equation S(x)·Λ(x) mod x^2t that only need the remainder
+func Mod(dividend, divisor Polynomial) Polynomial {
+	if dividend.Field() != divisor.Field() {
+		panic("polynomials must be over the same field")
+	}
+
+	if divisor.IsZero() {
+		panic("division by zero polynomial")
+	}
+
+	field := dividend.Field()
+	divCoeffs := divisor.Coefficients()
+	divisorDeg := len(divCoeffs) - 1
+
+	// If dividend degree < divisor degree, the dividend is the remainder
+	if dividend.Degree() < divisorDeg {
+		return dividend
+	}
+
+	remCoeffs := dividend.Coefficients()
+	leadingCoeff := divCoeffs[divisorDeg]
+
+	// Cancel the leading term of the remainder until its degree drops below the divisor's
+	for remDeg := len(remCoeffs) - 1; remDeg >= divisorDeg; remDeg-- {
+		if remCoeffs[remDeg].IsZero() {
+			continue
+		}
+
+		factor := field.Div(remCoeffs[remDeg], leadingCoeff)
+		offset := remDeg - divisorDeg
+		for i := 0; i <= divisorDeg; i++ {
+			remCoeffs[offset+i] = field.Sub(remCoeffs[offset+i], field.Mul(factor, divCoeffs[i]))
+		}
+	}
+
+	return NewPolynomial(field, remCoeffs)
+}
+
+// EuclidStep records one division of the extended Euclidean algorithm
+//
+// Step i divides the remainder r_(i-2) by r_(i-1), giving r_(i-2) = Quotient·r_(i-1) + Remainder.
+// U and V are the running Bézout coefficients, which keep the invariant
+//
+//	U·a + V·b = Remainder
+//
+// for the inputs a and b of EuclideanSteps.
+type EuclidStep struct {
+	Quotient  Polynomial
+	Remainder Polynomial
+	U         Polynomial
+	V         Polynomial
+}
+
+// EuclideanSteps runs the extended Euclidean algorithm on a and b and returns every step
+//
+// The remainder degrees strictly decrease, and the last step has a zero remainder;
+// its predecessor's remainder (or b, if there is only one step) is gcd(a, b) up to a
+// scalar. Reed-Solomon decoders stop early instead: with a = x^2t and b = S(x), the
+// first step whose remainder has degree < t solves the key equation, with V as the
+// error locator and Remainder as the error evaluator (up to a common scalar).
+//
+// Parameters:
+//   - a, b: Polynomials over the same field
+//
+// Returns:
+//   - The remainder sequence with quotients and Bézout coefficients, or nil if b is zero
+//
+// Example:
+//
+//	// a = x^4, b = S(x) of degree 3
+//	steps := EuclideanSteps(a, b)
+//	// steps[0].Quotient has degree 1, steps[0].V = -steps[0].Quotient
+func EuclideanSteps(a, b Polynomial) []EuclidStep {
+	if a.Field() != b.Field() {
+		panic("polynomials must be over the same field")
+	}
+
+	field := a.Field()
+	zero := NewPolynomial(field, []gfpn.Element{})
+	one := NewPolynomial(field, []gfpn.Element{field.One()})
+
+	// (r, u, v) for the two previous remainders, starting from a = 1·a + 0·b and b = 0·a + 1·b
+	prevR, prevU, prevV := a, one, zero
+	curR, curU, curV := b, zero, one
+
+	var steps []EuclidStep
+	for !curR.IsZero() {
+		quotient, remainder := Divide(prevR, curR)
+		u := Subtract(prevU, Multiply(quotient, curU))
+		v := Subtract(prevV, Multiply(quotient, curV))
+
+		steps = append(steps, EuclidStep{Quotient: quotient, Remainder: remainder, U: u, V: v})
+
+		prevR, prevU, prevV = curR, curU, curV
+		curR, curU, curV = remainder, u, v
+	}
+
+	return steps
+}
+
+// GCD returns the monic greatest common divisor of a and b
+// The gcd of two zero polynomials is the zero polynomial
+func GCD(a, b Polynomial) Polynomial {
+	g, _, _ := ExtendedGCD(a, b)
+	return g
+}
+
+// ExtendedGCD returns the monic gcd g of a and b with Bézout coefficients s, t
+//
+// The coefficients satisfy s·a + t·b = g. When g = 1, t is the inverse of b
+// modulo a, e.g. to invert a polynomial modulo x^k. This is the last non-zero
+// remainder of EuclideanSteps, scaled by the inverse of its leading coefficient.
+//
+// Parameters:
+//   - a, b: Polynomials over the same field
+//
+// Returns:
+//   - g: The monic gcd, or zero if both a and b are zero
+//   - s, t: Bézout coefficients with s·a + t·b = g
+//
+// Example:
+//
+//	// a = (x - α)(x - α^2), b = (x - α)(x - α^3)
+//	g, s, t := ExtendedGCD(a, b)
+//	// g = x - α, and Add(Multiply(s, a), Multiply(t, b)) equals g
+func ExtendedGCD(a, b Polynomial) (g, s, t Polynomial) {
+	if a.Field() != b.Field() {
+		panic("polynomials must be over the same field")
+	}
+
+	field := a.Field()
+	zero := NewPolynomial(field, []gfpn.Element{})
+	one := NewPolynomial(field, []gfpn.Element{field.One()})
+
+	// The gcd is the last non-zero remainder: a = 1·a + 0·b if b is zero, b = 0·a + 1·b
+	// if b divides a, and otherwise the remainder of the step before the final one
+	switch steps := EuclideanSteps(a, b); {
+	case b.IsZero():
+		g, s, t = a, one, zero
+	case len(steps) == 1:
+		g, s, t = b, zero, one
+	default:
+		last := steps[len(steps)-2]
+		g, s, t = last.Remainder, last.U, last.V
+	}
+
+	if g.IsZero() {
+		return zero, zero, zero
+	}
+
+	scale := g.LeadingCoefficient().Inverse()
+	return g.Monic(), ScalarMultiply(scale, s), ScalarMultiply(scale, t)
+}
+
+// Roots returns the distinct roots of p, found by evaluating it at every field element
+//
+// This costs O(p^n · deg p) field operations, which is fine for fields up to GF(256).
+// Roots are returned in the order of Field.Elements() and without multiplicity,
+// e.g. (x - 1)^2 has the single root 1. Every element is a root of the zero
+// polynomial, and a non-zero constant has none.
+func Roots(p Polynomial) []gfpn.Element {
+	var roots []gfpn.Element
+	for _, x := range p.Field().Elements() {
+		if p.Evaluate(x).IsZero() {
+			roots = append(roots, x)
+		}
+	}
+	return roots
+}
+
+// Compose returns the composition p(q(x))
+//
+// Horner's method with q in place of x:
+//
+//	p(q) = (···((a_n·q + a_(n-1))·q + a_(n-2))···)·q + a_0
+//
+// so the result has degree deg(p)·deg(q) for non-constant p and q (the leading
+// coefficient a_n·b_m^n is non-zero in a field). If p is constant, so is the
+// result; if q is constant c, the result is the constant p(c).
+//
+// Parameters:
+//   - p: Outer polynomial
+//   - q: Inner polynomial, over the same field
+//
+// Returns:
+//   - The polynomial p(q(x))
+//
+// Example:
+//
+//	// p = x^2 + 1, q = x + α
+//	r := Compose(p, q)
+//	// r = x^2 + α^2 + 1 in characteristic 2
+func Compose(p, q Polynomial) Polynomial {
+	if p.Field() != q.Field() {
+		panic("polynomials must be over the same field")
+	}
+
+	field := p.Field()
+	coeffs := p.Coefficients()
+
+	result := NewPolynomial(field, []gfpn.Element{})
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		result = Add(Multiply(result, q), NewPolynomial(field, []gfpn.Element{coeffs[i]}))
+	}
+	return result
+}
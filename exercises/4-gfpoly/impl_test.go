@@ -0,0 +1,564 @@
+package gfpoly
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
+	"github.com/jalphad/abstract_algebra/testcases/testutil"
+)
+
+// qrField creates GF(256) with the QR code polynomial x^8 + x^4 + x^3 + x^2 + 1
+func qrField(t testing.TB) gfpn.Field {
+	t.Helper()
+	field, err := gfpn.NewField(2, 8, []int{1, 0, 1, 1, 1, 0, 0, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(256): %v", err)
+	}
+	return field
+}
+
+// newPoly creates a polynomial from element indices, lowest degree first
+func newPoly(field gfpn.Field, indices ...int) Polynomial {
+	return NewPolynomialFromIndices(field, indices)
+}
+
+// assertCoefficients checks that a polynomial has the expected coefficient strings
+func assertCoefficients(t *testing.T, p Polynomial, want []string) {
+	t.Helper()
+	got := testutil.ElementsToStrings(p.Coefficients())
+	if len(got) != len(want) {
+		t.Fatalf("got coefficients %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got coefficients %v, want %v", got, want)
+		}
+	}
+}
+
+func TestNewPolynomialFromIndices(t *testing.T) {
+	field := qrField(t)
+
+	// 1 + α^4·x + α^254·x^3, with trailing zeros trimmed as by NewPolynomial
+	got := NewPolynomialFromIndices(field, []int{1, 5, 0, 255, 0})
+	want := NewPolynomial(field, []gfpn.Element{
+		field.One(), field.Element(5), field.Zero(), field.Element(255),
+	})
+	if got.Degree() != 3 {
+		t.Errorf("Degree() = %d, want 3", got.Degree())
+	}
+	assertCoefficients(t, got, testutil.ElementsToStrings(want.Coefficients()))
+
+	if !NewPolynomialFromIndices(field, nil).IsZero() {
+		t.Error("no indices should give the zero polynomial")
+	}
+}
+
+func TestShift(t *testing.T) {
+	field := qrField(t)
+	zero, one := field.Zero().String(), field.One().String()
+
+	// (x + 1) * x^2 = x^3 + x^2
+	p := newPoly(field, 1, 1)
+	shifted := p.Shift(2)
+	if shifted.Degree() != 3 {
+		t.Errorf("Degree() = %d, want 3", shifted.Degree())
+	}
+	assertCoefficients(t, shifted, []string{zero, zero, one, one})
+
+	// Shifting by zero leaves the polynomial unchanged
+	assertCoefficients(t, p.Shift(0), []string{one, one})
+
+	// Shifting the zero polynomial gives the zero polynomial
+	if !NewPolynomial(field, nil).Shift(3).IsZero() {
+		t.Errorf("shifted zero polynomial should be zero")
+	}
+}
+
+func TestCoefficientsPadded(t *testing.T) {
+	field := qrField(t)
+	zero, one := field.Zero().String(), field.One().String()
+
+	// x + 1 padded to 4 coefficients: 1 + x + 0x^2 + 0x^3
+	p := newPoly(field, 1, 1)
+	padded := testutil.ElementsToStrings(p.CoefficientsPadded(4))
+	want := []string{one, one, zero, zero}
+	if len(padded) != len(want) {
+		t.Fatalf("CoefficientsPadded(4) = %v, want %v", padded, want)
+	}
+	for i := range want {
+		if padded[i] != want[i] {
+			t.Fatalf("CoefficientsPadded(4) = %v, want %v", padded, want)
+		}
+	}
+
+	// The zero polynomial pads to all zeros
+	for i, c := range NewPolynomial(field, nil).CoefficientsPadded(3) {
+		if !c.IsZero() {
+			t.Errorf("coefficient %d of padded zero polynomial = %s, want 0", i, c)
+		}
+	}
+
+	// Padding to fewer entries than the polynomial has panics
+	defer func() {
+		if recover() == nil {
+			t.Errorf("CoefficientsPadded(1) on a degree-1 polynomial should panic")
+		}
+	}()
+	p.CoefficientsPadded(1)
+}
+
+func TestTruncate(t *testing.T) {
+	field := qrField(t)
+	p := newPoly(field, 1, 2, 3, 4, 5, 6)
+
+	truncated := p.Truncate(2)
+	if truncated.Degree() != 2 {
+		t.Errorf("Degree() = %d, want 2", truncated.Degree())
+	}
+	assertCoefficients(t, truncated, testutil.ElementsToStrings(newPoly(field, 1, 2, 3).Coefficients()))
+
+	// Truncating at or above the degree keeps every term
+	assertCoefficients(t, p.Truncate(5), testutil.ElementsToStrings(p.Coefficients()))
+	assertCoefficients(t, p.Truncate(10), testutil.ElementsToStrings(p.Coefficients()))
+
+	// Dropped high terms can leave zero coefficients that are normalized away
+	q := newPoly(field, 7, 0, 0, 9)
+	assertCoefficients(t, q.Truncate(2), []string{field.Element(7).String()})
+
+	if !p.Truncate(-1).IsZero() {
+		t.Errorf("Truncate(-1) should give the zero polynomial")
+	}
+}
+
+func TestEvaluateWithDerivative(t *testing.T) {
+	field := qrField(t)
+	polys := []Polynomial{
+		newPoly(field),
+		newPoly(field, 7),
+		newPoly(field, 1, 1),
+		newPoly(field, 3, 0, 5, 9),
+		newPoly(field, 12, 200, 31, 4, 77, 1),
+	}
+	points := []int{0, 1, 2, 3, 29, 128, 255}
+
+	for _, p := range polys {
+		derivative := FormalDerivative(p)
+		for _, idx := range points {
+			x := field.Element(idx)
+			value, dValue := p.EvaluateWithDerivative(x)
+			if want := p.Evaluate(x); !value.Equal(want) {
+				t.Errorf("p(%s) = %s, want %s", x, value, want)
+			}
+			if want := derivative.Evaluate(x); !dValue.Equal(want) {
+				t.Errorf("p'(%s) = %s, want %s", x, dValue, want)
+			}
+		}
+	}
+}
+
+func TestMod(t *testing.T) {
+	field := qrField(t)
+	rng := rand.New(rand.NewSource(42))
+
+	randomPoly := func(maxDegree int) Polynomial {
+		indices := make([]int, rng.Intn(maxDegree+1)+1)
+		for i := range indices {
+			indices[i] = rng.Intn(field.Order())
+		}
+		return newPoly(field, indices...)
+	}
+
+	for i := 0; i < 100; i++ {
+		dividend := randomPoly(20)
+		divisor := randomPoly(8)
+		if divisor.IsZero() {
+			continue
+		}
+
+		_, want := Divide(dividend, divisor)
+		got := Mod(dividend, divisor)
+		assertCoefficients(t, got, testutil.ElementsToStrings(want.Coefficients()))
+	}
+
+	// A dividend of lower degree is its own remainder
+	low := newPoly(field, 5, 6)
+	assertCoefficients(t, Mod(low, newPoly(field, 1, 2, 3)), testutil.ElementsToStrings(low.Coefficients()))
+}
+
+func TestEuclideanSteps(t *testing.T) {
+	field := qrField(t)
+
+	// a = x^4, b = S(x) = α^5 + α^2·x + α^9·x^2 + α^3·x^3
+	a := newPoly(field, 0, 0, 0, 0, 1)
+	b := newPoly(field, 6, 3, 10, 4)
+
+	steps := EuclideanSteps(a, b)
+	if len(steps) == 0 {
+		t.Fatal("expected at least one step")
+	}
+
+	prevDegree := b.Degree()
+	for i, step := range steps {
+		if step.Remainder.Degree() >= prevDegree {
+			t.Errorf("step %d: remainder degree %d, want < %d", i, step.Remainder.Degree(), prevDegree)
+		}
+		prevDegree = step.Remainder.Degree()
+
+		bezout := Add(Multiply(step.U, a), Multiply(step.V, b))
+		assertCoefficients(t, bezout, testutil.ElementsToStrings(step.Remainder.Coefficients()))
+	}
+
+	if last := steps[len(steps)-1].Remainder; !last.IsZero() {
+		t.Errorf("last remainder = %v, want zero", testutil.ElementsToStrings(last.Coefficients()))
+	}
+
+	if steps := EuclideanSteps(a, newPoly(field)); steps != nil {
+		t.Errorf("EuclideanSteps(a, 0) = %d steps, want nil", len(steps))
+	}
+}
+
+func TestScaleShiftSubtract(t *testing.T) {
+	field := qrField(t)
+	rng := rand.New(rand.NewSource(7))
+
+	randomPoly := func(maxDegree int) Polynomial {
+		indices := make([]int, rng.Intn(maxDegree+1))
+		for i := range indices {
+			indices[i] = rng.Intn(field.Order())
+		}
+		return newPoly(field, indices...)
+	}
+
+	for i := 0; i < 200; i++ {
+		target, source := randomPoly(12), randomPoly(8)
+		scalar := field.Element(rng.Intn(field.Order()))
+		shift := rng.Intn(6)
+
+		want := Subtract(target, ScalarMultiply(scalar, source.Shift(shift)))
+		got := ScaleShiftSubtract(target, source, scalar, shift)
+		assertCoefficients(t, got, testutil.ElementsToStrings(want.Coefficients()))
+	}
+
+	// Cancelling the leading term lowers the degree: (α·x^2 + 1) - α·x^2·1 = 1
+	got := ScaleShiftSubtract(newPoly(field, 1, 0, 2), newPoly(field, 1), field.Element(2), 2)
+	assertCoefficients(t, got, []string{field.One().String()})
+}
+
+// benchmarkLocatorUpdate runs the Berlekamp-Massey locator update with update
+func benchmarkLocatorUpdate(b *testing.B, update func(target, source Polynomial, scalar gfpn.Element, shift int) Polynomial) {
+	field := qrField(b)
+	target := newPoly(field, 1, 17, 0, 200, 33, 5, 91, 12)
+	source := newPoly(field, 1, 44, 3, 150, 7, 60)
+	scalar := field.Element(99)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		update(target, source, scalar, 2)
+	}
+}
+
+func BenchmarkScaleShiftSubtract(b *testing.B) {
+	benchmarkLocatorUpdate(b, ScaleShiftSubtract)
+}
+
+func BenchmarkScaleShiftSubtractNaive(b *testing.B) {
+	benchmarkLocatorUpdate(b, func(target, source Polynomial, scalar gfpn.Element, shift int) Polynomial {
+		return Subtract(target, ScalarMultiply(scalar, source.Shift(shift)))
+	})
+}
+
+func TestAddSubtract(t *testing.T) {
+	// GF(8) = GF(2)[x]/(x^3 + x + 1): α = 010, α^2 = 100, α^3 = 011
+	gf8, err := gfpn.NewField(2, 3, []int{1, 1, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(8): %v", err)
+	}
+
+	p := newPoly(gf8, 1, 2, 4)    // 1 + α·x + α^3·x^2
+	q := newPoly(gf8, 2, 2, 4, 3) // α + α·x + α^3·x^2 + α^2·x^3
+
+	// 1 + α = α^3 and the middle terms cancel; q's extra term is carried over
+	assertCoefficients(t, Add(p, q), []string{"011", "0", "0", "100"})
+	assertCoefficients(t, Add(q, p), []string{"011", "0", "0", "100"})
+	// In characteristic 2 subtraction is addition
+	assertCoefficients(t, Subtract(p, q), []string{"011", "0", "0", "100"})
+
+	// Cancelling leading terms lowers the degree
+	if sum := Add(p, newPoly(gf8, 0, 0, 4)); sum.Degree() != 1 {
+		t.Errorf("degree of p + α^3·x^2 = %d, want 1", sum.Degree())
+	}
+
+	zero := NewPolynomial(gf8, nil)
+	assertCoefficients(t, Add(p, zero), []string{"001", "010", "011"})
+	assertCoefficients(t, Add(zero, zero), []string{})
+
+	// Over GF(9) subtraction differs from addition; check p - p = 0 and (p - q) + q = p
+	gf9, err := gfpn.NewField(3, 2, []int{2, 2, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(9): %v", err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		a := make([]int, 1+rng.Intn(5))
+		b := make([]int, 1+rng.Intn(5))
+		for i := range a {
+			a[i] = rng.Intn(9)
+		}
+		for i := range b {
+			b[i] = rng.Intn(9)
+		}
+		p, q := newPoly(gf9, a...), newPoly(gf9, b...)
+
+		if diff := Subtract(p, p); !diff.IsZero() {
+			t.Errorf("p - p = %v, want 0", testutil.ElementsToStrings(diff.Coefficients()))
+		}
+		if roundTrip := Add(Subtract(p, q), q); !roundTrip.Equal(p) {
+			t.Errorf("(p - q) + q = %s, want %s", roundTrip, p)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	// GF(8) = GF(2)[x]/(x^3 + x + 1): α^3 = 011, α^5 = 111
+	gf8, err := gfpn.NewField(2, 3, []int{1, 1, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(8): %v", err)
+	}
+
+	tests := []struct {
+		name string
+		p    Polynomial
+		want string
+	}{
+		{"zero", NewPolynomial(gf8, nil), "0"},
+		{"padded zero", newPoly(gf8, 0, 0, 0), "0"},
+		{"constant one", newPoly(gf8, 1), "001"},
+		{"α^3·x^2 + x + α^5", newPoly(gf8, 6, 1, 4), "011·x^2 + x + 111"},
+		{"skips zero terms", newPoly(gf8, 0, 0, 0, 1), "x^3"},
+		{"linear", newPoly(gf8, 2, 3), "100·x + 010"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			if got := fmt.Sprint(tt.p); got != tt.want {
+				t.Errorf("fmt.Sprint = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	field := qrField(t)
+
+	p := newPoly(field, 3, 0, 7)
+	if !p.Equal(p) {
+		t.Error("p is not equal to itself")
+	}
+	if !p.Equal(newPoly(field, 3, 0, 7, 0, 0)) {
+		t.Error("trailing zero coefficients make polynomials unequal")
+	}
+	if p.Equal(newPoly(field, 3, 0, 8)) || p.Equal(newPoly(field, 3, 0)) || p.Equal(newPoly(field, 3, 0, 7, 1)) {
+		t.Error("different polynomials compare equal")
+	}
+
+	zero, empty, padded := newPoly(field, 0), NewPolynomial(field, nil), newPoly(field, 0, 0, 0)
+	if !zero.Equal(empty) || !empty.Equal(padded) || !padded.Equal(zero) {
+		t.Error("representations of the zero polynomial are unequal")
+	}
+	if zero.Equal(newPoly(field, 1)) {
+		t.Error("zero equals one")
+	}
+
+	// The same indices over another field give a different polynomial
+	gf16, err := gfpn.NewField(2, 4, []int{1, 1, 0, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(16): %v", err)
+	}
+	if p.Equal(newPoly(gf16, 3, 0, 7)) {
+		t.Error("polynomials over different fields compare equal")
+	}
+	if empty.Equal(NewPolynomial(gf16, nil)) {
+		t.Error("zero polynomials over different fields compare equal")
+	}
+}
+
+func TestExtendedGCD(t *testing.T) {
+	field := qrField(t)
+	alpha := field.Primitive()
+
+	// linear returns x - α^k
+	linear := func(k int) Polynomial {
+		return NewPolynomial(field, []gfpn.Element{alpha.Pow(k), field.One()})
+	}
+	product := func(factors ...Polynomial) Polynomial {
+		result := NewPolynomial(field, []gfpn.Element{field.One()})
+		for _, f := range factors {
+			result = Multiply(result, f)
+		}
+		return result
+	}
+	zero := NewPolynomial(field, nil)
+
+	tests := []struct {
+		name string
+		a, b Polynomial
+		want Polynomial
+	}{
+		{"coprime", product(linear(1), linear(2)), product(linear(3), linear(4), linear(5)), newPoly(field, 1)},
+		{"common factor", product(linear(1), linear(2), linear(7)), product(linear(1), linear(7), linear(9)),
+			product(linear(1), linear(7))},
+		{"non-monic multiple", ScalarMultiply(alpha.Pow(40), product(linear(3), linear(6))), linear(6), linear(6)},
+		{"b divides a", product(linear(3), linear(6)), linear(3), linear(3)},
+		{"b is zero", ScalarMultiply(alpha.Pow(9), linear(2)), zero, linear(2)},
+		{"both zero", zero, zero, zero},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, s, tc := ExtendedGCD(tt.a, tt.b)
+			if !g.Equal(tt.want) {
+				t.Errorf("gcd = %s, want %s", g, tt.want)
+			}
+			if got := GCD(tt.a, tt.b); !got.Equal(tt.want) {
+				t.Errorf("GCD = %s, want %s", got, tt.want)
+			}
+			if combination := Add(Multiply(s, tt.a), Multiply(tc, tt.b)); !combination.Equal(g) {
+				t.Errorf("s·a + t·b = %s, want %s (s = %s, t = %s)", combination, g, s, tc)
+			}
+		})
+	}
+}
+
+func TestRoots(t *testing.T) {
+	// GF(8) = GF(2)[x]/(x^3 + x + 1)
+	gf8, err := gfpn.NewField(2, 3, []int{1, 1, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(8): %v", err)
+	}
+	one := gf8.One()
+	alpha := gf8.Primitive()
+
+	// x^2 - 1 = (x - 1)^2 in characteristic 2, so 1 is its only root
+	minusOne := one.Negate()
+	roots := Roots(NewPolynomial(gf8, []gfpn.Element{minusOne, gf8.Zero(), one}))
+	if len(roots) != 1 || !roots[0].Equal(one) {
+		t.Errorf("roots of x^2 - 1 = %v, want [1]", testutil.ElementsToStrings(roots))
+	}
+
+	// α^3·x + α^5 has the single root α^5/α^3 = α^2 (char 2, so minus is plus)
+	roots = Roots(NewPolynomial(gf8, []gfpn.Element{alpha.Pow(5), alpha.Pow(3)}))
+	if len(roots) != 1 || !roots[0].Equal(alpha.Pow(2)) {
+		t.Errorf("roots of α^3·x + α^5 = %v, want [α^2]", testutil.ElementsToStrings(roots))
+	}
+
+	// x^3 + x + 1 has α and its conjugates α^2, α^4 as roots
+	roots = Roots(newPoly(gf8, 1, 1, 0, 1))
+	want := map[string]bool{alpha.String(): true, alpha.Pow(2).String(): true, alpha.Pow(4).String(): true}
+	if len(roots) != 3 {
+		t.Fatalf("roots of x^3 + x + 1 = %v, want α, α^2, α^4", testutil.ElementsToStrings(roots))
+	}
+	for _, r := range roots {
+		if !want[r.String()] {
+			t.Errorf("unexpected root %s of x^3 + x + 1", r)
+		}
+	}
+
+	// x^8 - x vanishes on all of GF(8); a non-zero constant nowhere
+	if roots := Roots(Subtract(newPoly(gf8, 0, 0, 0, 0, 0, 0, 0, 0, 1), newPoly(gf8, 0, 1))); len(roots) != 8 {
+		t.Errorf("x^8 - x has %d roots, want 8", len(roots))
+	}
+	if roots := Roots(newPoly(gf8, 5)); len(roots) != 0 {
+		t.Errorf("constant has roots %v", testutil.ElementsToStrings(roots))
+	}
+	if roots := Roots(NewPolynomial(gf8, nil)); len(roots) != 8 {
+		t.Errorf("zero polynomial has %d roots, want 8", len(roots))
+	}
+}
+
+func TestCompose(t *testing.T) {
+	field := qrField(t)
+	x := newPoly(field, 0, 1)
+	rng := rand.New(rand.NewSource(3))
+
+	randomPoly := func(maxDegree int) Polynomial {
+		indices := make([]int, 1+rng.Intn(maxDegree+1))
+		for i := range indices {
+			indices[i] = rng.Intn(256)
+		}
+		indices[len(indices)-1] = 1 + rng.Intn(255)
+		return newPoly(field, indices...)
+	}
+
+	for trial := 0; trial < 20; trial++ {
+		p, q := randomPoly(5), randomPoly(4)
+
+		if got := Compose(p, x); !got.Equal(p) {
+			t.Errorf("p(x) = %s, want %s", got, p)
+		}
+		if got := Compose(x, q); !got.Equal(q) {
+			t.Errorf("x∘q = %s, want %s", got, q)
+		}
+
+		r := Compose(p, q)
+		if p.Degree() > 0 && q.Degree() > 0 && r.Degree() != p.Degree()*q.Degree() {
+			t.Errorf("deg p(q) = %d, want %d·%d", r.Degree(), p.Degree(), q.Degree())
+		}
+		for _, point := range []gfpn.Element{field.Zero(), field.One(), field.Element(1 + rng.Intn(255))} {
+			if got, want := r.Evaluate(point), p.Evaluate(q.Evaluate(point)); !got.Equal(want) {
+				t.Errorf("p(q(%s)) = %s, want %s", point, got, want)
+			}
+		}
+	}
+
+	// Constant inner polynomial gives the constant p(c); zero outer polynomial gives zero
+	p := newPoly(field, 4, 9, 17)
+	c := newPoly(field, 30)
+	if got, want := Compose(p, c), NewPolynomial(field, []gfpn.Element{p.Evaluate(field.Element(30))}); !got.Equal(want) {
+		t.Errorf("p(c) = %s, want %s", got, want)
+	}
+	if got := Compose(NewPolynomial(field, nil), p); !got.IsZero() {
+		t.Errorf("0∘p = %s, want 0", got)
+	}
+	if got := Compose(newPoly(field, 12), p); !got.Equal(newPoly(field, 12)) {
+		t.Errorf("constant∘p = %s, want the constant", got)
+	}
+}
+
+func TestMonic(t *testing.T) {
+	field := qrField(t)
+	one := field.One()
+
+	p := newPoly(field, 7, 0, 30, 100)
+	if lead := p.LeadingCoefficient(); !lead.Equal(field.Element(100)) {
+		t.Errorf("leading coefficient = %s, want %s", lead, field.Element(100))
+	}
+
+	monic := p.Monic()
+	if !monic.LeadingCoefficient().Equal(one) {
+		t.Errorf("Monic() has leading coefficient %s, want 1", monic.LeadingCoefficient())
+	}
+	if monic.Degree() != p.Degree() {
+		t.Errorf("Monic() has degree %d, want %d", monic.Degree(), p.Degree())
+	}
+	if scaled := ScalarMultiply(p.LeadingCoefficient(), monic); !scaled.Equal(p) {
+		t.Errorf("lead·Monic() = %s, want %s", scaled, p)
+	}
+	if again := monic.Monic(); !again.Equal(monic) {
+		t.Errorf("Monic() of a monic polynomial = %s, want %s", again, monic)
+	}
+
+	zero := NewPolynomial(field, nil)
+	if !zero.LeadingCoefficient().IsZero() {
+		t.Errorf("leading coefficient of zero = %s, want 0", zero.LeadingCoefficient())
+	}
+	if !zero.Monic().IsZero() {
+		t.Errorf("Monic() of zero = %s, want 0", zero.Monic())
+	}
+}
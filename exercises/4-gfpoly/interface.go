@@ -11,15 +11,39 @@ type Polynomial interface {
 	// Coefficients returns the polynomial coefficients from lowest to highest degree
 	Coefficients() []gfpn.Element
 
+	// CoefficientsPadded returns the coefficients zero-padded at the high end to exactly length entries
+	CoefficientsPadded(length int) []gfpn.Element
+
 	// Degree returns the degree of the polynomial (-1 for zero polynomial)
 	Degree() int
 
 	// Evaluate evaluates the polynomial at a given point
 	Evaluate(x gfpn.Element) gfpn.Element
 
+	// EvaluateWithDerivative evaluates the polynomial and its formal derivative at a given point
+	EvaluateWithDerivative(x gfpn.Element) (value, derivative gfpn.Element)
+
 	// IsZero returns true if this is the zero polynomial
 	IsZero() bool
 
 	// Field returns the underlying field
 	Field() gfpn.Field
+
+	// Shift multiplies the polynomial by x^k
+	Shift(k int) Polynomial
+
+	// Truncate drops all terms of degree greater than maxDegree
+	Truncate(maxDegree int) Polynomial
+
+	// LeadingCoefficient returns the coefficient of the highest power of x (zero for the zero polynomial)
+	LeadingCoefficient() gfpn.Element
+
+	// Monic returns the polynomial divided by its leading coefficient (zero stays zero)
+	Monic() Polynomial
+
+	// Equal reports whether two polynomials are over the same field with the same coefficients
+	Equal(other Polynomial) bool
+
+	// String renders the polynomial from highest to lowest degree, e.g. "011·x^2 + x + 111"
+	String() string
 }
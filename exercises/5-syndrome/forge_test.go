@@ -8,6 +8,7 @@ import (
 
 	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
 	"github.com/jalphad/abstract_algebra/testcases/syndrome"
+	"github.com/jalphad/abstract_algebra/testcases/testutil"
 
 	v1 "github.com/jalphad/testforge/proto"
 	"google.golang.org/grpc"
@@ -55,12 +56,9 @@ func TestSyndromeImplementation(t *testing.T) {
 	generatorRoot := field.Element(input.GeneratorRootIdx)
 
 	// Calculate syndromes using student implementation
-	syndromes := CalculateSyndromes(field, input.Received, input.NumECSymbols, generatorRoot)
-
-	// Convert syndromes to strings
-	syndromeStrings := make([]string, len(syndromes))
-	for i, s := range syndromes {
-		syndromeStrings[i] = s.String()
+	syndromes, err := CalculateSyndromes(field, input.Received, input.NumECSymbols, generatorRoot)
+	if err != nil {
+		t.Fatalf("Failed to calculate syndromes: %v", err)
 	}
 
 	// Check if errors exist
@@ -68,7 +66,7 @@ func TestSyndromeImplementation(t *testing.T) {
 
 	// Create response
 	response := syndrome.SyndromeTestResponse{
-		Syndromes: syndromeStrings,
+		Syndromes: testutil.ElementsToStrings(syndromes),
 		HasErrors: hasErrors,
 	}
 
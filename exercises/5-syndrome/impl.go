@@ -1,38 +1,153 @@
 package syndrome
 
 import (
+	"errors"
+
 	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
 )
 
+// ErrEmptyCodeword is returned when syndromes are requested for a received word of length zero
+//
+// The evaluation of an empty polynomial is zero at every point, which would read as
+// "no errors" even though there is nothing to check.
+var ErrEmptyCodeword = errors.New("empty received codeword")
+
 // CalculateSyndromes computes the syndrome values for a received codeword
 // This is the first step in Reed-Solomon decoding
 //
 // Parameters:
 //   - field: The finite field GF(p^n) over which the code is defined
-//   - received: The received codeword as a slice of bytes (each byte represents a field element index)
+//   - received: The received codeword as a slice of bytes (each byte represents a field element index),
+//     highest-degree coefficient first as in QR codes: received[0] is the coefficient of x^(n-1)
 //   - numECSymbols: The number of error correction symbols (t in a t-error correcting code means 2t EC symbols)
 //   - generatorRoot: The root used to generate the Reed-Solomon code (typically α, the primitive element)
 //
 // Returns:
 //   - A slice of syndrome values [S_0, S_1, ..., S_{2t-1}]
 //   - If all syndromes are zero, the codeword has no detectable errors
+//   - ErrEmptyCodeword if received is empty
 //
 // Mathematical background:
 //
 //	For a received polynomial r(x) = c(x) + e(x) where c(x) is the codeword and e(x) is the error,
 //	the syndrome S_i = r(α^i) for i = 0, 1, ..., 2t-1
 //	If there are no errors, e(x) = 0, and all syndromes will be zero.
+//
+// The evaluation points start at generatorRoot^0 as in QR codes, see CalculateSyndromesFCR
+// for codes whose generator polynomial has a different first consecutive root.
 func CalculateSyndromes(
 	field gfpn.Field,
 	received []byte,
 	numECSymbols int,
 	generatorRoot gfpn.Element,
-) []gfpn.Element {
-	panic("not implemented")
+) ([]gfpn.Element, error) {
+	return CalculateSyndromesFCR(field, received, numECSymbols, generatorRoot, 0)
+}
+
+// CalculateSyndromesFCR computes the syndromes of a received codeword for a Reed-Solomon
+// code whose generator polynomial has the first consecutive root generatorRoot^firstRoot
+//
+// With b = firstRoot the generator is g(x) = (x - α^b)(x - α^(b+1))···(x - α^(b+numECSymbols-1))
+// and the syndromes are S_i = r(α^(b+i)). QR codes use b = 0 (CalculateSyndromes);
+// many other codes and textbooks use b = 1.
+//
+// Parameters:
+//   - field, received, numECSymbols, generatorRoot: as for CalculateSyndromes
+//   - firstRoot: The exponent b of the first consecutive root of the generator polynomial
+//
+// Returns:
+//   - A slice of syndrome values [S_0, S_1, ..., S_{numECSymbols-1}] with S_i = r(generatorRoot^(b+i))
+//   - ErrEmptyCodeword if received is empty
+//
+// Interaction with Forney's algorithm:
+//
+//	An error of magnitude Y at location X contributes Y·X^(b+i) to S_i, i.e. Y·X^b
+//	where b = 0 would give Y. The error locator Λ(x) found by Berlekamp-Massey, and
+//	with it Chien search, is the same for every b. The evaluator Ω(x) = S(x)·Λ(x)
+//	mod x^(2t) however carries the extra X^b, so Forney's formula becomes
+//	Y = -X^(1-b)·Ω(X^(-1)) / Λ'(X^(-1)): each magnitude gains a factor X^(-b).
+func CalculateSyndromesFCR(
+	field gfpn.Field,
+	received []byte,
+	numECSymbols int,
+	generatorRoot gfpn.Element,
+	firstRoot int,
+) ([]gfpn.Element, error) {
+	if len(received) == 0 {
+		return nil, ErrEmptyCodeword
+	}
+
+	coefficients := make([]gfpn.Element, len(received))
+	for i, b := range received {
+		coefficients[i] = field.Element(int(b))
+	}
+	return CalculateSyndromesElementsFCR(field, coefficients, numECSymbols, generatorRoot, firstRoot)
+}
+
+// CalculateSyndromesElements computes the syndromes of a received word given as field elements
+//
+// This is CalculateSyndromes without the byte encoding, for codes whose symbols
+// do not fit the byte-as-index convention, e.g. over GF(3^3) or GF(2^4). The
+// coefficient order is the same: received[0] is the coefficient of x^(n-1).
+//
+// Parameters:
+//   - field: The finite field GF(p^n) over which the code is defined
+//   - received: The received codeword, highest-degree coefficient first
+//   - numECSymbols: The number of error correction symbols
+//   - generatorRoot: The root used to generate the Reed-Solomon code
+//
+// Returns:
+//   - A slice of syndrome values [S_0, S_1, ..., S_{numECSymbols-1}] with S_i = r(generatorRoot^i)
+//   - ErrEmptyCodeword if received is empty
+func CalculateSyndromesElements(
+	field gfpn.Field,
+	received []gfpn.Element,
+	numECSymbols int,
+	generatorRoot gfpn.Element,
+) ([]gfpn.Element, error) {
+	return CalculateSyndromesElementsFCR(field, received, numECSymbols, generatorRoot, 0)
+}
+
+// CalculateSyndromesElementsFCR is CalculateSyndromesFCR for a received word given as field elements
+//
+// Returns [S_0, ..., S_{numECSymbols-1}] with S_i = r(generatorRoot^(firstRoot+i)),
+// or ErrEmptyCodeword if received is empty.
+func CalculateSyndromesElementsFCR(
+	field gfpn.Field,
+	received []gfpn.Element,
+	numECSymbols int,
+	generatorRoot gfpn.Element,
+	firstRoot int,
+) ([]gfpn.Element, error) {
+	if len(received) == 0 {
+		return nil, ErrEmptyCodeword
+	}
+
+	syndromes := make([]gfpn.Element, numECSymbols)
+	point := generatorRoot.Pow(firstRoot)
+	for i := range syndromes {
+		// Horner's method from the highest-degree coefficient down:
+		// r(p) = (···(r_(n-1)·p + r_(n-2))·p + ···)·p + r_0
+		value := field.Zero()
+		for _, c := range received {
+			value = field.Add(field.Mul(value, point), c)
+		}
+		syndromes[i] = value
+
+		// Next evaluation point: generatorRoot^(firstRoot+i+1)
+		point = field.Mul(point, generatorRoot)
+	}
+
+	return syndromes, nil
 }
 
 // HasErrors checks if any syndromes are non-zero
 // Returns true if errors are detected, false otherwise
 func HasErrors(syndromes []gfpn.Element) bool {
-	panic("not implemented")
+	for _, s := range syndromes {
+		if !s.IsZero() {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,253 @@
+package syndrome
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
+	"github.com/jalphad/abstract_algebra/exercises/4-gfpoly"
+)
+
+func TestCalculateSyndromes_EmptyCodeword(t *testing.T) {
+	field := qrField(t)
+
+	syndromes, err := CalculateSyndromes(field, []byte{}, 4, field.Primitive())
+	if !errors.Is(err, ErrEmptyCodeword) {
+		t.Fatalf("err = %v, want ErrEmptyCodeword", err)
+	}
+	if syndromes != nil {
+		t.Errorf("syndromes = %v, want nil", syndromes)
+	}
+}
+
+// qrField creates GF(256) with the QR code polynomial x^8 + x^4 + x^3 + x^2 + 1
+func qrField(t *testing.T) gfpn.Field {
+	t.Helper()
+	field, err := gfpn.NewField(2, 8, []int{1, 0, 1, 1, 1, 0, 0, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(256): %v", err)
+	}
+	return field
+}
+
+// encodeElements returns the systematic Reed-Solomon codeword for message with numEC
+// check symbols, both with the highest-degree coefficient first
+func encodeElements(field gfpn.Field, message []gfpn.Element, numEC int, root gfpn.Element) []gfpn.Element {
+	// g(x) = (x - root^0)(x - root^1)···(x - root^(numEC-1))
+	generator := gfpoly.NewPolynomial(field, []gfpn.Element{field.One()})
+	for i := 0; i < numEC; i++ {
+		factor := gfpoly.NewPolynomial(field, []gfpn.Element{root.Pow(i).Negate(), field.One()})
+		generator = gfpoly.Multiply(generator, factor)
+	}
+
+	// c(x) = m(x)·x^numEC - (m(x)·x^numEC mod g(x))
+	coeffs := make([]gfpn.Element, len(message))
+	for i, m := range message {
+		coeffs[len(message)-1-i] = m
+	}
+	shifted := gfpoly.NewPolynomial(field, coeffs).Shift(numEC)
+	codeword := gfpoly.Subtract(shifted, gfpoly.Mod(shifted, generator))
+
+	n := len(message) + numEC
+	received := make([]gfpn.Element, n)
+	for i, c := range codeword.CoefficientsPadded(n) {
+		received[n-1-i] = c
+	}
+	return received
+}
+
+// encode is encodeElements for messages given as element indices (see gfpn.Field.Element)
+func encode(field gfpn.Field, message []byte, numEC int, root gfpn.Element) []byte {
+	elements := make([]gfpn.Element, len(message))
+	for i, b := range message {
+		elements[i] = field.Element(int(b))
+	}
+
+	codeword := encodeElements(field, elements, numEC, root)
+	received := make([]byte, len(codeword))
+	for i, c := range codeword {
+		if power, err := field.Log(c); err == nil {
+			received[i] = byte(power + 1)
+		}
+	}
+	return received
+}
+
+func TestCalculateSyndromes(t *testing.T) {
+	field := qrField(t)
+	alpha := field.Primitive()
+	message := []byte{0x40, 0x54, 0x86, 0x56, 0xC6, 0xC6, 0xF0}
+	received := encode(field, message, 10, alpha)
+
+	// A codeword is divisible by g(x), so it vanishes at every root α^i
+	syndromes, err := CalculateSyndromes(field, received, 10, alpha)
+	if err != nil {
+		t.Fatalf("CalculateSyndromes returned error: %v", err)
+	}
+	if len(syndromes) != 10 {
+		t.Fatalf("got %d syndromes, want 10", len(syndromes))
+	}
+	if HasErrors(syndromes) {
+		t.Errorf("clean codeword has syndromes %v", syndromes)
+	}
+
+	// A single error e at x^j gives S_i = e·(α^j)^i, non-zero for every i
+	position := 4
+	index := len(received) - 1 - position
+	corrupted := append([]byte{}, received...)
+	corrupted[index] ^= 0x2B
+	syndromes, err = CalculateSyndromes(field, corrupted, 10, alpha)
+	if err != nil {
+		t.Fatalf("CalculateSyndromes returned error: %v", err)
+	}
+	if !HasErrors(syndromes) {
+		t.Fatal("corrupted codeword has all syndromes zero")
+	}
+
+	magnitude := field.Sub(field.Element(int(corrupted[index])), field.Element(int(received[index])))
+	for i, s := range syndromes {
+		if want := magnitude.Mul(alpha.Pow(position * i)); !s.Equal(want) {
+			t.Errorf("S_%d = %s, want e·X^%d = %s", i, s, i, want)
+		}
+	}
+}
+
+func TestHasErrors(t *testing.T) {
+	field := qrField(t)
+	zero := field.Zero()
+
+	if HasErrors(nil) {
+		t.Error("no syndromes reported as errors")
+	}
+	if HasErrors([]gfpn.Element{zero, zero, zero}) {
+		t.Error("all-zero syndromes reported as errors")
+	}
+	if !HasErrors([]gfpn.Element{zero, zero, field.Element(7)}) {
+		t.Error("a non-zero syndrome was not reported")
+	}
+}
+
+func TestCalculateSyndromesElements(t *testing.T) {
+	// GF(27) = GF(3)[x]/(x^3 + 2x + 1): symbols are not bytes, and -1 ≠ 1
+	field, err := gfpn.NewField(3, 3, []int{1, 2, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(27): %v", err)
+	}
+	alpha := field.Primitive()
+
+	message := []gfpn.Element{field.Element(5), field.Element(0), field.Element(26), field.Element(13), field.One()}
+	received := encodeElements(field, message, 6, alpha)
+
+	syndromes, err := CalculateSyndromesElements(field, received, 6, alpha)
+	if err != nil {
+		t.Fatalf("CalculateSyndromesElements returned error: %v", err)
+	}
+	if len(syndromes) != 6 || HasErrors(syndromes) {
+		t.Errorf("clean GF(27) codeword has syndromes %v", syndromes)
+	}
+
+	// Adding 2·x^3 shifts every syndrome by 2·α^(3i)
+	corrupted := append([]gfpn.Element{}, received...)
+	two := field.Add(field.One(), field.One())
+	corrupted[len(corrupted)-1-3] = corrupted[len(corrupted)-1-3].Add(two)
+	syndromes, err = CalculateSyndromesElements(field, corrupted, 6, alpha)
+	if err != nil {
+		t.Fatalf("CalculateSyndromesElements returned error: %v", err)
+	}
+	for i, s := range syndromes {
+		if want := two.Mul(alpha.Pow(3 * i)); !s.Equal(want) {
+			t.Errorf("S_%d = %s, want 2·α^%d = %s", i, s, 3*i, want)
+		}
+	}
+
+	if _, err := CalculateSyndromesElements(field, nil, 6, alpha); !errors.Is(err, ErrEmptyCodeword) {
+		t.Errorf("err = %v, want ErrEmptyCodeword", err)
+	}
+}
+
+func TestCalculateSyndromes_MatchesElements(t *testing.T) {
+	field := qrField(t)
+	received := []byte{0x12, 0x00, 0xFF, 0x80, 0x01, 0x7A}
+
+	elements := make([]gfpn.Element, len(received))
+	for i, b := range received {
+		elements[i] = field.Element(int(b))
+	}
+
+	fromBytes, err := CalculateSyndromes(field, received, 4, field.Primitive())
+	if err != nil {
+		t.Fatalf("CalculateSyndromes returned error: %v", err)
+	}
+	fromElements, err := CalculateSyndromesElements(field, elements, 4, field.Primitive())
+	if err != nil {
+		t.Fatalf("CalculateSyndromesElements returned error: %v", err)
+	}
+	for i := range fromBytes {
+		if !fromBytes[i].Equal(fromElements[i]) {
+			t.Errorf("S_%d: bytes give %s, elements give %s", i, fromBytes[i], fromElements[i])
+		}
+	}
+}
+
+func TestCalculateSyndromesFCR(t *testing.T) {
+	field := qrField(t)
+	alpha := field.Primitive()
+
+	// Two errors on a valid codeword: 0x5A at x^7 and 0x03 at x^2
+	received := encode(field, []byte{0x40, 0xD2, 0x75, 0x47, 0x76, 0x17}, 6, alpha)
+	n := len(received)
+	received[n-1-7] ^= 0x5A
+	received[n-1-2] ^= 0x03
+
+	fcr0, err := CalculateSyndromesFCR(field, received, 7, alpha, 0)
+	if err != nil {
+		t.Fatalf("CalculateSyndromesFCR(b=0) returned error: %v", err)
+	}
+	fcr1, err := CalculateSyndromesFCR(field, received, 6, alpha, 1)
+	if err != nil {
+		t.Fatalf("CalculateSyndromesFCR(b=1) returned error: %v", err)
+	}
+
+	// b = 0 is the default
+	plain, err := CalculateSyndromes(field, received, 7, alpha)
+	if err != nil {
+		t.Fatalf("CalculateSyndromes returned error: %v", err)
+	}
+	for i := range plain {
+		if !plain[i].Equal(fcr0[i]) {
+			t.Errorf("S_%d: CalculateSyndromes gives %s, b=0 gives %s", i, plain[i], fcr0[i])
+		}
+	}
+
+	// Both evaluate r at consecutive powers of α, one step apart
+	for i := range fcr1 {
+		if !fcr1[i].Equal(fcr0[i+1]) {
+			t.Errorf("S_%d with b=1 = %s, want S_%d with b=0 = %s", i, fcr1[i], i+1, fcr0[i+1])
+		}
+	}
+}
+
+func TestCalculateSyndromesFCR_SingleErrorFactor(t *testing.T) {
+	field := qrField(t)
+	alpha := field.Primitive()
+
+	// The error pattern alone: outside the generator's roots a codeword would
+	// contribute to the shifted syndromes as well
+	received := make([]byte, 8)
+	received[len(received)-1-5] = 0x9E
+
+	// A single error at location X = α^5 contributes an extra X^b to every syndrome
+	x := alpha.Pow(5)
+	fcr0, _ := CalculateSyndromesFCR(field, received, 4, alpha, 0)
+	for _, b := range []int{1, 2, -1} {
+		fcrB, err := CalculateSyndromesFCR(field, received, 4, alpha, b)
+		if err != nil {
+			t.Fatalf("CalculateSyndromesFCR(b=%d) returned error: %v", b, err)
+		}
+		for i := range fcrB {
+			if want := fcr0[i].Mul(x.Pow(b)); !fcrB[i].Equal(want) {
+				t.Errorf("b=%d: S_%d = %s, want X^%d·S_%d = %s", b, i, fcrB[i], b, i, want)
+			}
+		}
+	}
+}
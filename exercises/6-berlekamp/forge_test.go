@@ -8,6 +8,7 @@ import (
 
 	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
 	"github.com/jalphad/abstract_algebra/testcases/berlekamp"
+	"github.com/jalphad/abstract_algebra/testcases/testutil"
 
 	v1 "github.com/jalphad/testforge/proto"
 	"google.golang.org/grpc"
@@ -52,40 +53,17 @@ func TestBerlekampMasseyImplementation(t *testing.T) {
 	}
 
 	// Parse syndromes from strings to elements
-	syndromes := make([]gfpn.Element, len(input.Syndromes))
-	order := 1
-	for i := 0; i < input.Degree; i++ {
-		order *= int(input.Prime)
-	}
-
-	for i, s := range input.Syndromes {
-		found := false
-		for j := 0; j < order; j++ {
-			elem := field.Element(j)
-			if elem.String() == s {
-				syndromes[i] = elem
-				found = true
-				break
-			}
-		}
-		if !found {
-			t.Fatalf("Failed to parse syndrome: %s", s)
-		}
+	syndromes, err := testutil.ParseElements(field, input.Syndromes)
+	if err != nil {
+		t.Fatalf("Failed to parse syndromes: %v", err)
 	}
 
 	// Run Berlekamp-Massey using student implementation
 	lambda := BerlekampMassey(field, syndromes)
 
-	// Convert to response format
-	coeffs := lambda.Coefficients()
-	errorLocator := make([]string, len(coeffs))
-	for i, c := range coeffs {
-		errorLocator[i] = c.String()
-	}
-
 	// Create response
 	response := berlekamp.BerlekampTestResponse{
-		ErrorLocator: errorLocator,
+		ErrorLocator: testutil.ElementsToStrings(lambda.Coefficients()),
 		Degree:       lambda.Degree(),
 	}
 
@@ -18,6 +18,170 @@ import (
 //   - The error locator polynomial of minimal degree
 //
 // Algorithm: Berlekamp-Massey iterative algorithm
+//
+// The locator update Λ(x) ← Λ(x) - (d/b)·x^m·B(x) is a single call to
+// gfpoly.ScaleShiftSubtract, which avoids allocating intermediate polynomials.
 func BerlekampMassey(field gfpn.Field, syndromes []gfpn.Element) gfpoly.Polynomial {
-	panic("TODO: implement BerlekampMassey")
+	one := gfpoly.NewPolynomial(field, []gfpn.Element{field.One()})
+
+	// lambda is the current connection polynomial Λ(x) and prev the one B(x) from
+	// before the last length change, with its discrepancy prevDiscrepancy. length
+	// is the current LFSR length L and shift the number of steps m since B was saved.
+	lambda := one
+	prev := one
+	prevDiscrepancy := field.One()
+	length := 0
+	shift := 1
+
+	for n := range syndromes {
+		// Discrepancy d = S_n + Λ_1·S_(n-1) + ... + Λ_L·S_(n-L)
+		coeffs := lambda.CoefficientsPadded(length + 1)
+		discrepancy := syndromes[n]
+		for i := 1; i <= length; i++ {
+			discrepancy = field.Add(discrepancy, field.Mul(coeffs[i], syndromes[n-i]))
+		}
+
+		if discrepancy.IsZero() {
+			shift++
+			continue
+		}
+
+		// Λ(x) ← Λ(x) - (d/b)·x^m·B(x)
+		scale := field.Div(discrepancy, prevDiscrepancy)
+		next := gfpoly.ScaleShiftSubtract(lambda, prev, scale, shift)
+
+		if 2*length <= n {
+			// The LFSR has to grow: the old Λ becomes the new B
+			prev = lambda
+			prevDiscrepancy = discrepancy
+			length = n + 1 - length
+			shift = 1
+		} else {
+			shift++
+		}
+		lambda = next
+	}
+
+	return lambda
+}
+
+// BerlekampMasseyWithErasures computes the error locator for a received word with known erasures
+//
+// An erasure is a symbol whose position is known but whose value is not, so it costs
+// one syndrome instead of two: with μ erasures and ν errors, 2t syndromes suffice as
+// long as 2ν + μ ≤ 2t. The erasures are first folded out of the syndromes:
+//
+//	Γ(x) = Π (1 - α^(j_k)·x)           erasure locator over the positions j_k
+//	T(x) = Γ(x)·S(x) mod x^(2t)        modified (Forney) syndromes
+//
+// T_μ, ..., T_(2t-1) depend on the errors alone and are fed to BerlekampMassey.
+// The errata locator, whose roots cover errors and erasures, is then Λ(x)·Γ(x).
+//
+// Parameters:
+//   - field: The finite field GF(p^n) over which the code is defined
+//   - syndromes: The syndrome sequence [S_0, S_1, ..., S_{2t-1}] with S_i = r(α^i)
+//   - erasurePositions: Distinct erasure positions j_k, where position j is the coefficient of x^j
+//
+// Returns:
+//   - lambda: The error locator polynomial Λ(x) of minimal degree, 1 if there are no errors
+//     or no syndromes are left after the erasures
+//   - erasureLocator: The erasure locator polynomial Γ(x)
+//
+// Example:
+//
+//	// 4 syndromes: 2 erasures and 1 error, more than the t = 2 errors BM alone corrects
+//	lambda, gamma := BerlekampMasseyWithErasures(field, syndromes, []int{3, 9})
+//	errata := gfpoly.Multiply(lambda, gamma)
+func BerlekampMasseyWithErasures(
+	field gfpn.Field,
+	syndromes []gfpn.Element,
+	erasurePositions []int,
+) (lambda gfpoly.Polynomial, erasureLocator gfpoly.Polynomial) {
+	one := gfpoly.NewPolynomial(field, []gfpn.Element{field.One()})
+
+	// Γ(x) = Π (1 - X_k·x) with X_k = α^(j_k)
+	erasureLocator = one
+	alpha := field.Primitive()
+	for _, pos := range erasurePositions {
+		factor := gfpoly.NewPolynomial(field, []gfpn.Element{field.One(), alpha.Pow(pos).Negate()})
+		erasureLocator = gfpoly.Multiply(erasureLocator, factor)
+	}
+
+	numErasures := len(erasurePositions)
+	if numErasures >= len(syndromes) {
+		return one, erasureLocator
+	}
+
+	// T(x) = Γ(x)·S(x) mod x^(2t); the first μ coefficients still mix in the erasures
+	forneySyndromes := gfpoly.Multiply(erasureLocator, gfpoly.NewPolynomial(field, syndromes)).
+		CoefficientsPadded(len(syndromes) + numErasures)[:len(syndromes)]
+
+	return BerlekampMassey(field, forneySyndromes[numErasures:]), erasureLocator
+}
+
+// IsValidLocator reports whether lambda is a normalized error locator polynomial
+//
+// An error locator is Λ(x) = (1 - X_1·x)(1 - X_2·x)···(1 - X_ν·x), so its constant
+// term Λ(0) is always 1. Berlekamp-Massey starts from Λ(x) = 1 and only ever adds
+// multiples of x, so an output with any other constant term points to a bug. The
+// zero polynomial is never a valid locator.
+//
+// Parameters:
+//   - lambda: The candidate error locator polynomial
+//
+// Returns:
+//   - true if lambda is non-zero and Λ(0) equals the field's One
+func IsValidLocator(lambda gfpoly.Polynomial) bool {
+	if lambda == nil || lambda.IsZero() {
+		return false
+	}
+	return lambda.Coefficients()[0].Equal(lambda.Field().One())
+}
+
+// SolveKeyEquation solves the key equation with the Euclidean (Sugiyama) algorithm
+//
+// This is the alternative to BerlekampMassey: run the extended Euclidean algorithm on
+// a = x^2t and b = S(x) = S_0 + S_1·x + ... + S_{2t-1}·x^(2t-1), and stop at the first
+// remainder of degree < t. The Bézout identity U·x^2t + V·S = R then gives
+//
+//	V(x)·S(x) ≡ R(x) (mod x^2t)
+//
+// so V is the error locator and R the error evaluator, up to the scalar that makes
+// Λ(0) = 1. If V(0) is zero there is no such scalar; lambda is then returned
+// un-normalized and fails IsValidLocator, meaning the errors are uncorrectable.
+//
+// Parameters:
+//   - field: The finite field GF(p^n) over which the code is defined
+//   - syndromes: The syndrome sequence [S_0, S_1, ..., S_{2t-1}]
+//
+// Returns:
+//   - lambda: The error locator polynomial Λ(x)
+//   - omega: The error evaluator polynomial Ω(x)
+func SolveKeyEquation(field gfpn.Field, syndromes []gfpn.Element) (lambda, omega gfpoly.Polynomial) {
+	one := gfpoly.NewPolynomial(field, []gfpn.Element{field.One()})
+	s := gfpoly.NewPolynomial(field, syndromes)
+	if s.IsZero() {
+		return one, s
+	}
+
+	t := len(syndromes) / 2
+	if s.Degree() < t {
+		return one, s
+	}
+
+	a := one.Shift(len(syndromes))
+	for _, step := range gfpoly.EuclideanSteps(a, s) {
+		if step.Remainder.Degree() < t {
+			lambda, omega = step.V, step.Remainder
+			break
+		}
+	}
+
+	// Normalize so that Λ(0) = 1
+	lambda0 := lambda.Coefficients()[0]
+	if lambda0.IsZero() {
+		return lambda, omega
+	}
+	scale := lambda0.Inverse()
+	return gfpoly.ScalarMultiply(scale, lambda), gfpoly.ScalarMultiply(scale, omega)
 }
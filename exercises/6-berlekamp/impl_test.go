@@ -0,0 +1,253 @@
+package berlekamp
+
+import (
+	"testing"
+
+	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
+	"github.com/jalphad/abstract_algebra/exercises/4-gfpoly"
+	"github.com/jalphad/abstract_algebra/exercises/8-forney"
+	"github.com/jalphad/abstract_algebra/testcases/testutil"
+)
+
+func TestIsValidLocator(t *testing.T) {
+	field, err := gfpn.NewField(2, 8, []int{1, 0, 1, 1, 1, 0, 0, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(256): %v", err)
+	}
+
+	// Index 1 is the element 1 and index i > 1 is α^(i-1)
+	tests := []struct {
+		name    string
+		indices []int
+		want    bool
+	}{
+		{name: "normalized: 1 + α·x", indices: []int{1, 2}, want: true},
+		{name: "normalized: 1 + α^3·x + α^7·x^2", indices: []int{1, 4, 8}, want: true},
+		{name: "constant polynomial 1", indices: []int{1}, want: true},
+		{name: "un-normalized: α + α^2·x", indices: []int{2, 3}, want: false},
+		{name: "zero constant term: α·x", indices: []int{0, 2}, want: false},
+		{name: "zero polynomial", indices: []int{}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lambda := gfpoly.NewPolynomialFromIndices(field, tt.indices)
+			if got := IsValidLocator(lambda); got != tt.want {
+				t.Errorf("IsValidLocator() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSolveKeyEquation(t *testing.T) {
+	field, err := gfpn.NewField(2, 8, []int{1, 0, 1, 1, 1, 0, 0, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(256): %v", err)
+	}
+
+	pow := func(x gfpn.Element, n int) gfpn.Element {
+		result := field.One()
+		for i := 0; i < n; i++ {
+			result = field.Mul(result, x)
+		}
+		return result
+	}
+	alpha := field.Primitive()
+
+	// Errors of magnitude α^3 at position 2 and α^7 at position 5, 4 syndromes (t = 2)
+	errors := map[int]gfpn.Element{2: pow(alpha, 3), 5: pow(alpha, 7)}
+	syndromes := make([]gfpn.Element, 4)
+	for i := range syndromes {
+		syndromes[i] = field.Zero()
+		for pos, magnitude := range errors {
+			syndromes[i] = field.Add(syndromes[i], field.Mul(magnitude, pow(alpha, i*pos)))
+		}
+	}
+
+	lambda, omega := SolveKeyEquation(field, syndromes)
+	if !IsValidLocator(lambda) {
+		t.Fatalf("lambda = %v is not a valid locator", testutil.ElementsToStrings(lambda.Coefficients()))
+	}
+	if lambda.Degree() != len(errors) {
+		t.Errorf("deg Λ = %d, want %d", lambda.Degree(), len(errors))
+	}
+	if omega.Degree() >= len(syndromes)/2 {
+		t.Errorf("deg Ω = %d, want < %d", omega.Degree(), len(syndromes)/2)
+	}
+
+	// The roots of Λ are the inverse error locators α^(-pos)
+	for pos := range errors {
+		root := field.Div(field.One(), pow(alpha, pos))
+		if value := lambda.Evaluate(root); !value.IsZero() {
+			t.Errorf("Λ(α^-%d) = %s, want 0", pos, value)
+		}
+	}
+
+	// No errors: Λ = 1, Ω = 0
+	zeros := testutil.ElementsFromIndices(field, []int{0, 0, 0, 0})
+	lambda, omega = SolveKeyEquation(field, zeros)
+	if lambda.Degree() != 0 || !omega.IsZero() {
+		t.Errorf("zero syndromes: deg Λ = %d, deg Ω = %d, want 0 and -1", lambda.Degree(), omega.Degree())
+	}
+}
+
+func TestBerlekampMassey(t *testing.T) {
+	field, err := gfpn.NewField(2, 8, []int{1, 0, 1, 1, 1, 0, 0, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(256): %v", err)
+	}
+	alpha := field.Primitive()
+
+	// Error position -> magnitude as a power of α
+	tests := []struct {
+		name   string
+		errors map[int]int
+	}{
+		{name: "no errors", errors: map[int]int{}},
+		{name: "one error", errors: map[int]int{4: 9}},
+		{name: "two errors", errors: map[int]int{2: 3, 5: 7}},
+		{name: "two errors, one at x^0", errors: map[int]int{0: 200, 17: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// S_i = Σ Y_j·X_j^i with X_j = α^pos, 4 syndromes (t = 2)
+			syndromes := make([]gfpn.Element, 4)
+			for i := range syndromes {
+				syndromes[i] = field.Zero()
+				for pos, magnitude := range tt.errors {
+					syndromes[i] = syndromes[i].Add(alpha.Pow(magnitude).Mul(alpha.Pow(i * pos)))
+				}
+			}
+
+			// Λ(x) = Π (1 - X_j·x)
+			want := gfpoly.NewPolynomial(field, []gfpn.Element{field.One()})
+			for pos := range tt.errors {
+				factor := gfpoly.NewPolynomial(field, []gfpn.Element{field.One(), alpha.Pow(pos).Negate()})
+				want = gfpoly.Multiply(want, factor)
+			}
+
+			lambda := BerlekampMassey(field, syndromes)
+			if !lambda.Equal(want) {
+				t.Errorf("BerlekampMassey() = %s, want %s", lambda, want)
+			}
+			if !IsValidLocator(lambda) {
+				t.Errorf("BerlekampMassey() = %s is not a valid locator", lambda)
+			}
+
+			// Both key equation solvers find the same minimal locator
+			if fromEuclid, _ := SolveKeyEquation(field, syndromes); !lambda.Equal(fromEuclid) {
+				t.Errorf("BerlekampMassey() = %s, SolveKeyEquation() = %s", lambda, fromEuclid)
+			}
+		})
+	}
+}
+
+func TestBerlekampMassey_GF27(t *testing.T) {
+	// Odd characteristic: the update subtracts rather than adds
+	field, err := gfpn.NewField(3, 3, []int{1, 2, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(27): %v", err)
+	}
+	alpha := field.Primitive()
+	two := field.Add(field.One(), field.One())
+
+	// Errors of magnitude 2 at x^1 and α^5 at x^8, 4 syndromes
+	syndromes := make([]gfpn.Element, 4)
+	for i := range syndromes {
+		syndromes[i] = two.Mul(alpha.Pow(i)).Add(alpha.Pow(5).Mul(alpha.Pow(8 * i)))
+	}
+
+	lambda := BerlekampMassey(field, syndromes)
+	if lambda.Degree() != 2 || !IsValidLocator(lambda) {
+		t.Fatalf("BerlekampMassey() = %s, want a valid locator of degree 2", lambda)
+	}
+	for _, pos := range []int{1, 8} {
+		if value := lambda.Evaluate(alpha.Pow(-pos)); !value.IsZero() {
+			t.Errorf("Λ(α^-%d) = %s, want 0", pos, value)
+		}
+	}
+}
+
+func TestBerlekampMasseyWithErasures(t *testing.T) {
+	field, err := gfpn.NewField(2, 8, []int{1, 0, 1, 1, 1, 0, 0, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(256): %v", err)
+	}
+	alpha := field.Primitive()
+	const codewordLength = 20
+
+	// 4 syndromes (t = 2) against 3 or 4 errata: beyond what BerlekampMassey alone corrects
+	tests := []struct {
+		name      string
+		errata    map[int]int // position -> magnitude as a power of α
+		erasures  []int
+		numErrors int
+	}{
+		{name: "2 erasures, 1 error", errata: map[int]int{3: 10, 9: 77, 14: 201}, erasures: []int{3, 9}, numErrors: 1},
+		{name: "4 erasures", errata: map[int]int{0: 5, 6: 6, 7: 7, 19: 8}, erasures: []int{0, 6, 7, 19}, numErrors: 0},
+		{name: "erasure read correctly", errata: map[int]int{2: 40, 11: 41}, erasures: []int{2, 5}, numErrors: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The errata pattern e(x) and its syndromes S_i = e(α^i)
+			errata := make([]gfpn.Element, codewordLength)
+			for i := range errata {
+				errata[i] = field.Zero()
+			}
+			for pos, magnitude := range tt.errata {
+				errata[pos] = alpha.Pow(magnitude)
+			}
+			e := gfpoly.NewPolynomial(field, errata)
+			syndromes := make([]gfpn.Element, 4)
+			for i := range syndromes {
+				syndromes[i] = e.Evaluate(alpha.Pow(i))
+			}
+
+			lambda, gamma := BerlekampMasseyWithErasures(field, syndromes, tt.erasures)
+			if !IsValidLocator(lambda) || !IsValidLocator(gamma) {
+				t.Fatalf("Λ = %s, Γ = %s: not valid locators", lambda, gamma)
+			}
+			if lambda.Degree() != tt.numErrors {
+				t.Errorf("deg Λ = %d, want %d", lambda.Degree(), tt.numErrors)
+			}
+			if gamma.Degree() != len(tt.erasures) {
+				t.Errorf("deg Γ = %d, want %d", gamma.Degree(), len(tt.erasures))
+			}
+
+			// The roots of Ψ = Λ·Γ within the codeword are the errata positions
+			psi := gfpoly.Multiply(lambda, gamma)
+			var positions []int
+			for pos := 0; pos < codewordLength; pos++ {
+				if psi.Evaluate(alpha.Pow(-pos)).IsZero() {
+					positions = append(positions, pos)
+				}
+			}
+			if len(positions) != psi.Degree() {
+				t.Fatalf("Ψ = %s has roots at %v, want %d", psi, positions, psi.Degree())
+			}
+
+			// Forney's formula on Ψ recovers every magnitude, erasures included
+			omega := forney.ComputeOmega(field, syndromes, psi)
+			magnitudes := forney.ComputeErrorMagnitudes(field, psi, omega, positions)
+			for i, pos := range positions {
+				if !magnitudes[i].Equal(errata[pos]) {
+					t.Errorf("magnitude at %d = %s, want %s", pos, magnitudes[i], errata[pos])
+				}
+			}
+			for pos := range tt.errata {
+				if value := psi.Evaluate(alpha.Pow(-pos)); !value.IsZero() {
+					t.Errorf("Ψ(α^-%d) = %s, want 0", pos, value)
+				}
+			}
+		})
+	}
+
+	// Without erasures this is plain Berlekamp-Massey with Γ = 1
+	syndromes := testutil.ElementsFromIndices(field, []int{7, 0, 42, 3})
+	lambda, gamma := BerlekampMasseyWithErasures(field, syndromes, nil)
+	if gamma.Degree() != 0 || !lambda.Equal(BerlekampMassey(field, syndromes)) {
+		t.Errorf("no erasures: Λ = %s, Γ = %s", lambda, gamma)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
 	"github.com/jalphad/abstract_algebra/exercises/4-gfpoly"
 	"github.com/jalphad/abstract_algebra/testcases/chien"
+	"github.com/jalphad/abstract_algebra/testcases/testutil"
 
 	v1 "github.com/jalphad/testforge/proto"
 	"google.golang.org/grpc"
@@ -53,25 +54,9 @@ func TestChienSearchImplementation(t *testing.T) {
 	}
 
 	// Parse lambda coefficients from strings to elements
-	order := 1
-	for i := 0; i < input.Degree; i++ {
-		order *= int(input.Prime)
-	}
-
-	lambdaCoeffs := make([]gfpn.Element, len(input.LambdaCoeffs))
-	for i, s := range input.LambdaCoeffs {
-		found := false
-		for j := 0; j < order; j++ {
-			elem := field.Element(j)
-			if elem.String() == s {
-				lambdaCoeffs[i] = elem
-				found = true
-				break
-			}
-		}
-		if !found {
-			t.Fatalf("Failed to parse lambda coefficient: %s", s)
-		}
+	lambdaCoeffs, err := testutil.ParseElements(field, input.LambdaCoeffs)
+	if err != nil {
+		t.Fatalf("Failed to parse lambda coefficients: %v", err)
 	}
 
 	// Create lambda polynomial
@@ -1,10 +1,17 @@
 package chien
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
 	"github.com/jalphad/abstract_algebra/exercises/4-gfpoly"
 )
 
+// ErrRootCountMismatch is returned by ChienSearchChecked when the error locator does not
+// have as many roots inside the codeword as its degree
+var ErrRootCountMismatch = errors.New("error locator root count mismatch")
+
 // ChienSearch finds the roots of the error locator polynomial L(x)
 //
 // The Chien search algorithm systematically evaluates L(x) at all possible
@@ -33,5 +40,60 @@ import (
 // Returns:
 //   - A slice of error positions [j_1, j_2, ..., j_ν]
 func ChienSearch(field gfpn.Field, lambda gfpoly.Polynomial, codewordLength int) []int {
-	panic("not implemented")
+	coeffs := lambda.Coefficients()
+
+	// steps[i] = α^{-i}, the factor that moves term i from L_i·α^{-ij} to L_i·α^{-i(j+1)}
+	alphaInverse := field.Primitive().Inverse()
+	steps := make([]gfpn.Element, len(coeffs))
+	step := field.One()
+	for i := range steps {
+		steps[i] = step
+		step = field.Mul(step, alphaInverse)
+	}
+
+	terms := make([]gfpn.Element, len(coeffs))
+	copy(terms, coeffs)
+
+	positions := []int{}
+	for j := 0; j < codewordLength; j++ {
+		// Σ b_i = L(α^{-j})
+		sum := field.Zero()
+		for _, b := range terms {
+			sum = field.Add(sum, b)
+		}
+		if sum.IsZero() {
+			positions = append(positions, j)
+		}
+
+		for i := range terms {
+			terms[i] = field.Mul(terms[i], steps[i])
+		}
+	}
+
+	return positions
+}
+
+// ChienSearchChecked is ChienSearch with a consistency check on the roots found
+//
+// A locator of degree ν for ν correctable errors splits into ν distinct factors
+// (1 - α^j·x) with every j inside the codeword. Finding fewer roots means the locator
+// does not describe a correctable error pattern: some of its roots lie outside the
+// codeword, or in an extension field, which happens when there were more than t
+// errors. Correcting at the roots that were found would only produce garbage.
+//
+// Parameters:
+//   - field: The finite field GF(p^n) over which the code is defined
+//   - lambda: The error locator polynomial L(x)
+//   - codewordLength: Length of the codeword
+//
+// Returns:
+//   - The error positions found, also when the check fails
+//   - An error wrapping ErrRootCountMismatch if their number differs from deg(L)
+func ChienSearchChecked(field gfpn.Field, lambda gfpoly.Polynomial, codewordLength int) ([]int, error) {
+	positions := ChienSearch(field, lambda, codewordLength)
+	if len(positions) != lambda.Degree() {
+		return positions, fmt.Errorf("%w: found %d roots but Λ has degree %d",
+			ErrRootCountMismatch, len(positions), lambda.Degree())
+	}
+	return positions, nil
 }
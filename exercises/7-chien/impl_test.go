@@ -0,0 +1,99 @@
+package chien
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
+	"github.com/jalphad/abstract_algebra/exercises/4-gfpoly"
+)
+
+func TestChienSearch(t *testing.T) {
+	field, err := gfpn.NewField(2, 8, []int{1, 0, 1, 1, 1, 0, 0, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(256): %v", err)
+	}
+	alpha := field.Primitive()
+
+	tests := []struct {
+		name      string
+		positions []int // error positions in Λ
+		want      []int // positions found in a codeword of length 26
+	}{
+		{name: "no errors", positions: []int{}, want: []int{}},
+		{name: "one error", positions: []int{7}, want: []int{7}},
+		{name: "two errors", positions: []int{3, 12}, want: []int{3, 12}},
+		{name: "two errors at the ends", positions: []int{25, 0}, want: []int{0, 25}},
+		{name: "root outside the codeword", positions: []int{4, 40}, want: []int{4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Λ(x) = Π (1 - X_j·x) with error locators X_j = α^j
+			lambda := gfpoly.NewPolynomial(field, []gfpn.Element{field.One()})
+			for _, pos := range tt.positions {
+				factor := gfpoly.NewPolynomial(field, []gfpn.Element{field.One(), alpha.Pow(pos).Negate()})
+				lambda = gfpoly.Multiply(lambda, factor)
+			}
+
+			got := ChienSearch(field, lambda, 26)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChienSearch() = %v, want %v", got, tt.want)
+			}
+
+			// Each root found is the reciprocal of its error locator
+			for _, pos := range got {
+				root := alpha.Pow(pos).Inverse()
+				if value := lambda.Evaluate(root); !value.IsZero() {
+					t.Errorf("Λ(1/α^%d) = %s, want 0", pos, value)
+				}
+			}
+		})
+	}
+}
+
+func TestChienSearchChecked(t *testing.T) {
+	field, err := gfpn.NewField(2, 8, []int{1, 0, 1, 1, 1, 0, 0, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(256): %v", err)
+	}
+	alpha := field.Primitive()
+	locator := func(pos int) gfpoly.Polynomial {
+		return gfpoly.NewPolynomial(field, []gfpn.Element{field.One(), alpha.Pow(pos).Negate()})
+	}
+
+	// Consistent: two roots for degree 2
+	lambda := gfpoly.Multiply(locator(3), locator(9))
+	positions, err := ChienSearchChecked(field, lambda, 26)
+	if err != nil {
+		t.Fatalf("ChienSearchChecked() error = %v", err)
+	}
+	if !reflect.DeepEqual(positions, []int{3, 9}) {
+		t.Errorf("ChienSearchChecked() = %v, want [3 9]", positions)
+	}
+
+	tests := []struct {
+		name   string
+		lambda gfpoly.Polynomial
+		want   []int
+	}{
+		// α^-40 is not α^-j for any j in a codeword of length 26
+		{name: "root outside the codeword", lambda: gfpoly.Multiply(locator(4), locator(40)), want: []int{4}},
+		// x^2 + x + 1 is irreducible over GF(2) but splits in GF(256) with roots of
+		// order 3, i.e. at positions 85 and 170
+		{name: "roots only in a longer codeword", lambda: gfpoly.NewPolynomialFromIndices(field, []int{1, 1, 1}), want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			positions, err := ChienSearchChecked(field, tt.lambda, 26)
+			if !errors.Is(err, ErrRootCountMismatch) {
+				t.Fatalf("ChienSearchChecked(%s) error = %v, want ErrRootCountMismatch", tt.lambda, err)
+			}
+			if !reflect.DeepEqual(positions, tt.want) {
+				t.Errorf("ChienSearchChecked(%s) positions = %v, want %v", tt.lambda, positions, tt.want)
+			}
+		})
+	}
+}
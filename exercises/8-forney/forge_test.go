@@ -9,6 +9,7 @@ import (
 	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
 	"github.com/jalphad/abstract_algebra/exercises/4-gfpoly"
 	"github.com/jalphad/abstract_algebra/testcases/forney"
+	"github.com/jalphad/abstract_algebra/testcases/testutil"
 
 	v1 "github.com/jalphad/testforge/proto"
 	"google.golang.org/grpc"
@@ -52,40 +53,16 @@ func TestForneyImplementation(t *testing.T) {
 		t.Fatalf("Failed to create field: %v", err)
 	}
 
-	// Helper to parse element strings
-	order := 1
-	for i := 0; i < input.Degree; i++ {
-		order *= int(input.Prime)
-	}
-
-	parseElement := func(s string) (gfpn.Element, error) {
-		for j := 0; j < order; j++ {
-			elem := field.Element(j)
-			if elem.String() == s {
-				return elem, nil
-			}
-		}
-		return field.Zero(), err
-	}
-
 	// Parse syndromes
-	syndromes := make([]gfpn.Element, len(input.Syndromes))
-	for i, s := range input.Syndromes {
-		elem, err := parseElement(s)
-		if err != nil {
-			t.Fatalf("Failed to parse syndrome %d: %s", i, s)
-		}
-		syndromes[i] = elem
+	syndromes, err := testutil.ParseElements(field, input.Syndromes)
+	if err != nil {
+		t.Fatalf("Failed to parse syndromes: %v", err)
 	}
 
 	// Parse lambda coefficients
-	lambdaCoeffs := make([]gfpn.Element, len(input.LambdaCoeffs))
-	for i, s := range input.LambdaCoeffs {
-		elem, err := parseElement(s)
-		if err != nil {
-			t.Fatalf("Failed to parse lambda coefficient %d: %s", i, s)
-		}
-		lambdaCoeffs[i] = elem
+	lambdaCoeffs, err := testutil.ParseElements(field, input.LambdaCoeffs)
+	if err != nil {
+		t.Fatalf("Failed to parse lambda coefficients: %v", err)
 	}
 
 	// Create lambda polynomial
@@ -97,16 +74,10 @@ func TestForneyImplementation(t *testing.T) {
 	// Compute error magnitudes using student implementation
 	magnitudes := ComputeErrorMagnitudes(field, lambda, omega, input.ErrorPositions)
 
-	// Convert magnitudes to strings for response
-	magnitudeStrings := make([]string, len(magnitudes))
-	for i, mag := range magnitudes {
-		magnitudeStrings[i] = mag.String()
-	}
-
 	// Create response
 	response := forney.ForneyTestResponse{
 		OmegaCoeffs:     omega.Coefficients(),
-		ErrorMagnitudes: magnitudeStrings,
+		ErrorMagnitudes: testutil.ElementsToStrings(magnitudes),
 	}
 
 	// Encode response
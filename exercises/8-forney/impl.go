@@ -27,7 +27,16 @@ import (
 // Returns:
 //   - The error evaluator polynomial O(x) of degree < deg(L)
 func ComputeOmega(field gfpn.Field, syndromes []gfpn.Element, lambda gfpoly.Polynomial) gfpoly.Polynomial {
-	panic("TODO: implement ComputeOmega")
+	numErrors := lambda.Degree()
+	if numErrors <= 0 {
+		return gfpoly.NewPolynomial(field, []gfpn.Element{})
+	}
+
+	// O(x) = S(x) · L(x) mod x^ν
+	product := gfpoly.Multiply(gfpoly.NewPolynomial(field, syndromes), lambda)
+	xToNu := gfpoly.NewPolynomial(field, []gfpn.Element{field.One()}).Shift(numErrors)
+
+	return gfpoly.Mod(product, xToNu)
 }
 
 // FormalDerivative computes the formal derivative of a polynomial over a finite field
@@ -49,16 +58,21 @@ func FormalDerivative(poly gfpoly.Polynomial) gfpoly.Polynomial {
 //
 // Forney's formula computes the error magnitude Yᵢ at position jᵢ:
 //
-//	Yᵢ = -O(X_i^{-1}) / (X_i · L'(X_i^{-1}))
+//	Yᵢ = -Xᵢ · O(X_i^{-1}) / L'(X_i^{-1})
 //
 // where:
 //   - X_i = α^j_i is the error locator
 //   - O(x) is the error evaluator polynomial
 //   - L'(x) is the formal derivative of the error locator polynomial
 //
+// The factor Xᵢ comes from the syndromes starting at S_0 = r(α^0). For codes whose
+// syndromes start at S_1 = r(α^1) the factor disappears.
+//
 // In characteristic 2 fields, -a = a, so the formula becomes:
 //
-//	Yᵢ = O(X_i^{-1}) / (Xᵢ · L'(X_i^{-1}))
+//	Yᵢ = Xᵢ · O(X_i^{-1}) / L'(X_i^{-1})
+//
+// L(X_i^{-1}) and L'(X_i^{-1}) are computed together in a single Horner pass.
 //
 // Parameters:
 //   - field: The finite field GF(p^n)
@@ -85,5 +99,27 @@ func ComputeErrorMagnitudes(
 	omega gfpoly.Polynomial,
 	errorPositions []int,
 ) []gfpn.Element {
-	panic("TODO: implement ComputeErrorMagnitudes")
+	alpha := field.Primitive()
+	magnitudes := make([]gfpn.Element, len(errorPositions))
+
+	for i, pos := range errorPositions {
+		// X_i = α^j_i
+		locator := field.One()
+		for k := 0; k < pos; k++ {
+			locator = field.Mul(locator, alpha)
+		}
+		locatorInverse := locator.Inverse()
+
+		// Only the derivative of L is needed, the value is zero at an error position
+		_, lambdaDerivative := lambda.EvaluateWithDerivative(locatorInverse)
+		if lambdaDerivative.IsZero() {
+			panic("L'(X^-1) is zero: error locator has a repeated root")
+		}
+
+		// Y_i = -X_i · O(X_i^{-1}) / L'(X_i^{-1})
+		numerator := field.Mul(locator, omega.Evaluate(locatorInverse))
+		magnitudes[i] = field.Sub(field.Zero(), field.Div(numerator, lambdaDerivative))
+	}
+
+	return magnitudes
 }
@@ -6,6 +6,7 @@ require (
 	github.com/jalphad/testforge v0.0.0-20251018131101-ff30512041c0
 	github.com/makiuchi-d/gozxing v0.1.1
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/text v0.27.0
 	google.golang.org/grpc v1.76.0
 )
 
@@ -14,7 +15,6 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	golang.org/x/net v0.42.0 // indirect
 	golang.org/x/sys v0.34.0 // indirect
-	golang.org/x/text v0.27.0 // indirect
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250804133106-a7a43d27e69b // indirect
 	google.golang.org/protobuf v1.36.8 // indirect
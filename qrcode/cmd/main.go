@@ -1,8 +1,11 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/jalphad/abstract_algebra/qrcode/decoder"
 	"github.com/jalphad/abstract_algebra/qrcode/types"
@@ -17,24 +20,28 @@ import (
 // error correction algorithms built on top of a generic implementation
 // of Galois Fields (GF(p^n)).
 func main() {
-	if len(os.Args) < 2 {
-		printUsage()
+	// Parse command-line flags
+	verbose := flag.Bool("v", false, "Verbose mode (show detailed decoding steps)")
+	blocks := flag.Bool("blocks", false, "Print each RS block's codewords in hex before and after correction")
+	dir := flag.String("dir", "", "Decode every PNG/JPEG image in a directory and print aggregate statistics")
+	flag.Usage = printUsage
+	flag.Parse()
+
+	if *dir != "" {
+		decodeDirectory(*dir)
 		return
 	}
 
-	// Parse command-line flags
-	verbose := false
-	imagePath := os.Args[1]
-
-	if len(os.Args) >= 3 && os.Args[1] == "-v" {
-		verbose = true
-		if len(os.Args) < 3 {
-			printUsage()
-			return
-		}
-		imagePath = os.Args[2]
+	if flag.NArg() < 1 {
+		printUsage()
+		return
 	}
 
+	decodeImage(flag.Arg(0), *verbose, *blocks)
+}
+
+// decodeImage extracts and decodes a single QR code image, printing each step
+func decodeImage(imagePath string, verbose, blocks bool) {
 	// Step 1: Extract QR code data from image
 	fmt.Println("=== QR Code Extraction ===")
 	extractor := types.NewQRExtractor()
@@ -61,8 +68,17 @@ func main() {
 	}
 
 	dec.SetVerbose(verbose)
+	dec.SetCaptureBlocks(blocks)
 
 	result, err := dec.Decode(qrData)
+	if blocks && result != nil {
+		// Also shown when decoding fails, since that is when the dump is most useful:
+		// Decode returns the block results together with a correction error
+		fmt.Println("\n=== Reed-Solomon Block Codewords ===")
+		if dumpErr := decoder.WriteBlockDump(os.Stdout, result.BlockResults); dumpErr != nil {
+			fmt.Printf("Error printing blocks: %v\n", dumpErr)
+		}
+	}
 	if err != nil {
 		fmt.Printf("Error decoding QR code: %v\n", err)
 		os.Exit(1)
@@ -100,19 +116,70 @@ func main() {
 	fmt.Println("decoded the QR code using GF(256) finite field arithmetic!")
 }
 
+// decodeDirectory decodes every image in a directory and prints a line per image
+// followed by statistics aggregated over all of them
+func decodeDirectory(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		fmt.Printf("Error reading directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	dec, err := decoder.NewDecoder()
+	if err != nil {
+		fmt.Printf("Error creating decoder: %v\n", err)
+		os.Exit(1)
+	}
+
+	extractor := types.NewQRExtractor()
+	stats := decoder.NewStats()
+
+	fmt.Printf("=== Decoding images in %s ===\n", dir)
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".png" && ext != ".jpg" && ext != ".jpeg") {
+			continue
+		}
+
+		qrData, err := extractor.ExtractFromImage(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			fmt.Printf("✗ %s: extraction failed: %v\n", entry.Name(), err)
+			stats.Observe(nil, nil)
+			continue
+		}
+
+		result, err := dec.Decode(qrData)
+		stats.Observe(result, qrData)
+		if err != nil {
+			fmt.Printf("✗ %s: decoding failed: %v\n", entry.Name(), err)
+			continue
+		}
+
+		fmt.Printf("✓ %s: \"%s\" (%d error(s) corrected)\n", entry.Name(), result.Message, result.NumErrorsCorrected)
+	}
+
+	fmt.Println("\n=== STATISTICS ===")
+	fmt.Print(stats.Report())
+}
+
 func printUsage() {
 	fmt.Println("QR Code Decoder with Reed-Solomon Error Correction")
 	fmt.Println()
 	fmt.Println("Usage:")
-	fmt.Println("  go run main.go [-v] <qr_code_image>")
+	fmt.Println("  go run main.go [-v] [-blocks] <qr_code_image>")
+	fmt.Println("  go run main.go -dir <directory>")
 	fmt.Println()
 	fmt.Println("Arguments:")
 	fmt.Println("  qr_code_image    Path to QR code image (PNG, JPEG)")
 	fmt.Println()
 	fmt.Println("Options:")
 	fmt.Println("  -v               Verbose mode (show detailed decoding steps)")
+	fmt.Println("  -blocks          Print each RS block's codewords (hex) before and after correction")
+	fmt.Println("  -dir <directory> Decode all images in a directory and print statistics")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  go run main.go qr_code.png")
 	fmt.Println("  go run main.go -v my_qr_code.jpg")
+	fmt.Println("  go run main.go -blocks damaged_qr_code.png")
+	fmt.Println("  go run main.go -dir ./scans")
 }
@@ -2,8 +2,13 @@ package correction
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
+	"github.com/jalphad/abstract_algebra/exercises/4-gfpoly"
+	"github.com/jalphad/abstract_algebra/exercises/6-berlekamp"
+	"github.com/jalphad/abstract_algebra/exercises/7-chien"
+	"github.com/jalphad/abstract_algebra/exercises/8-forney"
 )
 
 // ApplyCorrections corrects errors in the received codeword
@@ -65,6 +70,19 @@ func ApplyCorrections(
 	return corrected
 }
 
+// NumSyndromes returns how many syndromes to compute for a block with numECCodewords EC codewords
+//
+// A Reed-Solomon code with e EC codewords has a generator polynomial with e
+// consecutive roots α^0, ..., α^(e-1), so there are exactly e syndromes
+// S_i = r(α^i) carrying information. Textbooks usually write this as 2t, the
+// count needed to correct t errors, but a QR block's EC codeword count is often
+// odd and is not derived from t. The syndrome count therefore follows the EC
+// codeword count, not 2t: Version 1-L has 7 EC codewords and 7 syndromes, even
+// though it corrects at most 3 errors.
+func NumSyndromes(numECCodewords int) int {
+	return numECCodewords
+}
+
 // VerifyCorrection verifies that a codeword is valid by computing its syndromes
 //
 // A valid codeword has all syndromes equal to zero. This function computes
@@ -73,7 +91,7 @@ func ApplyCorrections(
 // Parameters:
 //   - field: The finite field GF(p^n)
 //   - codeword: The codeword to verify
-//   - numSyndromes: Number of syndromes to compute (typically 2t for t-error correction)
+//   - numECCodewords: Number of EC codewords in the codeword (see NumSyndromes)
 //
 // Returns:
 //   - syndromes: The computed syndrome values
@@ -88,9 +106,10 @@ func ApplyCorrections(
 func VerifyCorrection(
 	field gfpn.Field,
 	codeword []gfpn.Element,
-	numSyndromes int,
+	numECCodewords int,
 ) ([]gfpn.Element, bool) {
 	// Compute syndromes directly
+	numSyndromes := NumSyndromes(numECCodewords)
 	alpha := field.Primitive() // primitive element
 	syndromes := make([]gfpn.Element, numSyndromes)
 
@@ -173,6 +192,143 @@ func ExtractMessage(
 	return message
 }
 
+// VecAdd adds two codewords (or any element vectors) position by position
+//
+// Reed-Solomon codes are linear, so codeword manipulation is vector arithmetic
+// over GF(p^n): a received word is codeword + error vector, and correcting it
+// adds the negated error vector back. In characteristic 2, VecAdd(a, b) is XOR.
+//
+// Parameters:
+//   - field: The finite field GF(p^n)
+//   - a, b: Vectors of equal length
+//
+// Returns:
+//   - The vector [a_0 + b_0, a_1 + b_1, ...]
+//   - Error if the lengths differ
+//
+// Example:
+//
+//	received, err := VecAdd(field, codeword, errorVector)
+func VecAdd(field gfpn.Field, a, b []gfpn.Element) ([]gfpn.Element, error) {
+	if len(a) != len(b) {
+		return nil, fmt.Errorf("vector lengths differ: %d and %d", len(a), len(b))
+	}
+
+	sum := make([]gfpn.Element, len(a))
+	for i := range a {
+		sum[i] = field.Add(a[i], b[i])
+	}
+
+	return sum, nil
+}
+
+// VecScale multiplies every entry of a vector by the scalar s
+//
+// Scaling a codeword gives another codeword (linearity), which is how an
+// encoder combines generator rows and how a single error of magnitude Y is
+// spread over a unit vector.
+//
+// Parameters:
+//   - field: The finite field GF(p^n)
+//   - s: The scalar
+//   - a: The vector to scale
+//
+// Returns:
+//   - The vector [s·a_0, s·a_1, ...]
+func VecScale(field gfpn.Field, s gfpn.Element, a []gfpn.Element) []gfpn.Element {
+	scaled := make([]gfpn.Element, len(a))
+	for i := range a {
+		scaled[i] = field.Mul(s, a[i])
+	}
+
+	return scaled
+}
+
+// RSEncoder systematically encodes messages with a Reed-Solomon code
+//
+// The generator polynomial has the numEC consecutive roots α^0, ..., α^(numEC-1),
+// matching the syndromes S_i = r(α^i) checked by VerifyCorrection:
+//
+//	g(x) = (x - α^0)(x - α^1)···(x - α^(numEC-1))
+//
+// Codewords are multiples of g(x). Vectors are ordered lowest degree first.
+// NewRSEncoderFCR moves the roots to α^b, ..., α^(b+numEC-1) for codes whose
+// first consecutive root b is not 0.
+type RSEncoder struct {
+	field     gfpn.Field
+	numEC     int
+	generator gfpoly.Polynomial
+}
+
+// NewRSEncoder creates an encoder adding numEC parity symbols over the given field
+//
+// Returns an error if numEC is not positive or not smaller than the field order,
+// the longest possible codeword being p^n - 1 symbols.
+func NewRSEncoder(field gfpn.Field, numEC int) (*RSEncoder, error) {
+	return NewRSEncoderFCR(field, numEC, 0)
+}
+
+// NewRSEncoderFCR creates an encoder whose generator polynomial has the first
+// consecutive root α^firstRoot:
+//
+//	g(x) = (x - α^b)(x - α^(b+1))···(x - α^(b+numEC-1)),  b = firstRoot
+//
+// The codewords then have zero syndromes S_i = r(α^(b+i)), see
+// syndrome.CalculateSyndromesFCR. NewRSEncoder is the QR case b = 0.
+func NewRSEncoderFCR(field gfpn.Field, numEC, firstRoot int) (*RSEncoder, error) {
+	if numEC <= 0 || numEC >= field.Order()-1 {
+		return nil, fmt.Errorf("invalid number of EC symbols %d for a field of order %d", numEC, field.Order())
+	}
+
+	generator := gfpoly.NewPolynomial(field, []gfpn.Element{field.One()})
+	for i := 0; i < numEC; i++ {
+		root := field.Exp(firstRoot + i)
+		factor := gfpoly.NewPolynomial(field, []gfpn.Element{field.Sub(field.Zero(), root), field.One()})
+		generator = gfpoly.Multiply(generator, factor)
+	}
+
+	return &RSEncoder{field: field, numEC: numEC, generator: generator}, nil
+}
+
+// Generator returns the generator polynomial g(x)
+func (e *RSEncoder) Generator() gfpoly.Polynomial {
+	return e.generator
+}
+
+// Parity computes the numEC parity symbols for a message
+//
+// With the message as d(x) = data[0] + data[1]·x + ..., the parity is
+//
+//	p(x) = -(d(x)·x^numEC mod g(x))
+//
+// so that d(x)·x^numEC + p(x) is divisible by g(x). In characteristic 2 the
+// negation disappears and the parity is just the remainder. The result always
+// has numEC entries (high-order zeros included), lowest degree first, leaving
+// the choice of parity-first or parity-last layout to the caller.
+//
+// Example:
+//
+//	parity := encoder.Parity(data)
+//	codeword := append(parity, data...) // lowest degree first, see Encode
+func (e *RSEncoder) Parity(data []gfpn.Element) []gfpn.Element {
+	if len(data)+e.numEC > e.field.Order()-1 {
+		panic(fmt.Sprintf("codeword length %d exceeds the maximum %d for this field",
+			len(data)+e.numEC, e.field.Order()-1))
+	}
+
+	shifted := gfpoly.NewPolynomial(e.field, data).Shift(e.numEC)
+	remainder := gfpoly.Mod(shifted, e.generator)
+	return VecScale(e.field, e.field.Sub(e.field.Zero(), e.field.One()), remainder.CoefficientsPadded(e.numEC))
+}
+
+// Encode returns the systematic codeword for a message: the parity symbols in
+// the low degree terms followed by the message, i.e. append(Parity(data), data...)
+//
+// ExtractMessage(codeword, len(data), true) recovers the message.
+func (e *RSEncoder) Encode(data []gfpn.Element) []gfpn.Element {
+	return append(e.Parity(data), data...)
+}
+
 // DecodeResult contains the result of Reed-Solomon decoding
 type DecodeResult struct {
 	Success           bool           // Whether decoding succeeded
@@ -183,3 +339,178 @@ type DecodeResult struct {
 	CorrectedCodeword []gfpn.Element // The corrected codeword
 	Syndromes         []gfpn.Element // Final syndromes (should be all zero)
 }
+
+// DecodeWithErasures corrects a received word with both errors and erasures
+//
+// An erasure is a symbol known to be unreliable (e.g. an unreadable module) whose
+// position is given, so only its value has to be found. Each erasure costs one
+// EC symbol and each unknown error two, so decoding succeeds when
+//
+//	2ν + μ ≤ numEC
+//
+// for ν errors and μ erasures. The steps are:
+//
+//  1. Syndromes S_i = r(α^i) for i = 0, ..., numEC-1 (see VerifyCorrection)
+//  2. Erasure locator Γ(x) = Π (1 - α^(j_k)·x) over the erasure positions j_k
+//  3. Forney syndromes T(x) = Γ(x)·S(x) mod x^numEC, from which the erasures have
+//     been removed; T_μ, ..., T_(numEC-1) are fed to Berlekamp-Massey to find
+//     the error locator Λ(x)
+//  4. Errata locator Ψ(x) = Λ(x)·Γ(x), whose roots cover errors and erasures
+//  5. Chien search on Ψ(x) and Forney's formula for all errata magnitudes
+//  6. Apply the corrections and verify that all syndromes are zero
+//
+// Positions follow the standard polynomial convention: received[j] is the
+// coefficient of x^j. The message is taken from the end of the corrected
+// codeword, as produced by systematic encoding with the parity in the low
+// degree terms (see ExtractMessage).
+//
+// Parameters:
+//   - field: The finite field GF(p^n)
+//   - received: The received word, lowest degree first
+//   - erasurePositions: Distinct positions of the erased symbols
+//   - numEC: Number of EC (parity) symbols
+//
+// Returns:
+//   - The decode result, whose ErrorPositions and ErrorMagnitudes cover errors
+//     and erasures alike (an erasure that was read correctly has magnitude zero)
+//   - Error if the erasures are invalid or the errata exceed the budget
+//
+// Example:
+//
+//	// RS(15,11) over GF(16): 2 erasures and 1 error use all 4 EC symbols
+//	result, err := DecodeWithErasures(field, received, []int{3, 9}, 4)
+func DecodeWithErasures(
+	field gfpn.Field,
+	received []gfpn.Element,
+	erasurePositions []int,
+	numEC int,
+) (DecodeResult, error) {
+	if numEC <= 0 || numEC >= len(received) {
+		return DecodeResult{}, fmt.Errorf("invalid code parameters: %d EC symbols in a codeword of length %d",
+			numEC, len(received))
+	}
+	if len(erasurePositions) > numEC {
+		return DecodeResult{}, fmt.Errorf("too many erasures: %d, can correct at most %d",
+			len(erasurePositions), numEC)
+	}
+
+	// Step 1: syndromes
+	syndromes, valid := VerifyCorrection(field, received, numEC)
+	if valid {
+		return DecodeResult{
+			Success:           true,
+			Message:           ExtractMessage(received, len(received)-numEC, true),
+			CorrectedCodeword: append([]gfpn.Element(nil), received...),
+			Syndromes:         syndromes,
+		}, nil
+	}
+
+	// Steps 2-3: erasure locator Γ(x) = Π (1 - X_k·x) with X_k = α^(j_k), Forney
+	// syndromes and the error locator
+	seen := make(map[int]bool, len(erasurePositions))
+	for _, pos := range erasurePositions {
+		if pos < 0 || pos >= len(received) {
+			return DecodeResult{}, fmt.Errorf("erasure position %d out of bounds [0, %d)", pos, len(received))
+		}
+		if seen[pos] {
+			return DecodeResult{}, fmt.Errorf("duplicate erasure position %d", pos)
+		}
+		seen[pos] = true
+	}
+
+	numErasures := len(erasurePositions)
+	lambda, gamma := berlekamp.BerlekampMasseyWithErasures(field, syndromes, erasurePositions)
+	if !berlekamp.IsValidLocator(lambda) {
+		return DecodeResult{}, fmt.Errorf("invalid error locator polynomial: constant term is not 1")
+	}
+	if numErrors := lambda.Degree(); 2*numErrors+numErasures > numEC {
+		return DecodeResult{}, fmt.Errorf("too many errata: %d errors and %d erasures need %d EC symbols, have %d",
+			numErrors, numErasures, 2*numErrors+numErasures, numEC)
+	}
+
+	// Step 4: errata locator and evaluator
+	psi := gfpoly.Multiply(lambda, gamma)
+	omega := forney.ComputeOmega(field, syndromes, psi)
+
+	// Step 5: errata positions and magnitudes
+	positions := chien.ChienSearch(field, psi, len(received))
+	if len(positions) != psi.Degree() {
+		return DecodeResult{}, fmt.Errorf("errata locator has %d roots in the codeword, want %d",
+			len(positions), psi.Degree())
+	}
+	magnitudes := forney.ComputeErrorMagnitudes(field, psi, omega, positions)
+
+	// Step 6: apply and verify
+	corrected := ApplyCorrections(field, received, positions, magnitudes)
+	finalSyndromes, valid := VerifyCorrection(field, corrected, numEC)
+	if !valid {
+		return DecodeResult{}, fmt.Errorf("correction verification failed")
+	}
+
+	return DecodeResult{
+		Success:           true,
+		Message:           ExtractMessage(corrected, len(received)-numEC, true),
+		NumErrors:         len(positions),
+		ErrorPositions:    positions,
+		ErrorMagnitudes:   magnitudes,
+		CorrectedCodeword: corrected,
+		Syndromes:         finalSyndromes,
+	}, nil
+}
+
+// symbolAlphabetSize is the number of distinct symbol values, q, for byte-oriented
+// Reed-Solomon codes over GF(256) such as those used by QR codes
+const symbolAlphabetSize = 256
+
+// MiscorrectionRisk estimates the probability that a successful decode is a miscorrection
+//
+// A bounded-distance decoder that reports e corrected errors has moved the received
+// word onto the nearest codeword within distance e. If the word actually carried more
+// errors than the code can handle, it still "decodes" whenever it happens to land in
+// the decoding sphere of a different codeword. Treating such a word as uniformly
+// random over the q^numEC syndromes, the chance of landing within distance e of some
+// codeword is the sphere volume divided by the number of syndromes:
+//
+//	P(e) ≈ V(n, e) / q^numEC,  where V(n, e) = Σ_{i=0}^{e} C(n, i)·(q-1)^i
+//
+// The risk is negligible when few errors were corrected and grows steeply as e
+// approaches the correction capacity t = floor(numEC/2). Results above t mean the
+// decode cannot be trusted at all, so the risk is 1.
+//
+// Parameters:
+//   - numErrorsFound: Number of symbol errors the decoder corrected (e)
+//   - numEC: Number of EC (parity) symbols in the block
+//   - codewordLength: Total number of symbols in the block (n), at most q-1
+//
+// Returns:
+//   - The estimated miscorrection probability in [0, 1]
+//
+// Example:
+//
+//	// Version 1-L block: 26 codewords, 7 EC codewords
+//	risk := MiscorrectionRisk(1, 7, 26)
+//	// risk ≈ 9.2e-14
+func MiscorrectionRisk(numErrorsFound, numEC, codewordLength int) float64 {
+	if numEC <= 0 || codewordLength <= numEC || codewordLength >= symbolAlphabetSize {
+		panic(fmt.Sprintf("invalid code parameters: %d EC symbols in a codeword of length %d",
+			numEC, codewordLength))
+	}
+	if numErrorsFound < 0 {
+		panic(fmt.Sprintf("invalid error count %d", numErrorsFound))
+	}
+
+	if numErrorsFound > numEC/2 {
+		return 1
+	}
+
+	// Accumulate V(n, e) term by term: C(n, i)·(q-1)^i = C(n, i-1)·(q-1)^(i-1) · (n-i+1)(q-1)/i
+	q := float64(symbolAlphabetSize)
+	term := 1.0
+	volume := 1.0
+	for i := 1; i <= numErrorsFound; i++ {
+		term *= float64(codewordLength-i+1) * (q - 1) / float64(i)
+		volume += term
+	}
+
+	return math.Min(volume/math.Pow(q, float64(numEC)), 1)
+}
@@ -0,0 +1,339 @@
+package correction
+
+import (
+	"testing"
+
+	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
+	"github.com/jalphad/abstract_algebra/exercises/4-gfpoly"
+	"github.com/makiuchi-d/gozxing/qrcode/decoder"
+)
+
+func TestMiscorrectionRisk(t *testing.T) {
+	// Version 1-L block: 26 codewords, 7 EC codewords, t = 3
+	const numEC, codewordLength = 7, 26
+
+	if risk := MiscorrectionRisk(0, numEC, codewordLength); risk > 1e-15 {
+		t.Errorf("risk with no errors = %g, want ~0", risk)
+	}
+
+	// The risk grows strictly as the error count approaches t
+	previous := 0.0
+	for e := 0; e <= numEC/2; e++ {
+		risk := MiscorrectionRisk(e, numEC, codewordLength)
+		if risk <= previous {
+			t.Errorf("risk(%d) = %g, want more than risk(%d) = %g", e, risk, e-1, previous)
+		}
+		if risk >= 1e-5 {
+			t.Errorf("risk(%d) = %g, want well below 1 within capacity", e, risk)
+		}
+		previous = risk
+	}
+
+	// Beyond capacity the decode cannot be trusted
+	if risk := MiscorrectionRisk(numEC/2+1, numEC, codewordLength); risk != 1 {
+		t.Errorf("risk beyond capacity = %g, want 1", risk)
+	}
+}
+
+func TestMiscorrectionRisk_MoreECLowersRisk(t *testing.T) {
+	// The same number of errors is far safer with more parity symbols
+	low := MiscorrectionRisk(2, 22, 44)
+	high := MiscorrectionRisk(2, 8, 44)
+	if low >= high {
+		t.Errorf("risk with 22 EC = %g, want less than risk with 8 EC = %g", low, high)
+	}
+}
+
+func TestNumSyndromes(t *testing.T) {
+	// Version 1-L: one block with 7 EC codewords
+	version, err := decoder.Version_GetVersionForNumber(1)
+	if err != nil {
+		t.Fatalf("Failed to get version 1: %v", err)
+	}
+	numEC := version.GetECBlocksForLevel(decoder.ErrorCorrectionLevel_L).GetECCodewordsPerBlock()
+	if numEC != 7 {
+		t.Fatalf("version 1-L has %d EC codewords, want 7", numEC)
+	}
+
+	// One syndrome per EC codeword, not 2t = 6
+	if got := NumSyndromes(numEC); got != 7 {
+		t.Errorf("NumSyndromes(%d) = %d, want 7", numEC, got)
+	}
+}
+
+// qrField creates GF(256) with the QR code polynomial x^8 + x^4 + x^3 + x^2 + 1
+func qrField(t *testing.T) gfpn.Field {
+	t.Helper()
+	field, err := gfpn.NewField(2, 8, []int{1, 0, 1, 1, 1, 0, 0, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(256): %v", err)
+	}
+	return field
+}
+
+func TestVecAdd(t *testing.T) {
+	field := qrField(t)
+	a := []gfpn.Element{field.Element(1), field.Element(5), field.Zero()}
+	b := []gfpn.Element{field.Element(1), field.Element(9), field.Element(200)}
+
+	sum, err := VecAdd(field, a, b)
+	if err != nil {
+		t.Fatalf("VecAdd returned error: %v", err)
+	}
+
+	// In characteristic 2 equal entries cancel, and adding zero is the identity
+	want := []gfpn.Element{field.Zero(), field.Add(field.Element(5), field.Element(9)), field.Element(200)}
+	for i := range want {
+		if !sum[i].Equal(want[i]) {
+			t.Errorf("sum[%d] = %s, want %s", i, sum[i], want[i])
+		}
+	}
+
+	// Adding a vector to itself gives the zero vector
+	double, err := VecAdd(field, b, b)
+	if err != nil {
+		t.Fatalf("VecAdd returned error: %v", err)
+	}
+	for i, e := range double {
+		if !e.IsZero() {
+			t.Errorf("b + b [%d] = %s, want 0", i, e)
+		}
+	}
+
+	if _, err := VecAdd(field, a, b[:2]); err == nil {
+		t.Errorf("VecAdd with different lengths should return an error")
+	}
+}
+
+func TestVecScale(t *testing.T) {
+	field := qrField(t)
+	alpha := field.Primitive()
+	a := []gfpn.Element{field.One(), alpha, field.Zero()}
+
+	scaled := VecScale(field, alpha, a)
+	want := []gfpn.Element{alpha, field.Mul(alpha, alpha), field.Zero()}
+	if len(scaled) != len(want) {
+		t.Fatalf("len = %d, want %d", len(scaled), len(want))
+	}
+	for i := range want {
+		if !scaled[i].Equal(want[i]) {
+			t.Errorf("scaled[%d] = %s, want %s", i, scaled[i], want[i])
+		}
+	}
+
+	// Scaling by zero gives the zero vector, by one leaves it unchanged
+	for i, e := range VecScale(field, field.Zero(), a) {
+		if !e.IsZero() {
+			t.Errorf("0·a[%d] = %s, want 0", i, e)
+		}
+	}
+	for i, e := range VecScale(field, field.One(), a) {
+		if !e.Equal(a[i]) {
+			t.Errorf("1·a[%d] = %s, want %s", i, e, a[i])
+		}
+	}
+}
+
+func TestDecodeWithErasures(t *testing.T) {
+	// GF(16) = GF(2)[x]/(x^4 + x + 1)
+	field, err := gfpn.NewField(2, 4, []int{1, 1, 0, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(16): %v", err)
+	}
+
+	message := make([]gfpn.Element, 11)
+	for i := range message {
+		message[i] = field.Element(i + 3)
+	}
+	encoder, err := NewRSEncoder(field, 4)
+	if err != nil {
+		t.Fatalf("NewRSEncoder() error = %v", err)
+	}
+	codeword := encoder.Encode(message)
+	if _, valid := VerifyCorrection(field, codeword, 4); !valid {
+		t.Fatal("encoded word is not a codeword")
+	}
+
+	// 2 erasures (positions 2 and 9) and 1 error (position 12): 2·1 + 2 = 4 EC symbols
+	received := append([]gfpn.Element(nil), codeword...)
+	received[2] = field.Zero()
+	received[9] = field.Element(1)
+	received[12] = field.Add(received[12], field.Element(7))
+
+	result, err := DecodeWithErasures(field, received, []int{2, 9}, 4)
+	if err != nil {
+		t.Fatalf("DecodeWithErasures() error = %v", err)
+	}
+	if !result.Success {
+		t.Fatal("DecodeWithErasures() did not succeed")
+	}
+	for i := range codeword {
+		if !result.CorrectedCodeword[i].Equal(codeword[i]) {
+			t.Errorf("corrected[%d] = %s, want %s", i, result.CorrectedCodeword[i], codeword[i])
+		}
+	}
+	for i := range message {
+		if !result.Message[i].Equal(message[i]) {
+			t.Errorf("message[%d] = %s, want %s", i, result.Message[i], message[i])
+		}
+	}
+
+	found := make(map[int]bool)
+	for _, pos := range result.ErrorPositions {
+		found[pos] = true
+	}
+	for _, pos := range []int{2, 9, 12} {
+		if !found[pos] {
+			t.Errorf("errata position %d not reported, got %v", pos, result.ErrorPositions)
+		}
+	}
+
+	// Erasures alone can use the whole budget: 4 erasures, no errors
+	received = append([]gfpn.Element(nil), codeword...)
+	for _, pos := range []int{0, 6, 7, 14} {
+		received[pos] = field.Zero()
+	}
+	result, err = DecodeWithErasures(field, received, []int{0, 6, 7, 14}, 4)
+	if err != nil {
+		t.Fatalf("DecodeWithErasures() with 4 erasures error = %v", err)
+	}
+	for i := range codeword {
+		if !result.CorrectedCodeword[i].Equal(codeword[i]) {
+			t.Errorf("4 erasures: corrected[%d] = %s, want %s", i, result.CorrectedCodeword[i], codeword[i])
+		}
+	}
+
+	// More erasures than EC symbols can never be filled in
+	if _, err := DecodeWithErasures(field, codeword, []int{0, 1, 2, 3, 4}, 4); err == nil {
+		t.Error("expected an error for 5 erasures with 4 EC symbols")
+	}
+}
+
+func TestRSEncoder(t *testing.T) {
+	field := qrField(t)
+
+	// Version 1-M has 10 EC codewords per block
+	const numEC = 10
+	encoder, err := NewRSEncoder(field, numEC)
+	if err != nil {
+		t.Fatalf("NewRSEncoder() error = %v", err)
+	}
+	if encoder.Generator().Degree() != numEC {
+		t.Errorf("generator degree = %d, want %d", encoder.Generator().Degree(), numEC)
+	}
+
+	data := make([]gfpn.Element, 16)
+	for i := range data {
+		data[i] = field.Element(17*i + 5)
+	}
+
+	parity := encoder.Parity(data)
+	if len(parity) != numEC {
+		t.Fatalf("len(parity) = %d, want %d", len(parity), numEC)
+	}
+
+	codeword := append(append([]gfpn.Element(nil), parity...), data...)
+	if syndromes, valid := VerifyCorrection(field, codeword, numEC); !valid {
+		t.Errorf("parity-first codeword has non-zero syndromes %v", syndromes)
+	}
+
+	// Encode is the same layout, and the message is the high degree part
+	encoded := encoder.Encode(data)
+	for i := range codeword {
+		if !encoded[i].Equal(codeword[i]) {
+			t.Fatalf("Encode()[%d] = %s, want %s", i, encoded[i], codeword[i])
+		}
+	}
+	message := ExtractMessage(encoded, len(data), true)
+	for i := range data {
+		if !message[i].Equal(data[i]) {
+			t.Errorf("message[%d] = %s, want %s", i, message[i], data[i])
+		}
+	}
+
+	// An all-zero message has all-zero parity
+	zeros := make([]gfpn.Element, 8)
+	for i := range zeros {
+		zeros[i] = field.Zero()
+	}
+	for i, p := range encoder.Parity(zeros) {
+		if !p.IsZero() {
+			t.Errorf("parity[%d] of zero message = %s, want 0", i, p)
+		}
+	}
+
+	if _, err := NewRSEncoder(field, 0); err == nil {
+		t.Error("expected an error for 0 EC symbols")
+	}
+}
+
+func TestRSEncoderFCR(t *testing.T) {
+	field := qrField(t)
+
+	// Generator roots α^1, ..., α^6 as in textbook RS codes
+	const numEC, firstRoot = 6, 1
+	encoder, err := NewRSEncoderFCR(field, numEC, firstRoot)
+	if err != nil {
+		t.Fatalf("NewRSEncoderFCR() error = %v", err)
+	}
+
+	data := make([]gfpn.Element, 12)
+	for i := range data {
+		data[i] = field.Element(31*i + 3)
+	}
+	codeword := gfpoly.NewPolynomial(field, encoder.Encode(data))
+
+	for i := 0; i < numEC; i++ {
+		if value := codeword.Evaluate(field.Exp(firstRoot + i)); !value.IsZero() {
+			t.Errorf("codeword(α^%d) = %s, want 0", firstRoot+i, value)
+		}
+	}
+
+	// α^0 is no longer a root, so the QR syndrome S_0 is not zero
+	if value := codeword.Evaluate(field.One()); value.IsZero() {
+		t.Error("codeword(α^0) = 0, want a non-zero value for first root 1")
+	}
+}
+
+func TestDecodePrimeField(t *testing.T) {
+	// GF(7) as GF(7^1) = GF(7)[x]/(x + 1); its primitive element is a primitive root mod 7
+	field, err := gfpn.NewField(7, 1, []int{1, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(7): %v", err)
+	}
+	if got := field.Pow(field.Primitive(), 3); got.Equal(field.One()) {
+		t.Fatalf("α = %s has order 3, not 6", field.Primitive())
+	}
+
+	// RS(6,4): 4 message symbols and 2 parity symbols correct one error
+	encoder, err := NewRSEncoder(field, 2)
+	if err != nil {
+		t.Fatalf("NewRSEncoder() error = %v", err)
+	}
+	message := []gfpn.Element{field.Element(2), field.Element(5), field.Zero(), field.Element(4)}
+	codeword := encoder.Encode(message)
+	if len(codeword) != 6 {
+		t.Fatalf("len(codeword) = %d, want 6", len(codeword))
+	}
+	if _, valid := VerifyCorrection(field, codeword, 2); !valid {
+		t.Fatal("encoded word is not a codeword")
+	}
+
+	for pos := range codeword {
+		for _, magnitude := range field.MultiplicativeGroup() {
+			received := append([]gfpn.Element(nil), codeword...)
+			received[pos] = field.Add(received[pos], magnitude)
+
+			result, err := DecodeWithErasures(field, received, nil, 2)
+			if err != nil {
+				t.Fatalf("error %s at %d: DecodeWithErasures() error = %v", magnitude, pos, err)
+			}
+			for i := range codeword {
+				if !result.CorrectedCodeword[i].Equal(codeword[i]) {
+					t.Fatalf("error %s at %d: corrected[%d] = %s, want %s",
+						magnitude, pos, i, result.CorrectedCodeword[i], codeword[i])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package decoder
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/makiuchi-d/gozxing/qrcode/decoder"
+)
+
+// Mode identifies the encoding mode of a QR code data segment
+//
+// The values equal the 4-bit mode indicators written in front of each segment.
+type Mode int
+
+const (
+	ModeNumeric      Mode = 0b0001
+	ModeAlphanumeric Mode = 0b0010
+	ModeByte         Mode = 0b0100
+	ModeKanji        Mode = 0b1000
+)
+
+// alphanumericCharset lists the 45 characters of alphanumeric mode in value order
+const alphanumericCharset = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// String returns the name of the mode
+func (m Mode) String() string {
+	switch m {
+	case ModeNumeric:
+		return "Numeric"
+	case ModeAlphanumeric:
+		return "Alphanumeric"
+	case ModeByte:
+		return "Byte"
+	case ModeKanji:
+		return "Kanji"
+	default:
+		return fmt.Sprintf("Mode(%04b)", int(m))
+	}
+}
+
+// characterCountBits returns the length of the character count field for a mode
+//
+// The count field grows with the version so that larger symbols can hold longer segments:
+//
+//	Mode          Version 1-9   Version 10-26   Version 27-40
+//	Numeric       10            12              14
+//	Alphanumeric  9             11              13
+//	Byte          8             16              16
+//	Kanji         8             10              12
+func characterCountBits(mode Mode, version int) (int, error) {
+	var bits [3]int
+	switch mode {
+	case ModeNumeric:
+		bits = [3]int{10, 12, 14}
+	case ModeAlphanumeric:
+		bits = [3]int{9, 11, 13}
+	case ModeByte:
+		bits = [3]int{8, 16, 16}
+	case ModeKanji:
+		bits = [3]int{8, 10, 12}
+	default:
+		return 0, fmt.Errorf("unsupported mode: %v", mode)
+	}
+
+	switch {
+	case version <= 9:
+		return bits[0], nil
+	case version <= 26:
+		return bits[1], nil
+	default:
+		return bits[2], nil
+	}
+}
+
+// segmentLength returns the character count and the number of data bits needed
+// to encode a message in the given mode
+//
+// Bits per character group:
+//   - Numeric:      10 bits per 3 digits (7 bits for 2 trailing digits, 4 bits for 1)
+//   - Alphanumeric: 11 bits per 2 characters (6 bits for a trailing character)
+//   - Byte:         8 bits per byte
+//   - Kanji:        13 bits per character
+func segmentLength(message string, mode Mode) (count int, dataBits int, err error) {
+	switch mode {
+	case ModeNumeric:
+		for _, r := range message {
+			if r < '0' || r > '9' {
+				return 0, 0, fmt.Errorf("character %q cannot be encoded in numeric mode", r)
+			}
+		}
+		count = len(message)
+		dataBits = (count/3)*10 + [3]int{0, 4, 7}[count%3]
+	case ModeAlphanumeric:
+		for _, r := range message {
+			if !strings.ContainsRune(alphanumericCharset, r) {
+				return 0, 0, fmt.Errorf("character %q cannot be encoded in alphanumeric mode", r)
+			}
+		}
+		count = len(message)
+		dataBits = (count/2)*11 + (count%2)*6
+	case ModeByte:
+		count = len(message)
+		dataBits = count * 8
+	case ModeKanji:
+		count = utf8.RuneCountInString(message)
+		dataBits = count * 13
+	default:
+		return 0, 0, fmt.Errorf("unsupported mode: %v", mode)
+	}
+	return count, dataBits, nil
+}
+
+// parseECLevel converts an error correction level name ("L", "M", "Q", "H") to
+// the gozxing representation
+func parseECLevel(ecLevel string) (decoder.ErrorCorrectionLevel, error) {
+	level, err := decoder.ErrorCorrectionLevel_ValueOf(ecLevel)
+	if err != nil {
+		return level, fmt.Errorf("invalid error correction level %q", ecLevel)
+	}
+	return level, nil
+}
+
+// dataCapacityBits returns the number of data bits available in a version at an EC level
+//
+// This is the total number of codewords minus the error correction codewords,
+// multiplied by 8 bits per codeword.
+func dataCapacityBits(version *decoder.Version, ecLevel decoder.ErrorCorrectionLevel) int {
+	ecBlocks := version.GetECBlocksForLevel(ecLevel)
+	return (version.GetTotalCodewords() - ecBlocks.GetTotalECCodewords()) * 8
+}
+
+// MinimumVersion computes the smallest QR version that can hold a message
+//
+// The encoded length of a single-segment message is:
+//
+//	[Mode indicator: 4 bits][Character count: version dependent][Data bits]
+//
+// Because the character count field grows at versions 10 and 27, the bit length
+// is recomputed for every candidate version.
+//
+// Parameters:
+//   - message: The message to encode
+//   - ecLevel: Error correction level ("L", "M", "Q" or "H")
+//   - mode: The encoding mode used for the whole message
+//
+// Returns:
+//   - The smallest version (1-40) whose data capacity fits the message
+//   - Error if the message cannot be encoded in the mode or does not fit version 40
+//
+// Example:
+//
+//	version, err := MinimumVersion("Hello, World!", "L", ModeByte)
+//	// version = 1
+func MinimumVersion(message string, ecLevel string, mode Mode) (int, error) {
+	level, err := parseECLevel(ecLevel)
+	if err != nil {
+		return 0, err
+	}
+
+	count, dataBits, err := segmentLength(message, mode)
+	if err != nil {
+		return 0, err
+	}
+
+	for v := 1; v <= 40; v++ {
+		version, err := decoder.Version_GetVersionForNumber(v)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get version %d: %w", v, err)
+		}
+
+		countBits, err := characterCountBits(mode, v)
+		if err != nil {
+			return 0, err
+		}
+		if count >= 1<<countBits {
+			continue // character count does not fit the count field
+		}
+
+		if 4+countBits+dataBits <= dataCapacityBits(version, level) {
+			return v, nil
+		}
+	}
+
+	return 0, fmt.Errorf("message of %d characters does not fit in version 40-%s (%s mode)", count, ecLevel, mode)
+}
+
+// misdecodeProtectionCodewords returns the number of EC codewords the QR
+// specification reserves for error detection rather than correction
+//
+// For the smallest symbols (versions 1-3) ISO/IEC 18004 sets aside a few EC
+// codewords to lower the probability of a miscorrection. The remaining EC
+// codewords give the correction capacity listed in the specification:
+//
+//	Version  L  M  Q  H
+//	1        3  2  1  1
+//	2        2  0  0  0
+//	3        1  0  0  0
+func misdecodeProtectionCodewords(version int, ecLevel decoder.ErrorCorrectionLevel) int {
+	switch version {
+	case 1:
+		switch ecLevel {
+		case decoder.ErrorCorrectionLevel_L:
+			return 3
+		case decoder.ErrorCorrectionLevel_M:
+			return 2
+		default:
+			return 1
+		}
+	case 2:
+		if ecLevel == decoder.ErrorCorrectionLevel_L {
+			return 2
+		}
+	case 3:
+		if ecLevel == decoder.ErrorCorrectionLevel_L {
+			return 1
+		}
+	}
+	return 0
+}
+
+// MaxDataBytes returns the largest character count a single segment can hold
+//
+// The capacity left for the segment data is the version's data capacity minus
+// the 4-bit mode indicator and the character count field. For byte mode the
+// result is the maximum number of data bytes; for the other modes it is the
+// maximum number of characters (digits, alphanumeric characters or Kanji).
+// The count field width also caps the result, e.g. 255 for byte mode in
+// versions 1-9.
+//
+// Parameters:
+//   - version: QR version (1-40)
+//   - ecLevel: Error correction level ("L", "M", "Q" or "H")
+//   - mode: The encoding mode of the segment
+//
+// Returns:
+//   - The maximum character count a segment in this mode can declare
+//   - Error if the version, EC level or mode is invalid
+//
+// Example:
+//
+//	maxBytes, err := MaxDataBytes(1, "L", ModeByte)
+//	// maxBytes = 17 (19 data codewords = 152 bits, minus 4 + 8 header bits)
+func MaxDataBytes(version int, ecLevel string, mode Mode) (int, error) {
+	level, err := parseECLevel(ecLevel)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := decoder.Version_GetVersionForNumber(version)
+	if err != nil {
+		return 0, fmt.Errorf("invalid version %d: %w", version, err)
+	}
+
+	countBits, err := characterCountBits(mode, version)
+	if err != nil {
+		return 0, err
+	}
+
+	available := dataCapacityBits(v, level) - 4 - countBits
+
+	var count int
+	switch mode {
+	case ModeNumeric:
+		// 10 bits per 3 digits, 4 or 7 bits for a trailing group of 1 or 2
+		count = (available / 10) * 3
+		switch rest := available % 10; {
+		case rest >= 7:
+			count += 2
+		case rest >= 4:
+			count++
+		}
+	case ModeAlphanumeric:
+		// 11 bits per 2 characters, 6 bits for a trailing character
+		count = (available / 11) * 2
+		if available%11 >= 6 {
+			count++
+		}
+	case ModeByte:
+		count = available / 8
+	case ModeKanji:
+		count = available / 13
+	}
+
+	return min(count, 1<<countBits-1), nil
+}
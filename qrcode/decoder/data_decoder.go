@@ -1,7 +1,40 @@
 package decoder
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// ErrImpossibleCount is returned when a segment declares more characters than
+// the QR version can physically hold, a sign of a corrupt length field
+var ErrImpossibleCount = errors.New("character count exceeds symbol capacity")
+
+// ErrMessageTooLong is returned when a segment declares more bytes than
+// DataDecoder.MaxMessageBytes allows
+var ErrMessageTooLong = errors.New("message exceeds maximum length")
+
+// ErrUnsupportedECI is returned in strict mode when a QR code uses an ECI
+// assignment outside the supported set (ISO-8859-1, Shift-JIS, UTF-8)
+var ErrUnsupportedECI = errors.New("unsupported ECI assignment")
+
+// ECI assignment numbers for the character sets the decoder understands
+const (
+	eciISO8859_1Legacy = 1  // ISO-8859-1 (AIM ECI 2000 designation)
+	eciISO8859_1       = 3  // ISO-8859-1
+	eciShiftJIS        = 20 // Shift JIS
+	eciUTF8            = 26 // UTF-8
+)
+
+// Hanzi mode (GB/T 18284) is a Chinese extension, not part of ISO/IEC 18004, so it has
+// no Mode constant for capacity calculations
+const (
+	modeHanzi         = 0b1101
+	hanziSubsetGB2312 = 1
 )
 
 // DataDecoder decodes QR code data bytes into a readable message
@@ -15,9 +48,29 @@ import (
 // For educational purposes and broad compatibility, we focus on Byte mode,
 // which can represent any UTF-8 text.
 type DataDecoder struct {
-	// Add configuration fields if needed in the future
+	// StrictECI rejects QR codes whose ECI (Extended Channel Interpretation)
+	// assignment is not ISO-8859-1, Shift-JIS or UTF-8 with ErrUnsupportedECI.
+	// When false, unsupported assignments fall back to the raw bytes.
+	StrictECI bool
+
+	// CharsetHeuristic guesses the character set of byte mode segments without
+	// an ECI designator. The specification says ISO-8859-1, but many encoders
+	// write UTF-8 without saying so. With the heuristic, a leading UTF-8 byte
+	// order mark is stripped and the data decoded as UTF-8, data that is valid
+	// UTF-8 is decoded as UTF-8, and anything else as ISO-8859-1. When false,
+	// the bytes are returned as-is.
+	CharsetHeuristic bool
+
+	// MaxMessageBytes caps the byte count a segment may declare. Larger counts
+	// are rejected with ErrMessageTooLong before any data is read. This is a
+	// policy limit on top of the physical capacity check (ErrImpossibleCount),
+	// and also applies when the version is unknown. Zero means no limit.
+	MaxMessageBytes int
 }
 
+// utf8BOM is the UTF-8 encoding of U+FEFF, which some encoders prepend to mark UTF-8 data
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
 // NewDataDecoder creates a new data decoder
 func NewDataDecoder() *DataDecoder {
 	return &DataDecoder{}
@@ -33,11 +86,16 @@ func NewDataDecoder() *DataDecoder {
 //   - 0010: Alphanumeric
 //   - 0100: Byte
 //   - 1000: Kanji
+//   - 1101: Hanzi (GB2312, Chinese QR codes)
+//   - 0111: ECI designator, selects the character set of the following segment
 //   - 0000: End of message (ECI mode or terminator)
 //
 // This implementation focuses on Byte mode (0100), which is the most common
 // for UTF-8 text.
 //
+// Without the version the byte mode character count is assumed to be 8 bits
+// (versions 1-9); use DecodeForVersion when the version is known.
+//
 // Parameters:
 //   - dataBytes: Error-corrected data codewords from error correction step
 //
@@ -45,6 +103,33 @@ func NewDataDecoder() *DataDecoder {
 //   - Decoded message as UTF-8 string
 //   - Error if decoding fails
 func (dd *DataDecoder) Decode(dataBytes []byte) (string, error) {
+	return dd.decode(dataBytes, 0, "")
+}
+
+// DecodeForVersion decodes corrected data bytes from a QR code of known version and EC level
+//
+// Knowing the symbol allows two things Decode cannot do:
+//   - Reading the 16-bit byte mode character count used by versions 10-40
+//   - Rejecting counts larger than the symbol can hold (see MaxDataBytes) with
+//     ErrImpossibleCount, which catches corrupt length fields before reading data
+//
+// Parameters:
+//   - dataBytes: Error-corrected data codewords from error correction step
+//   - version: QR version (1-40)
+//   - ecLevel: Error correction level ("L", "M", "Q" or "H")
+//
+// Returns:
+//   - Decoded message as UTF-8 string
+//   - Error if decoding fails
+func (dd *DataDecoder) DecodeForVersion(dataBytes []byte, version int, ecLevel string) (string, error) {
+	if version < 1 || version > 40 {
+		return "", fmt.Errorf("invalid version %d", version)
+	}
+	return dd.decode(dataBytes, version, ecLevel)
+}
+
+// decode decodes data bytes; version 0 means the symbol version is unknown
+func (dd *DataDecoder) decode(dataBytes []byte, version int, ecLevel string) (string, error) {
 	if len(dataBytes) == 0 {
 		return "", fmt.Errorf("no data to decode")
 	}
@@ -58,16 +143,35 @@ func (dd *DataDecoder) Decode(dataBytes []byte) (string, error) {
 		return "", fmt.Errorf("failed to read mode indicator: %w", err)
 	}
 
+	// An ECI designator only changes how the following segment is interpreted
+	eci := -1
+	if modeIndicator == 0b0111 {
+		eci, err = dd.readECI(bits)
+		if err != nil {
+			return "", err
+		}
+
+		modeIndicator, err = bits.readBits(4)
+		if err != nil {
+			return "", fmt.Errorf("failed to read mode indicator after ECI: %w", err)
+		}
+	}
+
 	// Check mode
 	switch modeIndicator {
 	case 0b0100: // Byte mode
-		return dd.decodeByteMode(bits)
+		if eci >= 0 {
+			return dd.decodeByteModeECI(bits, eci, version, ecLevel)
+		}
+		return dd.decodeByteMode(bits, version, ecLevel)
 	case 0b0001: // Numeric mode
 		return "", fmt.Errorf("numeric mode not yet supported (educational focus is on byte mode)")
 	case 0b0010: // Alphanumeric mode
 		return "", fmt.Errorf("alphanumeric mode not yet supported (educational focus is on byte mode)")
 	case 0b1000: // Kanji mode
 		return "", fmt.Errorf("kanji mode not yet supported (educational focus is on byte mode)")
+	case modeHanzi:
+		return decodeHanziMode(bits, version)
 	case 0b0000: // Terminator or ECI
 		return "", nil // Empty message
 	default:
@@ -80,24 +184,159 @@ func (dd *DataDecoder) Decode(dataBytes []byte) (string, error) {
 // Byte mode format:
 //   [Character count: 8 bits for version 1-9, 16 bits for version 10-40][Data bytes]
 //
-// For simplicity, we assume version 1-9 (8-bit character count).
-// This covers most common QR codes.
+// When the version is unknown (0), an 8-bit count is assumed, which covers
+// versions 1-9 and most common QR codes.
 //
 // Example:
 //   Data: 0100 00001111 01001000 01100101 01101100 01101100 01101111
 //         ^^^^ ^^^^^^^^ ^^^ 8 bytes of "Hello" (15 chars shown above is just example)
 //         mode count    data...
-func (dd *DataDecoder) decodeByteMode(bits *bitStream) (string, error) {
-	// Read character count (8 bits for version 1-9)
-	// For version 10+, this would be 16 bits
-	// TODO: Could take version as parameter to handle this correctly
-	count, err := bits.readBits(8)
+func (dd *DataDecoder) decodeByteMode(bits *bitStream, version int, ecLevel string) (string, error) {
+	dataBytes, err := dd.readByteSegment(bits, version, ecLevel)
+	if err != nil {
+		return "", err
+	}
+
+	if dd.CharsetHeuristic {
+		return decodeGuessedCharset(dataBytes), nil
+	}
+
+	// Convert to UTF-8 string
+	return string(dataBytes), nil
+}
+
+// decodeHanziMode decodes a Hanzi mode segment to UTF-8
+//
+// Hanzi mode format:
+//   [Subset indicator: 4 bits][Character count: 8/10/12 bits, as for Kanji][13 bits per character]
+//
+// Only subset 0001 (GB2312) is defined. Each GB2312 character is a two-byte code
+// in 0xA1A1-0xAAFE or 0xB0A1-0xFAFE. The encoder subtracts 0xA1A1 or 0xA6A1, leaving
+// a high byte h and a low byte l < 0x60, and writes h·0x60 + l in 13 bits. Decoding
+// reverses this; for example, 啊 (0xB0A1) is stored as 0x0A·0x60 + 0x00 = 960.
+//
+// When the version is unknown (0), an 8-bit count is assumed.
+func decodeHanziMode(bits *bitStream, version int) (string, error) {
+	subset, err := bits.readBits(4)
+	if err != nil {
+		return "", fmt.Errorf("failed to read hanzi subset indicator: %w", err)
+	}
+	if subset != hanziSubsetGB2312 {
+		return "", fmt.Errorf("unsupported hanzi subset: %04b", subset)
+	}
+
+	countBits := 8
+	if version > 0 {
+		countBits, err = characterCountBits(ModeKanji, version)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	count, err := bits.readBits(countBits)
 	if err != nil {
 		return "", fmt.Errorf("failed to read character count: %w", err)
 	}
+	if count*13 > bits.available() {
+		return "", fmt.Errorf("%w: %d hanzi characters declared, only %d bits left",
+			ErrImpossibleCount, count, bits.available())
+	}
+
+	gb2312 := make([]byte, 0, 2*count)
+	for i := 0; i < count; i++ {
+		value, err := bits.readBits(13)
+		if err != nil {
+			return "", fmt.Errorf("failed to read hanzi character %d: %w", i, err)
+		}
+		high, low := hanziToGB2312(value)
+		gb2312 = append(gb2312, high, low)
+	}
+
+	// GB2312 is a subset of GBK, whose decoder x/text provides
+	decoded, err := simplifiedchinese.GBK.NewDecoder().Bytes(gb2312)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode GB2312 data: %w", err)
+	}
+	return string(decoded), nil
+}
+
+// hanziToGB2312 reconstructs the two GB2312 bytes from a 13-bit Hanzi mode value
+func hanziToGB2312(value int) (byte, byte) {
+	assembled := (value/0x60)<<8 | value%0x60
+	if assembled < 0x0A00 {
+		assembled += 0xA1A1 // 0xA1A1-0xAAFE: symbols and punctuation
+	} else {
+		assembled += 0xA6A1 // 0xB0A1-0xFAFE: hanzi
+	}
+	return byte(assembled >> 8), byte(assembled)
+}
+
+// decodeGuessedCharset decodes byte mode data without an ECI as UTF-8 or ISO-8859-1
+//
+// Multi-byte UTF-8 sequences have a rigid structure (a lead byte 110xxxxx, 1110xxxx
+// or 11110xxx followed by 10xxxxxx continuation bytes) that Latin-1 text almost
+// never forms by accident: "é" is 0xE9 in Latin-1, which as UTF-8 would need two
+// continuation bytes after it. So valid UTF-8 is taken to be UTF-8, and pure ASCII
+// decodes the same either way.
+func decodeGuessedCharset(dataBytes []byte) string {
+	if bytes.HasPrefix(dataBytes, utf8BOM) {
+		return string(dataBytes[len(utf8BOM):])
+	}
+	if utf8.Valid(dataBytes) {
+		return string(dataBytes)
+	}
+	return decodeISO8859_1(dataBytes)
+}
+
+// decodeISO8859_1 converts ISO-8859-1 bytes to a string
+//
+// ISO-8859-1 byte values are exactly the first 256 Unicode code points.
+func decodeISO8859_1(dataBytes []byte) string {
+	runes := make([]rune, len(dataBytes))
+	for i, b := range dataBytes {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+// readByteSegment reads the character count and data bytes of a byte mode segment
+//
+// When the version is known, the count is checked against MaxDataBytes before any
+// data is read, and against MaxMessageBytes when set.
+func (dd *DataDecoder) readByteSegment(bits *bitStream, version int, ecLevel string) ([]byte, error) {
+	// Read character count (8 bits for version 1-9, 16 bits for version 10+)
+	countBits := 8
+	if version > 0 {
+		var err error
+		countBits, err = characterCountBits(ModeByte, version)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	count, err := bits.readBits(countBits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read character count: %w", err)
+	}
+
+	if version > 0 {
+		maxBytes, err := MaxDataBytes(version, ecLevel, ModeByte)
+		if err != nil {
+			return nil, err
+		}
+		if count > maxBytes {
+			return nil, fmt.Errorf("%w: %d bytes declared, version %d-%s holds at most %d",
+				ErrImpossibleCount, count, version, ecLevel, maxBytes)
+		}
+	}
+
+	if dd.MaxMessageBytes > 0 && count > dd.MaxMessageBytes {
+		return nil, fmt.Errorf("%w: %d bytes declared, limit is %d",
+			ErrMessageTooLong, count, dd.MaxMessageBytes)
+	}
 
 	if count == 0 {
-		return "", nil
+		return []byte{}, nil
 	}
 
 	// Read data bytes
@@ -105,13 +344,88 @@ func (dd *DataDecoder) decodeByteMode(bits *bitStream) (string, error) {
 	for i := 0; i < count; i++ {
 		b, err := bits.readBits(8)
 		if err != nil {
-			return "", fmt.Errorf("failed to read data byte %d: %w", i, err)
+			return nil, fmt.Errorf("failed to read data byte %d: %w", i, err)
 		}
 		dataBytes[i] = byte(b)
 	}
 
-	// Convert to UTF-8 string
-	return string(dataBytes), nil
+	return dataBytes, nil
+}
+
+// readECI reads an ECI designator following the 0111 mode indicator
+//
+// The assignment number is encoded in 1 to 3 bytes, with the leading bits
+// giving the length:
+//
+//	0xxxxxxx                    0-127
+//	10xxxxxx xxxxxxxx           0-16383
+//	110xxxxx xxxxxxxx xxxxxxxx  0-999999
+//
+// In strict mode, assignments outside the supported set return ErrUnsupportedECI.
+func (dd *DataDecoder) readECI(bits *bitStream) (int, error) {
+	first, err := bits.readBits(8)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read ECI designator: %w", err)
+	}
+
+	var eci int
+	switch {
+	case first&0x80 == 0:
+		eci = first
+	case first&0xC0 == 0x80:
+		rest, err := bits.readBits(8)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read ECI designator: %w", err)
+		}
+		eci = (first&0x3F)<<8 | rest
+	case first&0xE0 == 0xC0:
+		rest, err := bits.readBits(16)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read ECI designator: %w", err)
+		}
+		eci = (first&0x1F)<<16 | rest
+	default:
+		return 0, fmt.Errorf("invalid ECI designator: %08b", first)
+	}
+
+	if dd.StrictECI && !isSupportedECI(eci) {
+		return 0, fmt.Errorf("%w: %06d", ErrUnsupportedECI, eci)
+	}
+
+	return eci, nil
+}
+
+// isSupportedECI reports whether the decoder can convert an ECI character set to UTF-8
+func isSupportedECI(eci int) bool {
+	switch eci {
+	case eciISO8859_1Legacy, eciISO8859_1, eciShiftJIS, eciUTF8:
+		return true
+	default:
+		return false
+	}
+}
+
+// decodeByteModeECI decodes a byte mode segment in the character set selected by an ECI
+//
+// Unsupported assignments (only reachable when StrictECI is off) fall back to the raw bytes.
+func (dd *DataDecoder) decodeByteModeECI(bits *bitStream, eci int, version int, ecLevel string) (string, error) {
+	dataBytes, err := dd.readByteSegment(bits, version, ecLevel)
+	if err != nil {
+		return "", err
+	}
+
+	switch eci {
+	case eciISO8859_1Legacy, eciISO8859_1:
+		return decodeISO8859_1(dataBytes), nil
+	case eciShiftJIS:
+		decoded, err := japanese.ShiftJIS.NewDecoder().Bytes(dataBytes)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode Shift-JIS data: %w", err)
+		}
+		return string(decoded), nil
+	default:
+		return string(dataBytes), nil
+	}
 }
 
 // bitStream provides bit-level reading of byte data
@@ -2,8 +2,11 @@ package decoder
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/jalphad/abstract_algebra/qrcode/types"
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode/decoder"
 )
 
 // Decoder provides the complete QR code decoding pipeline
@@ -47,6 +50,24 @@ func (d *Decoder) SetVerbose(verbose bool) {
 	d.verbose = verbose
 }
 
+// SetCaptureBlocks enables or disables capturing the codewords of each RS block
+//
+// When enabled, every BlockResult also holds the block's data and EC codewords
+// as received (ReceivedCodewords), next to the always available
+// CorrectedCodewords. WriteBlockDump prints both in hex for debugging
+// extraction and interleaving.
+func (d *Decoder) SetCaptureBlocks(capture bool) {
+	d.errorCorrector.captureBlocks = capture
+}
+
+// SetMaxMessageBytes limits the length of decoded messages
+//
+// A segment declaring more than maxBytes bytes fails with ErrMessageTooLong
+// instead of being read. Zero (the default) disables the limit.
+func (d *Decoder) SetMaxMessageBytes(maxBytes int) {
+	d.dataDecoder.MaxMessageBytes = maxBytes
+}
+
 // Decode performs the complete QR code decoding pipeline
 //
 // Steps:
@@ -61,7 +82,9 @@ func (d *Decoder) SetVerbose(verbose bool) {
 //   - qrData: Raw QR code data from the extractor (includes codewords, version, EC level, etc.)
 //
 // Returns:
-//   - DecodeResult containing the message and error correction statistics
+//   - DecodeResult containing the message and error correction statistics. If a
+//     block cannot be corrected, a partial result with CorrectionSuccessful false
+//     and the BlockResults of all blocks is returned together with the error
 //   - Error if decoding fails (e.g., too many errors to correct)
 //
 // Example Usage:
@@ -90,7 +113,7 @@ func (d *Decoder) Decode(qrData *types.QRCodeData) (*DecodeResult, error) {
 	}
 
 	correctedData, blockResults, err := d.errorCorrector.CorrectCodewords(qrData)
-	if err != nil {
+	if err != nil && blockResults == nil {
 		return nil, fmt.Errorf("error correction failed: %w", err)
 	}
 
@@ -111,14 +134,19 @@ func (d *Decoder) Decode(qrData *types.QRCodeData) (*DecodeResult, error) {
 		}
 	}
 
-	if !allBlocksSucceeded {
+	if err != nil || !allBlocksSucceeded {
+		if err != nil {
+			err = fmt.Errorf("error correction failed: %w", err)
+		} else {
+			err = fmt.Errorf("error correction failed for one or more blocks")
+		}
 		return &DecodeResult{
 			Message:              "",
 			CorrectionSuccessful: false,
 			NumErrorsCorrected:   0,
 			ErrorPositions:       allErrorPositions,
 			BlockResults:         blockResults,
-		}, fmt.Errorf("error correction failed for one or more blocks")
+		}, err
 	}
 
 	if d.verbose {
@@ -131,7 +159,7 @@ func (d *Decoder) Decode(qrData *types.QRCodeData) (*DecodeResult, error) {
 		fmt.Println("\n--- Step 2: Data Decoding ---")
 	}
 
-	message, err := d.dataDecoder.Decode(correctedData)
+	message, err := d.dataDecoder.DecodeForVersion(correctedData, qrData.Version.GetVersionNumber(), qrData.ECLevel.String())
 	if err != nil {
 		return nil, fmt.Errorf("data decoding failed: %w", err)
 	}
@@ -148,6 +176,9 @@ func (d *Decoder) Decode(qrData *types.QRCodeData) (*DecodeResult, error) {
 		NumErrorsCorrected:   totalErrors,
 		ErrorPositions:       allErrorPositions,
 		BlockResults:         blockResults,
+		DataMask:             qrData.DataMask,
+		ECLevel:              qrData.ECLevel.String(),
+		Confidence:           decodeConfidence(blockResults),
 	}
 
 	if d.verbose {
@@ -157,6 +188,56 @@ func (d *Decoder) Decode(qrData *types.QRCodeData) (*DecodeResult, error) {
 	return result, nil
 }
 
+// DecodeWithRaw decodes a QR code twice: once with error correction and once without
+//
+// The raw result parses the data codewords exactly as they were read, skipping
+// Reed-Solomon correction, to show what error correction buys: on a damaged code
+// the raw message is garbled or cannot be parsed at all, while the corrected one
+// is intact. The raw decode is best-effort; if the damaged data cannot be parsed
+// its Message is empty, and no error is reported for it.
+//
+// Parameters:
+//   - qrData: Raw QR code data from the extractor
+//
+// Returns:
+//   - corrected: The result of Decode (nil or partial if correction fails)
+//   - raw: The message decoded from the uncorrected data codewords
+//   - err: The error from Decode, if any; raw is returned regardless
+//
+// Example:
+//
+//	corrected, raw, err := decoder.DecodeWithRaw(qrData)
+//	fmt.Printf("without correction: %q\n", raw.Message)
+//	fmt.Printf("with correction:    %q\n", corrected.Message)
+func (d *Decoder) DecodeWithRaw(qrData *types.QRCodeData) (corrected, raw *DecodeResult, err error) {
+	if qrData == nil || qrData.Version == nil {
+		return nil, nil, fmt.Errorf("missing version information")
+	}
+	if expected := qrData.Version.GetTotalCodewords(); len(qrData.RawCodewords) != expected {
+		return nil, nil, fmt.Errorf("version %d expects %d codewords, got %d",
+			qrData.Version.GetVersionNumber(), expected, len(qrData.RawCodewords))
+	}
+
+	// The data codewords of every block, uncorrected, in block order
+	ecBlocks := qrData.Version.GetECBlocksForLevel(qrData.ECLevel)
+	blocks := d.errorCorrector.deinterleaveBlocks(qrData.RawCodewords, ecBlocks)
+	for i, block := range blocks {
+		blocks[i] = block[:len(block)-ecBlocks.GetECCodewordsPerBlock()]
+	}
+
+	raw = &DecodeResult{
+		DataMask: qrData.DataMask,
+		ECLevel:  qrData.ECLevel.String(),
+	}
+	if message, decodeErr := d.dataDecoder.DecodeForVersion(concatenateBlocks(blocks),
+		qrData.Version.GetVersionNumber(), qrData.ECLevel.String()); decodeErr == nil {
+		raw.Message = message
+	}
+
+	corrected, err = d.Decode(qrData)
+	return corrected, raw, err
+}
+
 // DecodeWithStats is a convenience method that decodes and prints statistics
 //
 // This is useful for educational demonstrations where you want to show
@@ -188,3 +269,286 @@ func (d *Decoder) DecodeWithStats(qrData *types.QRCodeData) (*DecodeResult, erro
 
 	return result, nil
 }
+
+// DecodeBits runs error correction and returns the corrected data bits
+//
+// Unlike Decode, this does not interpret the mode indicators or segments. The
+// returned slice holds the bits of the corrected data codewords, most significant
+// bit of each codeword first, which makes it a convenient input for experimenting
+// with custom segment parsers.
+//
+// Parameters:
+//   - qrData: Raw QR code data from the extractor
+//
+// Returns:
+//   - The corrected data bits (8 bits per data codeword, MSB-first)
+//   - Error if error correction fails
+//
+// Example:
+//
+//	bits, err := decoder.DecodeBits(qrData)
+//	// bits[0:4] holds the mode indicator of the first segment
+func (d *Decoder) DecodeBits(qrData *types.QRCodeData) ([]bool, error) {
+	correctedData, blockResults, err := d.errorCorrector.CorrectCodewords(qrData)
+	if err != nil {
+		return nil, fmt.Errorf("error correction failed: %w", err)
+	}
+
+	for _, blockResult := range blockResults {
+		if !blockResult.CorrectionSucceeded {
+			return nil, fmt.Errorf("error correction failed for block %d", blockResult.BlockIndex)
+		}
+	}
+
+	bits := make([]bool, 0, len(correctedData)*8)
+	for _, b := range correctedData {
+		for i := 7; i >= 0; i-- {
+			bits = append(bits, (b>>i)&1 == 1)
+		}
+	}
+
+	return bits, nil
+}
+
+// Capabilities reports how much damage a QR code can recover from, without decoding it
+//
+// Each Reed-Solomon block with e EC codewords can correct (e - p) / 2 symbol
+// errors, where p is the number of codewords the specification reserves for
+// misdecode protection (non-zero only for versions 1-3). Summing over all blocks
+// gives the total number of correctable symbols, and dividing by the total number
+// of codewords gives the familiar recovery percentages:
+//   - Level L: ~7%
+//   - Level M: ~15%
+//   - Level Q: ~25%
+//   - Level H: ~30%
+//
+// Parameters:
+//   - qrData: Raw QR code data from the extractor (only version and EC level are used)
+//
+// Returns:
+//   - maxCorrectableSymbols: Total number of correctable codewords across all blocks
+//   - maxCorrectablePercent: That number as a percentage of the total codewords
+//   - err: Error if the version information is missing
+func (d *Decoder) Capabilities(qrData *types.QRCodeData) (maxCorrectableSymbols int, maxCorrectablePercent float64, err error) {
+	if qrData == nil || qrData.Version == nil {
+		return 0, 0, fmt.Errorf("missing version information")
+	}
+
+	ecBlocks := qrData.Version.GetECBlocksForLevel(qrData.ECLevel)
+	if ecBlocks == nil {
+		return 0, 0, fmt.Errorf("no block structure for error correction level %s", qrData.ECLevel)
+	}
+
+	protection := misdecodeProtectionCodewords(qrData.Version.GetVersionNumber(), qrData.ECLevel)
+	perBlock := (ecBlocks.GetECCodewordsPerBlock() - protection) / 2
+	maxCorrectableSymbols = perBlock * ecBlocks.GetNumBlocks()
+
+	totalCodewords := qrData.Version.GetTotalCodewords()
+	maxCorrectablePercent = float64(maxCorrectableSymbols) / float64(totalCodewords) * 100
+
+	return maxCorrectableSymbols, maxCorrectablePercent, nil
+}
+
+// AnalyzeOnly runs error correction and reports the per-block statistics without decoding the message
+//
+// This is intended for forensic tools that need to know how damaged a code is
+// (e.g. "3 errors corrected across 2 blocks") without reconstructing its possibly
+// sensitive content. The corrected data is discarded and never parsed.
+//
+// Parameters:
+//   - qrData: Raw QR code data from the extractor
+//
+// Returns:
+//   - The error correction result for every RS block, also when a block cannot be
+//     corrected: such a block has DetectedUncorrectable set
+//   - Error if error correction fails (the block results are still returned unless the
+//     codewords do not match the version at all)
+func (d *Decoder) AnalyzeOnly(qrData *types.QRCodeData) ([]BlockResult, error) {
+	_, blockResults, err := d.errorCorrector.CorrectCodewords(qrData)
+	if err != nil {
+		// blockResults is nil only if correction could not start, e.g. for a truncated read
+		return blockResults, fmt.Errorf("error correction failed: %w", err)
+	}
+
+	for _, blockResult := range blockResults {
+		if !blockResult.CorrectionSucceeded {
+			return blockResults, fmt.Errorf("error correction failed for one or more blocks")
+		}
+	}
+
+	return blockResults, nil
+}
+
+// BurstErrorSpan maps the corrected errors back to raw codeword indices and returns their span
+//
+// Correction happens per block, so BlockResult.ErrorPositions are relative to the
+// de-interleaved blocks. Physical damage, however, is contiguous in the interleaved
+// stream: a smudge across 6 raw codewords of a 2-block code shows up as 3 errors
+// in each block. This reverses the interleaving (see CodewordBlockMap) to recover
+// the physical extent of the damage.
+//
+// Parameters:
+//   - result: A successful decode result of qrData
+//   - qrData: The QR code data the result was decoded from
+//
+// Returns:
+//   - start, end: The smallest and largest raw codeword index with a corrected error (inclusive)
+//   - err: Error if no errors were corrected or the result does not match qrData
+//
+// Example:
+//
+//	result, _ := decoder.Decode(qrData)
+//	start, end, err := decoder.BurstErrorSpan(result, qrData)
+//	fmt.Printf("damage spans raw codewords %d-%d\n", start, end)
+func (d *Decoder) BurstErrorSpan(result *DecodeResult, qrData *types.QRCodeData) (start, end int, err error) {
+	if result == nil || qrData == nil || qrData.Version == nil {
+		return 0, 0, fmt.Errorf("missing decode result or version information")
+	}
+
+	// Invert the raw index -> block position mapping
+	rawIndex := make(map[BlockPos]int)
+	for r, pos := range d.errorCorrector.CodewordBlockMap(qrData) {
+		rawIndex[pos] = r
+	}
+
+	start, end = -1, -1
+	for _, block := range result.BlockResults {
+		blockLength := block.NumDataCodewords + block.NumECCodewords
+		for _, pos := range block.ErrorPositions {
+			// ErrorPositions use the standard convention (x^pos); blocks store the
+			// highest degree coefficient first
+			r, ok := rawIndex[BlockPos{Block: block.BlockIndex, Position: blockLength - 1 - pos}]
+			if !ok {
+				return 0, 0, fmt.Errorf("error position %d of block %d does not match the QR code's block structure",
+					pos, block.BlockIndex)
+			}
+			if start < 0 || r < start {
+				start = r
+			}
+			end = max(end, r)
+		}
+	}
+
+	if start < 0 {
+		return 0, 0, fmt.Errorf("no errors were corrected")
+	}
+	return start, end, nil
+}
+
+// candidateECLevels lists the error correction levels DecodeCandidates tries, in order
+var candidateECLevels = []decoder.ErrorCorrectionLevel{
+	decoder.ErrorCorrectionLevel_L,
+	decoder.ErrorCorrectionLevel_M,
+	decoder.ErrorCorrectionLevel_Q,
+	decoder.ErrorCorrectionLevel_H,
+}
+
+// DecodeCandidates decodes the code under every mask and EC level combination and returns all that succeed
+//
+// The format information (mask and EC level) is only protected by a short BCH code,
+// so in a damaged code it may be wrong or ambiguous. Instead of trusting it, this
+// re-reads the codewords under each of the 8 masks and corrects them under each of
+// the 4 EC levels, keeping every combination whose blocks all verify and whose data
+// then decodes into a message. Candidates are sorted by total errors corrected
+// (ties keep the format information's combination first), and each carries its
+// DataMask, ECLevel and Confidence.
+//
+// A clean code normally yields exactly one candidate. Note that single-block codes
+// (e.g. version 1) also verify at every EC level below their own, because the
+// generator polynomial of a lower level divides that of a higher one: a 1-H code
+// yields candidates for H, Q, M and L.
+//
+// Parameters:
+//   - qrData: Raw QR code data from the extractor (BitMatrix, Version and DataMask are used)
+//
+// Returns:
+//   - All candidate decodings, fewest corrected errors first
+//   - Error if the data has no matrix or no combination decodes
+func (d *Decoder) DecodeCandidates(qrData *types.QRCodeData) ([]*DecodeResult, error) {
+	if qrData == nil || qrData.Version == nil || qrData.BitMatrix == nil {
+		return nil, fmt.Errorf("QR code data has no version or bit matrix")
+	}
+
+	version := qrData.Version
+	var candidates []*DecodeResult
+	for mask := byte(0); mask < byte(len(decoder.DataMaskValues)); mask++ {
+		rawCodewords, err := readCodewordsWithMask(qrData, mask)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ecLevel := range candidateECLevels {
+			candidate := *qrData
+			candidate.RawCodewords = rawCodewords
+			candidate.ECLevel = ecLevel
+			candidate.DataMask = mask
+
+			correctedData, blockResults, err := d.errorCorrector.CorrectCodewords(&candidate)
+			if err != nil {
+				continue
+			}
+
+			message, err := d.dataDecoder.DecodeForVersion(correctedData, version.GetVersionNumber(), ecLevel.String())
+			if err != nil {
+				continue
+			}
+
+			result := &DecodeResult{
+				Message:              message,
+				CorrectionSuccessful: true,
+				BlockResults:         blockResults,
+				DataMask:             mask,
+				ECLevel:              ecLevel.String(),
+				Confidence:           decodeConfidence(blockResults),
+			}
+			for _, block := range blockResults {
+				result.NumErrorsCorrected += block.ErrorsFound
+				result.ErrorPositions = append(result.ErrorPositions, block.ErrorPositions...)
+			}
+			candidates = append(candidates, result)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no mask and error correction level combination decodes")
+	}
+
+	// The format information's own combination comes first among equals
+	isFormatCombination := func(r *DecodeResult) bool {
+		return r.DataMask == qrData.DataMask && r.ECLevel == qrData.ECLevel.String()
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].NumErrorsCorrected != candidates[j].NumErrorsCorrected {
+			return candidates[i].NumErrorsCorrected < candidates[j].NumErrorsCorrected
+		}
+		return isFormatCombination(candidates[i]) && !isFormatCombination(candidates[j])
+	})
+
+	return candidates, nil
+}
+
+// readCodewordsWithMask reads the codewords of qrData as if the code had been masked with mask
+//
+// qrData.BitMatrix was unmasked with qrData.DataMask, so a module must be flipped
+// wherever exactly one of the two masks applies.
+func readCodewordsWithMask(qrData *types.QRCodeData, mask byte) ([]byte, error) {
+	dimension := qrData.BitMatrix.GetHeight()
+	difference, err := gozxing.NewSquareBitMatrix(dimension)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bit matrix: %w", err)
+	}
+	decoder.DataMaskValues[qrData.DataMask].UnmaskBitMatrix(difference, dimension)
+	decoder.DataMaskValues[mask].UnmaskBitMatrix(difference, dimension)
+
+	codewords := make([]byte, qrData.Version.GetTotalCodewords())
+	for bitIndex, pos := range types.DataModulePositions(qrData.Version) {
+		if bitIndex >= len(codewords)*8 {
+			break
+		}
+		if qrData.BitMatrix.Get(pos.Col, pos.Row) != difference.Get(pos.Col, pos.Row) {
+			codewords[bitIndex/8] |= 0x80 >> (bitIndex % 8)
+		}
+	}
+
+	return codewords, nil
+}
@@ -1,13 +1,19 @@
 package decoder
 
 import (
+	"fmt"
 	"image"
 	"image/color"
+	"strings"
 	"testing"
 
+	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
+	"github.com/jalphad/abstract_algebra/exercises/5-syndrome"
+	"github.com/jalphad/abstract_algebra/qrcode/correction"
 	"github.com/jalphad/abstract_algebra/qrcode/types"
 	"github.com/makiuchi-d/gozxing"
 	"github.com/makiuchi-d/gozxing/qrcode"
+	zxingdecoder "github.com/makiuchi-d/gozxing/qrcode/decoder"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -117,6 +123,23 @@ func TestDecoder_DifferentECLevels(t *testing.T) {
 	}
 }
 
+// TestDecoder_MultipleBlocks tests that the data of a code with several RS blocks is
+// decoded in block order rather than in the interleaved order of the symbol
+func TestDecoder_MultipleBlocks(t *testing.T) {
+	testMessage := "Several blocks of different sizes must be joined in block order"
+	qrData := createTestQRCode(t, testMessage, gozxing.EncodeHintType_ERROR_CORRECTION, "Q")
+	require.Greater(t, qrData.Version.GetECBlocksForLevel(qrData.ECLevel).GetNumBlocks(), 1)
+
+	decoder, err := NewDecoder()
+	require.NoError(t, err)
+
+	result, err := decoder.Decode(qrData)
+	require.NoError(t, err)
+
+	assert.Equal(t, testMessage, result.Message)
+	assert.True(t, result.CorrectionSuccessful)
+}
+
 // TestDecoder_Verbose tests verbose mode
 func TestDecoder_Verbose(t *testing.T) {
 	testMessage := "Verbose test"
@@ -242,6 +265,61 @@ func TestErrorCorrector_GF256Field(t *testing.T) {
 	assert.True(t, sum.IsZero())
 }
 
+// TestErrorCorrector_ByteConversion tests that elementToByte inverts byteToElement for every byte
+func TestErrorCorrector_ByteConversion(t *testing.T) {
+	ec, err := NewErrorCorrector()
+	require.NoError(t, err)
+
+	for b := 0; b < 256; b++ {
+		assert.Equal(t, byte(b), ec.elementToByte(ec.byteToElement(byte(b))), "byte 0x%02X", b)
+	}
+
+	// α^8 = α^4 + α^3 + α^2 + 1 is 0x1D under the polynomial 0x11D
+	assert.Equal(t, byte(0x1D), ec.elementToByte(ec.field.Exp(8)))
+}
+
+// bruteForceElementToByte is the previous elementToByte, which searched all bytes for a match
+func bruteForceElementToByte(ec *ErrorCorrector, elem gfpn.Element) byte {
+	if elem.IsZero() {
+		return 0
+	}
+	for b := byte(1); b != 0; b++ {
+		if ec.byteToElement(b).Equal(elem) {
+			return b
+		}
+	}
+	return 0
+}
+
+// TestErrorCorrector_ElementToByteMatchesBruteForce tests that the log table lookup agrees
+// with searching every byte, for all 256 elements
+func TestErrorCorrector_ElementToByteMatchesBruteForce(t *testing.T) {
+	ec, err := NewErrorCorrector()
+	require.NoError(t, err)
+
+	for _, elem := range ec.field.Elements() {
+		assert.Equal(t, bruteForceElementToByte(ec, elem), ec.elementToByte(elem), "element %s", elem)
+	}
+}
+
+// BenchmarkElementToByte compares the log table lookup against searching every byte
+func BenchmarkElementToByte(b *testing.B) {
+	ec, err := NewErrorCorrector()
+	require.NoError(b, err)
+	elements := ec.field.Elements()
+
+	b.Run("LogTable", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ec.elementToByte(elements[i%len(elements)])
+		}
+	})
+	b.Run("BruteForce", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bruteForceElementToByte(ec, elements[i%len(elements)])
+		}
+	})
+}
+
 // createTestQRCode creates a QR code for testing
 func createTestQRCode(t *testing.T, content string, hintType gozxing.EncodeHintType, level string) *types.QRCodeData {
 	// Create QR code with specified error correction level
@@ -266,6 +344,14 @@ func createTestQRCode(t *testing.T, content string, hintType gozxing.EncodeHintT
 	return qrData
 }
 
+// encodeTestBlock appends the EC codewords of the error corrector's code to a block of data codewords
+func encodeTestBlock(t testing.TB, ec *ErrorCorrector, data []byte, numEC int) []byte {
+	t.Helper()
+	block, err := ec.encodeBlock(data, numEC)
+	require.NoError(t, err)
+	return block
+}
+
 // bitMatrixToImage converts a BitMatrix to an image
 func bitMatrixToImage(matrix *gozxing.BitMatrix) image.Image {
 	width := matrix.GetWidth()
@@ -328,3 +414,1078 @@ func BenchmarkDecode(b *testing.B) {
 		}
 	}
 }
+
+// TestMinimumVersion tests choosing the smallest version for a payload
+func TestMinimumVersion(t *testing.T) {
+	// A short message fits comfortably in version 1
+	version, err := MinimumVersion("Hello, World!", "L", ModeByte)
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+
+	// Version 1-L holds 19 data codewords: 4 + 8 + 17*8 = 148 bits fits, 18 bytes does not
+	version, err = MinimumVersion(strings.Repeat("a", 17), "L", ModeByte)
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+
+	version, err = MinimumVersion(strings.Repeat("a", 18), "L", ModeByte)
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	// A long message needs a much higher version, more so at a higher EC level
+	long := strings.Repeat("This is a longer message. ", 20)
+	versionL, err := MinimumVersion(long, "L", ModeByte)
+	require.NoError(t, err)
+	versionH, err := MinimumVersion(long, "H", ModeByte)
+	require.NoError(t, err)
+	assert.Greater(t, versionL, 1)
+	assert.Greater(t, versionH, versionL)
+
+	// Numeric mode packs digits more densely than byte mode
+	digits := strings.Repeat("1234567890", 5)
+	versionNumeric, err := MinimumVersion(digits, "M", ModeNumeric)
+	require.NoError(t, err)
+	versionByte, err := MinimumVersion(digits, "M", ModeByte)
+	require.NoError(t, err)
+	assert.Less(t, versionNumeric, versionByte)
+}
+
+// TestMinimumVersion_Errors tests messages that cannot be encoded
+func TestMinimumVersion_Errors(t *testing.T) {
+	_, err := MinimumVersion(strings.Repeat("a", 3000), "L", ModeByte)
+	assert.Error(t, err)
+
+	_, err = MinimumVersion("abc", "L", ModeNumeric)
+	assert.Error(t, err)
+
+	_, err = MinimumVersion("abc", "X", ModeByte)
+	assert.Error(t, err)
+}
+
+// TestDecoder_DecodeBits tests retrieving the raw corrected data bits
+func TestDecoder_DecodeBits(t *testing.T) {
+	qrData := createTestQRCode(t, "Bits", gozxing.EncodeHintType_ERROR_CORRECTION, "L")
+
+	decoder, err := NewDecoder()
+	require.NoError(t, err)
+
+	bits, err := decoder.DecodeBits(qrData)
+	require.NoError(t, err)
+
+	// Version 1-L has 19 data codewords
+	assert.Len(t, bits, 19*8)
+
+	// Byte mode indicator: 0100
+	assert.Equal(t, []bool{false, true, false, false}, bits[:4])
+
+	// Character count: 4 (00000100)
+	assert.Equal(t, []bool{false, false, false, false, false, true, false, false}, bits[4:12])
+}
+
+// TestDecoder_Capabilities tests the reported recovery capacity per EC level
+func TestDecoder_Capabilities(t *testing.T) {
+	decoder, err := NewDecoder()
+	require.NoError(t, err)
+
+	// Version 1 has 26 codewords; expected symbols follow the ISO/IEC 18004 table
+	tests := []struct {
+		level           string
+		expectedSymbols int
+		documentedPct   float64
+	}{
+		{"L", 2, 7},
+		{"M", 4, 15},
+		{"Q", 6, 25},
+		{"H", 8, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run("Level"+tt.level, func(t *testing.T) {
+			qrData := createTestQRCode(t, "Cap", gozxing.EncodeHintType_ERROR_CORRECTION, tt.level)
+			require.Equal(t, 1, qrData.Version.GetVersionNumber())
+
+			symbols, percent, err := decoder.Capabilities(qrData)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedSymbols, symbols)
+			assert.InDelta(t, tt.documentedPct, percent, 2.5)
+		})
+	}
+}
+
+// TestDecoder_Capabilities_MissingVersion tests that missing metadata is reported
+func TestDecoder_Capabilities_MissingVersion(t *testing.T) {
+	decoder, err := NewDecoder()
+	require.NoError(t, err)
+
+	_, _, err = decoder.Capabilities(&types.QRCodeData{})
+	assert.Error(t, err)
+}
+
+// TestDecoder_AnalyzeOnly tests reporting block statistics without the message
+func TestDecoder_AnalyzeOnly(t *testing.T) {
+	qrData := createTestQRCode(t, "Sensitive content", gozxing.EncodeHintType_ERROR_CORRECTION, "L")
+	qrData.RawCodewords[2] ^= 0x5A
+
+	decoder, err := NewDecoder()
+	require.NoError(t, err)
+
+	blockResults, err := decoder.AnalyzeOnly(qrData)
+	require.NoError(t, err)
+
+	result, err := decoder.Decode(qrData)
+	require.NoError(t, err)
+
+	assert.Equal(t, result.BlockResults, blockResults)
+
+	totalErrors := 0
+	for _, block := range blockResults {
+		totalErrors += block.ErrorsFound
+	}
+	assert.Equal(t, result.NumErrorsCorrected, totalErrors)
+	assert.Equal(t, 1, totalErrors)
+}
+
+func TestDecoder_AnalyzeOnly_Uncorrectable(t *testing.T) {
+	qrData := createTestQRCode(t, "Damaged beyond repair", gozxing.EncodeHintType_ERROR_CORRECTION, "L")
+
+	decoder, err := NewDecoder()
+	require.NoError(t, err)
+
+	// A clean code is analyzed without any block flagged
+	blockResults, err := decoder.AnalyzeOnly(qrData)
+	require.NoError(t, err)
+	for _, block := range blockResults {
+		assert.False(t, block.DetectedUncorrectable)
+	}
+
+	// Version 2-L has a single block with 10 EC codewords, correcting up to 4 errors
+	for i := 0; i < 12; i++ {
+		qrData.RawCodewords[2*i] ^= 0x96
+	}
+
+	blockResults, err = decoder.AnalyzeOnly(qrData)
+	require.Error(t, err)
+	require.Len(t, blockResults, 1)
+	assert.False(t, blockResults[0].CorrectionSucceeded)
+	assert.True(t, blockResults[0].DetectedUncorrectable)
+	assert.Nil(t, blockResults[0].CorrectedCodewords)
+}
+
+func TestErrorCorrector_TruncatedCodewords(t *testing.T) {
+	qrData := createTestQRCode(t, "Truncated", gozxing.EncodeHintType_ERROR_CORRECTION, "L")
+	qrData.RawCodewords = qrData.RawCodewords[:len(qrData.RawCodewords)-5]
+
+	ec, err := NewErrorCorrector()
+	require.NoError(t, err)
+
+	var correctErr error
+	assert.NotPanics(t, func() {
+		_, _, correctErr = ec.CorrectCodewords(qrData)
+	})
+	require.Error(t, correctErr)
+	assert.Contains(t, correctErr.Error(), "expects 26 codewords, got 21")
+}
+
+func TestErrorCorrector_RebuildBitMatrix(t *testing.T) {
+	ec, err := NewErrorCorrector()
+	require.NoError(t, err)
+
+	// A single block, and several interleaved blocks of different sizes
+	for _, tt := range []struct{ message, level string }{
+		{"Rebuild me", "L"},
+		{"Rebuilding a code with several blocks of different sizes", "Q"},
+	} {
+		qrData := createTestQRCode(t, tt.message, gozxing.EncodeHintType_ERROR_CORRECTION, tt.level)
+
+		// The extractor unmasks in place, so mask a copy to recover the symbol's modules
+		dimension := qrData.BitMatrix.GetHeight()
+		original, err := gozxing.NewBitMatrix(dimension, dimension)
+		require.NoError(t, err)
+		require.NoError(t, original.Xor(qrData.BitMatrix))
+		zxingdecoder.DataMaskValues[qrData.DataMask].UnmaskBitMatrix(original, dimension)
+
+		correctedData, _, err := ec.CorrectCodewords(qrData)
+		require.NoError(t, err)
+
+		rebuilt, err := ec.RebuildBitMatrix(qrData, correctedData)
+		require.NoError(t, err)
+
+		require.Equal(t, dimension, rebuilt.GetHeight())
+		for y := 0; y < dimension; y++ {
+			for x := 0; x < dimension; x++ {
+				require.Equal(t, original.Get(x, y), rebuilt.Get(x, y), "%s: module (%d, %d)", tt.message, x, y)
+			}
+		}
+	}
+}
+
+func TestErrorCorrector_RebuildBitMatrix_WrongLength(t *testing.T) {
+	qrData := createTestQRCode(t, "Rebuild me", gozxing.EncodeHintType_ERROR_CORRECTION, "L")
+
+	ec, err := NewErrorCorrector()
+	require.NoError(t, err)
+
+	_, err = ec.RebuildBitMatrix(qrData, []byte{0x40})
+	assert.Error(t, err)
+}
+
+func TestErrorCorrector_EncodeCodewords(t *testing.T) {
+	ec, err := NewErrorCorrector()
+	require.NoError(t, err)
+
+	// RSEncoder's EC codewords, interleaved, must match the symbol gozxing encoded
+	for _, tt := range []struct{ message, level string }{
+		{"Encode me", "M"},
+		{"Encoding a code with several blocks of different sizes", "Q"},
+	} {
+		qrData := createTestQRCode(t, tt.message, gozxing.EncodeHintType_ERROR_CORRECTION, tt.level)
+
+		data, _, err := ec.CorrectCodewords(qrData)
+		require.NoError(t, err)
+
+		rawCodewords, err := ec.EncodeCodewords(data, qrData.Version, qrData.ECLevel)
+		require.NoError(t, err)
+		assert.Equal(t, qrData.RawCodewords, rawCodewords, tt.message)
+	}
+
+	version, err := zxingdecoder.Version_GetVersionForNumber(1)
+	require.NoError(t, err)
+	_, err = ec.EncodeCodewords([]byte{0x40}, version, zxingdecoder.ErrorCorrectionLevel_L)
+	assert.Error(t, err)
+
+	// With another first root the EC codewords belong to that code, not to QR's
+	data := make([]byte, 19)
+	for i := range data {
+		data[i] = byte(i*13 + 1)
+	}
+	qrCodewords, err := ec.EncodeCodewords(data, version, zxingdecoder.ErrorCorrectionLevel_L)
+	require.NoError(t, err)
+	shifted, err := NewErrorCorrectorFCR(1)
+	require.NoError(t, err)
+	shiftedCodewords, err := shifted.EncodeCodewords(data, version, zxingdecoder.ErrorCorrectionLevel_L)
+	require.NoError(t, err)
+	assert.Equal(t, qrCodewords[:19], shiftedCodewords[:19])
+	assert.NotEqual(t, qrCodewords[19:], shiftedCodewords[19:])
+
+	_, result, err := shifted.CorrectSingleBlock(shiftedCodewords, 7)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.ErrorsFound)
+}
+
+// packBits packs a string of '0' and '1' characters (spaces ignored) into bytes, MSB first
+func packBits(t *testing.T, bits string) []byte {
+	bits = strings.ReplaceAll(bits, " ", "")
+	data := make([]byte, (len(bits)+7)/8)
+	for i, c := range bits {
+		switch c {
+		case '1':
+			data[i/8] |= 0x80 >> (i % 8)
+		case '0':
+		default:
+			t.Fatalf("invalid bit character %q", c)
+		}
+	}
+	return data
+}
+
+func TestDataDecoder_StrictECI(t *testing.T) {
+	// ECI 000009 (ISO-8859-7) + byte mode, count=2, "Hi", terminator
+	exotic := packBits(t, "0111 00001001 0100 00000010 01001000 01101001 0000")
+
+	lax := NewDataDecoder()
+	message, err := lax.Decode(exotic)
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", message)
+
+	strict := &DataDecoder{StrictECI: true}
+	_, err = strict.Decode(exotic)
+	assert.ErrorIs(t, err, ErrUnsupportedECI)
+
+	// ECI 000026 (UTF-8) is on the whitelist
+	utf8ECI := packBits(t, "0111 00011010 0100 00000010 01001000 01101001 0000")
+	message, err = strict.Decode(utf8ECI)
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", message)
+}
+
+func TestDataDecoder_ECI_ISO8859_1(t *testing.T) {
+	// ECI 000003 (ISO-8859-1) + byte mode, count=1, 0xE9 ("é")
+	data := packBits(t, "0111 00000011 0100 00000001 11101001 0000")
+
+	message, err := (&DataDecoder{StrictECI: true}).Decode(data)
+	require.NoError(t, err)
+	assert.Equal(t, "é", message)
+}
+
+// byteModeSegment packs a version 1-9 byte mode segment and terminator
+func byteModeSegment(t *testing.T, payload []byte) []byte {
+	bits := fmt.Sprintf("0100 %08b", len(payload))
+	for _, b := range payload {
+		bits += fmt.Sprintf(" %08b", b)
+	}
+	return packBits(t, bits+" 0000")
+}
+
+func TestDataDecoder_CharsetHeuristic(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload []byte
+		want    string
+	}{
+		{name: "UTF-8 accents", payload: []byte("Café crème"), want: "Café crème"},
+		{name: "Latin-1 accents", payload: []byte{'C', 'a', 'f', 0xE9, ' ', 'c', 'r', 0xE8, 'm', 'e'}, want: "Café crème"},
+		{name: "UTF-8 with BOM", payload: append([]byte{0xEF, 0xBB, 0xBF}, "Grüße"...), want: "Grüße"},
+		{name: "ASCII", payload: []byte("Hello"), want: "Hello"},
+	}
+
+	decoder := &DataDecoder{CharsetHeuristic: true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := decoder.Decode(byteModeSegment(t, tt.payload))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, message)
+		})
+	}
+
+	// Without the heuristic the Latin-1 bytes are returned as-is (invalid UTF-8)
+	message, err := NewDataDecoder().Decode(byteModeSegment(t, tests[1].payload))
+	require.NoError(t, err)
+	assert.Equal(t, string(tests[1].payload), message)
+}
+
+func TestMaxDataBytes(t *testing.T) {
+	tests := []struct {
+		version int
+		ecLevel string
+		mode    Mode
+		want    int
+	}{
+		{1, "L", ModeNumeric, 41},
+		{1, "L", ModeAlphanumeric, 25},
+		{1, "L", ModeByte, 17},
+		{1, "L", ModeKanji, 10},
+		{1, "H", ModeByte, 7},
+		{10, "M", ModeByte, 213},
+		{40, "L", ModeByte, 2953},
+		{40, "L", ModeNumeric, 7089},
+	}
+
+	for _, tt := range tests {
+		got, err := MaxDataBytes(tt.version, tt.ecLevel, tt.mode)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, "version %d-%s %v", tt.version, tt.ecLevel, tt.mode)
+	}
+
+	_, err := MaxDataBytes(41, "L", ModeByte)
+	assert.Error(t, err)
+	_, err = MaxDataBytes(1, "X", ModeByte)
+	assert.Error(t, err)
+}
+
+func TestDataDecoder_ImpossibleCount(t *testing.T) {
+	dd := NewDataDecoder()
+
+	// Byte mode, count=2, "Hi" fits easily in version 1-H (7 bytes)
+	valid := packBits(t, "0100 00000010 01001000 01101001 0000")
+	message, err := dd.DecodeForVersion(valid, 1, "H")
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", message)
+
+	// A corrupted count byte claims 200 bytes, more than version 1-H can hold
+	corrupt := packBits(t, "0100 11001000 01001000 01101001 0000")
+	_, err = dd.DecodeForVersion(corrupt, 1, "H")
+	assert.ErrorIs(t, err, ErrImpossibleCount)
+
+	// Without the version only the available bits limit the read
+	_, err = dd.Decode(corrupt)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrImpossibleCount)
+}
+
+func TestDataDecoder_MaxMessageBytes(t *testing.T) {
+	dd := &DataDecoder{MaxMessageBytes: 5}
+
+	// "Hello" is exactly at the limit
+	message, err := dd.Decode(byteModeSegment(t, []byte("Hello")))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", message)
+
+	// A header claiming 20 bytes is rejected before reading, with or without the version.
+	// Version 5-L could physically hold it, so only the limit catches it.
+	oversized := packBits(t, "0100 00010100 01001000 01101001 0000")
+	_, err = dd.Decode(oversized)
+	assert.ErrorIs(t, err, ErrMessageTooLong)
+	_, err = dd.DecodeForVersion(oversized, 5, "L")
+	assert.ErrorIs(t, err, ErrMessageTooLong)
+
+	// The limit also applies to ECI segments
+	eci := packBits(t, "0111 00011010 0100 00000110 01001000 01100101 01101100 01101100 01101111 00100001 0000")
+	_, err = dd.Decode(eci)
+	assert.ErrorIs(t, err, ErrMessageTooLong)
+
+	// Without a limit the message is decoded
+	message, err = NewDataDecoder().Decode(eci)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello!", message)
+}
+
+func TestDataDecoder_SixteenBitCount(t *testing.T) {
+	// Versions 10+ use a 16-bit byte mode count: 0100 + 0000000000000010 + "Hi"
+	data := packBits(t, "0100 0000000000000010 01001000 01101001 0000")
+
+	message, err := NewDataDecoder().DecodeForVersion(data, 10, "M")
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", message)
+}
+
+func TestStats_Observe(t *testing.T) {
+	qrDataFor := func(version int, level zxingdecoder.ErrorCorrectionLevel) *types.QRCodeData {
+		v, err := zxingdecoder.Version_GetVersionForNumber(version)
+		require.NoError(t, err)
+		return &types.QRCodeData{Version: v, ECLevel: level}
+	}
+
+	stats := NewStats()
+	stats.Observe(&DecodeResult{CorrectionSuccessful: true, NumErrorsCorrected: 0}, qrDataFor(1, zxingdecoder.ErrorCorrectionLevel_L))
+	stats.Observe(&DecodeResult{CorrectionSuccessful: true, NumErrorsCorrected: 3}, qrDataFor(1, zxingdecoder.ErrorCorrectionLevel_M))
+	stats.Observe(&DecodeResult{CorrectionSuccessful: true, NumErrorsCorrected: 5}, qrDataFor(4, zxingdecoder.ErrorCorrectionLevel_H))
+	stats.Observe(&DecodeResult{CorrectionSuccessful: false}, qrDataFor(4, zxingdecoder.ErrorCorrectionLevel_H))
+	stats.Observe(nil, nil) // extraction failed
+
+	assert.Equal(t, 5, stats.Total())
+	assert.Equal(t, 3, stats.Decoded)
+	assert.Equal(t, 2, stats.Failed)
+	assert.Equal(t, 8, stats.TotalErrorsCorrected)
+	assert.Equal(t, 5, stats.MaxErrorsCorrected)
+	assert.Equal(t, map[string]int{"L": 1, "M": 1, "H": 2}, stats.ECLevels)
+	assert.Equal(t, map[int]int{1: 2, 4: 2}, stats.Versions)
+
+	assert.Equal(t, "3 decoded, 2 failed, 8 errors corrected", stats.String())
+	report := stats.Report()
+	assert.Contains(t, report, "QR codes observed: 5")
+	assert.Contains(t, report, "  H: 2")
+	assert.Contains(t, report, "  4: 2")
+}
+
+func TestErrorCorrector_CodewordBlockMap(t *testing.T) {
+	// Version 5-H: 2 blocks of 11 data codewords, 2 blocks of 12, 22 EC codewords each
+	version, err := zxingdecoder.Version_GetVersionForNumber(5)
+	require.NoError(t, err)
+	qrData := &types.QRCodeData{Version: version, ECLevel: zxingdecoder.ErrorCorrectionLevel_H}
+
+	ec, err := NewErrorCorrector()
+	require.NoError(t, err)
+
+	blockMap := ec.CodewordBlockMap(qrData)
+	require.Len(t, blockMap, 134)
+
+	expected := map[int]BlockPos{
+		0:   {Block: 0, Position: 0},
+		1:   {Block: 1, Position: 0},
+		3:   {Block: 3, Position: 0},
+		4:   {Block: 0, Position: 1},
+		43:  {Block: 3, Position: 10},
+		44:  {Block: 2, Position: 11}, // only the longer blocks have a 12th data codeword
+		45:  {Block: 3, Position: 11},
+		46:  {Block: 0, Position: 11}, // first EC codeword of block 0
+		48:  {Block: 2, Position: 12}, // first EC codeword of block 2
+		133: {Block: 3, Position: 33},
+	}
+	for rawIndex, want := range expected {
+		assert.Equal(t, want, blockMap[rawIndex], "raw index %d", rawIndex)
+	}
+
+	// Every block position is hit exactly once
+	seen := make(map[BlockPos]bool)
+	for _, pos := range blockMap {
+		assert.False(t, seen[pos], "duplicate %v", pos)
+		seen[pos] = true
+	}
+}
+
+func TestDecoder_CaptureBlocks(t *testing.T) {
+	// Arrange: a Version 5-H code (4 blocks) built from known data blocks
+	version, err := zxingdecoder.Version_GetVersionForNumber(5)
+	require.NoError(t, err)
+	qrData := &types.QRCodeData{Version: version, ECLevel: zxingdecoder.ErrorCorrectionLevel_H}
+
+	dec, err := NewDecoder()
+	require.NoError(t, err)
+
+	ecBlocks := version.GetECBlocksForLevel(qrData.ECLevel)
+	numEC := ecBlocks.GetECCodewordsPerBlock()
+	var blocks [][]byte
+	for _, ecb := range ecBlocks.GetECBlocks() {
+		for i := 0; i < ecb.GetCount(); i++ {
+			data := make([]byte, ecb.GetDataCodewords())
+			for j := range data {
+				data[j] = byte(len(blocks)*16 + j)
+			}
+			blocks = append(blocks, encodeTestBlock(t, dec.errorCorrector, data, numEC))
+		}
+	}
+
+	blockMap := dec.errorCorrector.CodewordBlockMap(qrData)
+	qrData.RawCodewords = make([]byte, len(blockMap))
+	for rawIndex, pos := range blockMap {
+		qrData.RawCodewords[rawIndex] = blocks[pos.Block][pos.Position]
+	}
+	qrData.RawCodewords[1] ^= 0xFF // corrupt the first data codeword of block 1
+
+	// Act
+	dec.SetCaptureBlocks(true)
+	blockResults, err := dec.AnalyzeOnly(qrData)
+	require.NoError(t, err)
+
+	var dump strings.Builder
+	require.NoError(t, WriteBlockDump(&dump, blockResults))
+
+	// Assert: one section per block, and the corrected bytes match the originals
+	output := dump.String()
+	assert.Equal(t, 4, strings.Count(output, "Block "))
+	assert.Equal(t, 4, strings.Count(output, "Received data:"))
+	assert.Equal(t, 4, strings.Count(output, "Corrected EC:"))
+
+	for i, result := range blockResults {
+		assert.Equal(t, blocks[i], result.CorrectedCodewords, "block %d", i)
+	}
+	assert.Equal(t, blocks[1][0]^0xFF, blockResults[1].ReceivedCodewords[0])
+	assert.Equal(t, 1, blockResults[1].ErrorsFound)
+
+	// Without capture the received codewords are not kept
+	dec.SetCaptureBlocks(false)
+	blockResults, err = dec.AnalyzeOnly(qrData)
+	require.NoError(t, err)
+	assert.Nil(t, blockResults[0].ReceivedCodewords)
+	assert.NotNil(t, blockResults[0].CorrectedCodewords)
+}
+
+func TestDecoder_BlockDumpOnFailure(t *testing.T) {
+	// Arrange: a Version 5-H code (4 blocks) built from known data blocks
+	version, err := zxingdecoder.Version_GetVersionForNumber(5)
+	require.NoError(t, err)
+	qrData := &types.QRCodeData{Version: version, ECLevel: zxingdecoder.ErrorCorrectionLevel_H}
+
+	dec, err := NewDecoder()
+	require.NoError(t, err)
+
+	ecBlocks := version.GetECBlocksForLevel(qrData.ECLevel)
+	numEC := ecBlocks.GetECCodewordsPerBlock()
+	var blocks [][]byte
+	for _, ecb := range ecBlocks.GetECBlocks() {
+		for i := 0; i < ecb.GetCount(); i++ {
+			data := make([]byte, ecb.GetDataCodewords())
+			for j := range data {
+				data[j] = byte(len(blocks)*16 + j)
+			}
+			blocks = append(blocks, encodeTestBlock(t, dec.errorCorrector, data, numEC))
+		}
+	}
+
+	// Corrupt every data codeword of block 2, far beyond its 11 correctable errors
+	blockMap := dec.errorCorrector.CodewordBlockMap(qrData)
+	qrData.RawCodewords = make([]byte, len(blockMap))
+	for rawIndex, pos := range blockMap {
+		qrData.RawCodewords[rawIndex] = blocks[pos.Block][pos.Position]
+		if pos.Block == 2 && pos.Position < len(blocks[2])-numEC {
+			qrData.RawCodewords[rawIndex] ^= 0xC3
+		}
+	}
+
+	// Act: block capture is off, the failed block's codewords are kept anyway
+	result, err := dec.Decode(qrData)
+
+	// Assert
+	require.Error(t, err)
+	require.NotNil(t, result)
+	assert.False(t, result.CorrectionSuccessful)
+	require.Len(t, result.BlockResults, 4)
+
+	failed := result.BlockResults[2]
+	assert.False(t, failed.CorrectionSucceeded)
+	assert.Nil(t, failed.CorrectedCodewords)
+	assert.Equal(t, blocks[2][0]^0xC3, failed.ReceivedCodewords[0])
+	for _, i := range []int{0, 1, 3} {
+		assert.True(t, result.BlockResults[i].CorrectionSucceeded, "block %d", i)
+		assert.Equal(t, blocks[i], result.BlockResults[i].CorrectedCodewords, "block %d", i)
+	}
+
+	var dump strings.Builder
+	require.NoError(t, WriteBlockDump(&dump, result.BlockResults))
+	output := dump.String()
+	assert.Equal(t, 4, strings.Count(output, "Block "))
+	assert.Equal(t, 1, strings.Count(output, "(correction failed)"))
+	assert.Contains(t, output, "Block 2 (")
+}
+
+func TestErrorCorrector_CorrectSingleBlock(t *testing.T) {
+	ec, err := NewErrorCorrector()
+	require.NoError(t, err)
+
+	// Arrange: a hand-built block of 5 data + 6 EC codewords, not a QR block size
+	data := []byte{0x12, 0x34, 0x56, 0x78, 0x9A}
+	block := encodeTestBlock(t, ec, data, 6)
+	block[2] ^= 0x5A
+
+	// Act
+	corrected, result, err := ec.CorrectSingleBlock(block, 6)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, data, corrected)
+	assert.True(t, result.CorrectionSucceeded)
+	assert.Equal(t, 1, result.ErrorsFound)
+	assert.Equal(t, 5, result.NumDataCodewords)
+	assert.Equal(t, 6, result.NumECCodewords)
+
+	// Invalid sizes are rejected
+	_, _, err = ec.CorrectSingleBlock(block, 0)
+	assert.Error(t, err)
+	_, _, err = ec.CorrectSingleBlock(block, len(block))
+	assert.Error(t, err)
+	_, _, err = ec.CorrectSingleBlock(make([]byte, 256), 10)
+	assert.Error(t, err)
+}
+
+func TestErrorCorrector_DetectedUncorrectable(t *testing.T) {
+	ec, err := NewErrorCorrector()
+	require.NoError(t, err)
+
+	// 5 data + 6 EC codewords correct up to 3 errors
+	data := []byte{0x12, 0x34, 0x56, 0x78, 0x9A}
+	clean := encodeTestBlock(t, ec, data, 6)
+
+	// A clean block and a correctable one are not flagged
+	_, result, err := ec.CorrectSingleBlock(clean, 6)
+	require.NoError(t, err)
+	assert.False(t, result.DetectedUncorrectable)
+
+	oneError := append([]byte{}, clean...)
+	oneError[3] ^= 0x01
+	_, result, err = ec.CorrectSingleBlock(oneError, 6)
+	require.NoError(t, err)
+	assert.False(t, result.DetectedUncorrectable)
+
+	// 5 errors are detected (non-zero syndromes) but cannot be corrected
+	damaged := append([]byte{}, clean...)
+	for _, i := range []int{0, 2, 4, 6, 8} {
+		damaged[i] ^= 0xA5
+	}
+	_, result, err = ec.CorrectSingleBlock(damaged, 6)
+	require.Error(t, err)
+	assert.False(t, result.CorrectionSucceeded)
+	assert.True(t, result.DetectedUncorrectable)
+}
+
+func TestNewErrorCorrectorFCR(t *testing.T) {
+	// Arrange: a block encoded with generator roots α^1 ... α^8
+	ec, err := NewErrorCorrectorFCR(1)
+	require.NoError(t, err)
+
+	data := []byte("first root")
+	block := encodeTestBlock(t, ec, data, 8)
+
+	elements := make([]gfpn.Element, len(block))
+	for i, b := range block {
+		elements[i] = ec.byteToElement(b)
+	}
+	syndromes, err := ec.computeSyndromes(elements, 8)
+	require.NoError(t, err)
+	for i, s := range syndromes {
+		require.True(t, s.IsZero(), "S_%d = r(α^%d) = %s, want 0", i, i+1, s)
+	}
+
+	// The codeword is not a codeword of the QR (first root 0) code
+	qr, err := NewErrorCorrector()
+	require.NoError(t, err)
+	assert.NotEqual(t, encodeTestBlock(t, qr, data, 8), block)
+
+	// Act: 4 errors, the most 8 EC codewords can correct
+	damaged := append([]byte{}, block...)
+	for i, pos := range []int{0, 5, 11, 17} {
+		damaged[pos] ^= byte(0x11 * (i + 1))
+	}
+	corrected, result, err := ec.CorrectSingleBlock(damaged, 8)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, data, corrected)
+	assert.Equal(t, 4, result.ErrorsFound)
+	assert.Equal(t, block, result.CorrectedCodewords)
+}
+
+func TestErrorCorrector_ComputeSyndromes_EmptyCodeword(t *testing.T) {
+	ec, err := NewErrorCorrector()
+	require.NoError(t, err)
+
+	// An empty word would otherwise give all-zero syndromes, i.e. "no errors"
+	syndromes, err := ec.computeSyndromes([]gfpn.Element{}, 7)
+	assert.ErrorIs(t, err, syndrome.ErrEmptyCodeword)
+	assert.Nil(t, syndromes)
+}
+
+// TestErrorCorrector_SyndromeRoots tests that the precomputed evaluation points give the
+// same syndromes as computing α^(b+i) for each one, including past the cached range
+func TestErrorCorrector_SyndromeRoots(t *testing.T) {
+	for _, firstRoot := range []int{0, 1} {
+		ec, err := NewErrorCorrectorFCR(firstRoot)
+		require.NoError(t, err)
+		require.Len(t, ec.syndromeRoots, maxECCodewordsPerBlock)
+
+		uncached := *ec
+		uncached.syndromeRoots = nil
+
+		received := make([]gfpn.Element, 60)
+		for i := range received {
+			received[i] = ec.byteToElement(byte(37*i + 11))
+		}
+		for _, numEC := range []int{7, maxECCodewordsPerBlock, 40} {
+			want, err := uncached.computeSyndromes(received, numEC)
+			require.NoError(t, err)
+			got, err := ec.computeSyndromes(received, numEC)
+			require.NoError(t, err)
+			require.Len(t, got, len(want))
+			for i := range want {
+				assert.True(t, got[i].Equal(want[i]), "b=%d, S_%d = %s, want %s", firstRoot, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+// BenchmarkComputeSyndromes compares precomputed evaluation points against raising α
+// for every syndrome, on a Version 5-H block (33 codewords, 22 of them EC)
+func BenchmarkComputeSyndromes(b *testing.B) {
+	ec, err := NewErrorCorrector()
+	require.NoError(b, err)
+
+	// RSEncoder orders the codeword lowest degree first, computeSyndromes takes it in
+	// QR order, highest degree first
+	encoder, err := correction.NewRSEncoder(ec.field, 22)
+	require.NoError(b, err)
+	message := make([]gfpn.Element, 11)
+	for i := range message {
+		message[i] = ec.byteToElement(byte(i*29 + 7))
+	}
+	codeword := encoder.Encode(message)
+	codeword[5] = ec.field.Add(codeword[5], ec.byteToElement(0x5A))
+	received := make([]gfpn.Element, len(codeword))
+	for i, e := range codeword {
+		received[len(codeword)-1-i] = e
+	}
+
+	uncached := *ec
+	uncached.syndromeRoots = nil
+
+	b.Run("Precomputed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = ec.computeSyndromes(received, 22)
+		}
+	})
+	b.Run("PowPerSyndrome", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = uncached.computeSyndromes(received, 22)
+		}
+	})
+}
+
+func TestDecoder_DecodeCandidates(t *testing.T) {
+	dec, err := NewDecoder()
+	require.NoError(t, err)
+
+	// A clean 1-L code only verifies under its own mask and level
+	qrData := createTestQRCode(t, "Candidates", gozxing.EncodeHintType_ERROR_CORRECTION, "L")
+	candidates, err := dec.DecodeCandidates(qrData)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "Candidates", candidates[0].Message)
+	assert.Equal(t, qrData.DataMask, candidates[0].DataMask)
+	assert.Equal(t, "L", candidates[0].ECLevel)
+	assert.Equal(t, 1.0, candidates[0].Confidence)
+
+	// A 1-H codeword is also a codeword of the lower levels (their generators
+	// divide the H generator), so the same mask decodes at every level
+	qrData = createTestQRCode(t, "Hi", gozxing.EncodeHintType_ERROR_CORRECTION, "H")
+	candidates, err = dec.DecodeCandidates(qrData)
+	require.NoError(t, err)
+	require.Len(t, candidates, 4)
+
+	levels := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		levels[i] = candidate.ECLevel
+		assert.Equal(t, "Hi", candidate.Message)
+		assert.Equal(t, qrData.DataMask, candidate.DataMask)
+		assert.Equal(t, 0, candidate.NumErrorsCorrected)
+	}
+	assert.Equal(t, "H", levels[0], "format information's combination comes first")
+	assert.ElementsMatch(t, []string{"L", "M", "Q", "H"}, levels)
+}
+
+func TestDecoder_DecodeCandidates_SortedByErrors(t *testing.T) {
+	dec, err := NewDecoder()
+	require.NoError(t, err)
+
+	// Two damaged codewords: the H reading corrects them, but the lower levels
+	// see only part of the EC codewords and cannot
+	qrData := createTestQRCode(t, "Hi", gozxing.EncodeHintType_ERROR_CORRECTION, "H")
+	for _, pos := range types.DataModulePositions(qrData.Version)[:16] {
+		qrData.BitMatrix.Flip(pos.Col, pos.Row)
+	}
+
+	candidates, err := dec.DecodeCandidates(qrData)
+	require.NoError(t, err)
+	require.NotEmpty(t, candidates)
+	assert.Equal(t, "Hi", candidates[0].Message)
+	assert.Equal(t, "H", candidates[0].ECLevel)
+	assert.Equal(t, 2, candidates[0].NumErrorsCorrected)
+	assert.InDelta(t, 1.0, candidates[0].Confidence, 1e-9) // 2 of 8 correctable errors: still near-certain
+	for i := 1; i < len(candidates); i++ {
+		assert.LessOrEqual(t, candidates[i-1].NumErrorsCorrected, candidates[i].NumErrorsCorrected)
+	}
+}
+
+func TestErrorCorrector_CorrectedCodewords(t *testing.T) {
+	// Arrange: a code with one damaged codeword
+	qrData := createTestQRCode(t, "Full codeword", gozxing.EncodeHintType_ERROR_CORRECTION, "M")
+	qrData.RawCodewords[3] ^= 0x42
+
+	ec, err := NewErrorCorrector()
+	require.NoError(t, err)
+
+	// Act
+	correctedData, blockResults, err := ec.CorrectCodewords(qrData)
+	require.NoError(t, err)
+
+	// Assert: every corrected codeword is valid and its data prefix was returned
+	// (1-M is a single-block code, so the returned data is not interleaved)
+	require.Len(t, blockResults, 1)
+	offset := 0
+	for _, block := range blockResults {
+		require.Len(t, block.CorrectedCodewords, block.NumDataCodewords+block.NumECCodewords)
+
+		elements := make([]gfpn.Element, len(block.CorrectedCodewords))
+		for i, b := range block.CorrectedCodewords {
+			elements[i] = ec.byteToElement(b)
+		}
+		syndromes, err := ec.computeSyndromes(elements, block.NumECCodewords)
+		require.NoError(t, err)
+		for i, s := range syndromes {
+			assert.True(t, s.IsZero(), "block %d syndrome %d", block.BlockIndex, i)
+		}
+
+		assert.Equal(t, block.CorrectedCodewords[:block.NumDataCodewords],
+			correctedData[offset:offset+block.NumDataCodewords])
+		offset += block.NumDataCodewords
+	}
+	assert.Equal(t, 1, blockResults[0].ErrorsFound)
+}
+
+func TestHanziToGB2312(t *testing.T) {
+	tests := []struct {
+		value     int
+		high, low byte
+	}{
+		{0, 0xA1, 0xA1},    // full-width space, first code of the symbol range
+		{2, 0xA1, 0xA3},    // 。
+		{960, 0xB0, 0xA1},  // 啊, first hanzi
+		{3875, 0xCE, 0xC4}, // 文
+		{4655, 0xD6, 0xD0}, // 中
+	}
+	for _, tt := range tests {
+		high, low := hanziToGB2312(tt.value)
+		assert.Equal(t, []byte{tt.high, tt.low}, []byte{high, low}, "value %d", tt.value)
+	}
+}
+
+func TestDataDecoder_HanziMode(t *testing.T) {
+	// Hanzi mode, GB2312 subset, count=4, "中文啊。", terminator
+	data := packBits(t, "1101 0001 00000100 1001000101111 0111100100011 0001111000000 0000000000010 0000")
+
+	message, err := NewDataDecoder().Decode(data)
+	require.NoError(t, err)
+	assert.Equal(t, "中文啊。", message)
+
+	// Versions 10-26 use a 10-bit count
+	data = packBits(t, "1101 0001 0000000001 1001000101111 0000")
+	message, err = NewDataDecoder().DecodeForVersion(data, 10, "M")
+	require.NoError(t, err)
+	assert.Equal(t, "中", message)
+
+	// Only the GB2312 subset is defined
+	_, err = NewDataDecoder().Decode(packBits(t, "1101 0010 00000001 1001000101111 0000"))
+	assert.Error(t, err)
+
+	// A count larger than the remaining bits is rejected before reading
+	_, err = NewDataDecoder().Decode(packBits(t, "1101 0001 11111111 1001000101111 0000"))
+	assert.ErrorIs(t, err, ErrImpossibleCount)
+}
+
+func TestDecoder_DamageSweep(t *testing.T) {
+	// Version 1-M is a single block with 10 EC codewords, so up to 5 damaged
+	// codewords are correctable
+	const message = "Damage sweep"
+	const capacity = 5
+
+	clean, err := types.GenerateDamagedQR(message, "M", 0, 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, clean.Version.GetVersionNumber())
+
+	dec, err := NewDecoder()
+	require.NoError(t, err)
+
+	withinCapacity, beyondCapacity := 0, 0
+	for percent := 0.0; percent <= 20; percent++ {
+		for seed := int64(1); seed <= 5; seed++ {
+			qrData, err := types.GenerateDamagedQR(message, "M", percent, seed)
+			require.NoError(t, err, "%v%% seed %d", percent, seed)
+
+			damaged := 0
+			for i := range clean.RawCodewords {
+				if qrData.RawCodewords[i] != clean.RawCodewords[i] {
+					damaged++
+				}
+			}
+
+			result, err := dec.Decode(qrData)
+			if damaged <= capacity {
+				withinCapacity++
+				require.NoError(t, err, "%v%% seed %d: %d damaged codewords", percent, seed, damaged)
+				assert.Equal(t, message, result.Message)
+				assert.Equal(t, damaged, result.NumErrorsCorrected)
+				continue
+			}
+
+			// Beyond the capacity decoding must fail rather than return a wrong message
+			beyondCapacity++
+			if err == nil {
+				assert.Equal(t, message, result.Message,
+					"%v%% seed %d: %d damaged codewords decoded to a wrong message", percent, seed, damaged)
+			}
+		}
+	}
+
+	assert.Positive(t, withinCapacity)
+	assert.Positive(t, beyondCapacity)
+}
+
+func TestErrorCorrector_HasErrors(t *testing.T) {
+	ec, err := NewErrorCorrector()
+	require.NoError(t, err)
+
+	data := []byte{0x40, 0x54, 0x86, 0x56, 0xC6, 0xC6, 0xF0, 0xEC, 0x11}
+	block := encodeTestBlock(t, ec, data, 10)
+	toElements := func(codewords []byte) []gfpn.Element {
+		elements := make([]gfpn.Element, len(codewords))
+		for i, b := range codewords {
+			elements[i] = ec.byteToElement(b)
+		}
+		return elements
+	}
+
+	// A clean word has all syndromes zero
+	clean := toElements(block)
+	assert.False(t, ec.HasErrors(clean, 10))
+	assert.Equal(t, -1, ec.firstNonzeroSyndrome(clean, 10))
+
+	// A single error e gives S_0 = e, so the check stops after the first syndrome
+	block[3] ^= 0xFF
+	corrupt := toElements(block)
+	assert.True(t, ec.HasErrors(corrupt, 10))
+	assert.Equal(t, 0, ec.firstNonzeroSyndrome(corrupt, 10))
+
+	// The result agrees with the full syndrome computation
+	syndromes, err := ec.computeSyndromes(corrupt, 10)
+	require.NoError(t, err)
+	assert.False(t, syndromes[0].IsZero())
+
+	assert.True(t, ec.HasErrors(nil, 10))
+}
+
+func TestDecoder_BurstErrorSpan(t *testing.T) {
+	// At level Q every block can correct at least 2 errors
+	qrData := createTestQRCode(t, "Burst errors are spread over every block by interleaving",
+		gozxing.EncodeHintType_ERROR_CORRECTION, "Q")
+
+	dec, err := NewDecoder()
+	require.NoError(t, err)
+	numBlocks := qrData.Version.GetECBlocksForLevel(qrData.ECLevel).GetNumBlocks()
+	require.Greater(t, numBlocks, 1, "the message should need several blocks")
+
+	// Arrange: a contiguous burst of 2 errors per block
+	const burstStart = 10
+	burstEnd := burstStart + 2*numBlocks - 1
+	for i := burstStart; i <= burstEnd; i++ {
+		qrData.RawCodewords[i] ^= 0xA5
+	}
+
+	// Act
+	result, err := dec.Decode(qrData)
+	require.NoError(t, err)
+	start, end, err := dec.BurstErrorSpan(result, qrData)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2*numBlocks, result.NumErrorsCorrected)
+	assert.Equal(t, burstStart, start)
+	assert.Equal(t, burstEnd, end)
+
+	// A clean code has no burst
+	clean := createTestQRCode(t, "No damage", gozxing.EncodeHintType_ERROR_CORRECTION, "Q")
+	result, err = dec.Decode(clean)
+	require.NoError(t, err)
+	_, _, err = dec.BurstErrorSpan(result, clean)
+	assert.Error(t, err)
+}
+
+func TestDecoder_DecodeWithRaw(t *testing.T) {
+	testMessage := "Raw versus corrected"
+	qrData := createTestQRCode(t, testMessage, gozxing.EncodeHintType_ERROR_CORRECTION, "H")
+
+	dec, err := NewDecoder()
+	require.NoError(t, err)
+
+	// A clean code decodes the same either way
+	corrected, raw, err := dec.DecodeWithRaw(qrData)
+	require.NoError(t, err)
+	assert.Equal(t, testMessage, corrected.Message)
+	assert.Equal(t, testMessage, raw.Message)
+
+	// Corrupt two data codewords inside the message text
+	qrData.RawCodewords[3] ^= 0x21
+	qrData.RawCodewords[5] ^= 0x42
+
+	corrected, raw, err = dec.DecodeWithRaw(qrData)
+	require.NoError(t, err)
+	assert.Equal(t, testMessage, corrected.Message)
+	assert.Equal(t, 2, corrected.NumErrorsCorrected)
+	assert.NotEqual(t, testMessage, raw.Message)
+	assert.False(t, raw.CorrectionSuccessful)
+}
+
+func TestErrorCorrector_SetECOverride(t *testing.T) {
+	qrData := createTestQRCode(t, "Override the EC count", gozxing.EncodeHintType_ERROR_CORRECTION, "M")
+	qrData.RawCodewords[4] ^= 0x3C
+	standardEC := qrData.Version.GetECBlocksForLevel(qrData.ECLevel).GetECCodewordsPerBlock()
+
+	ec, err := NewErrorCorrector()
+	require.NoError(t, err)
+	expectedData, expectedBlocks, err := ec.CorrectCodewords(qrData)
+	require.NoError(t, err)
+
+	// An override equal to the standard value changes nothing
+	ec.SetECOverride(standardEC)
+	data, blocks, err := ec.CorrectCodewords(qrData)
+	require.NoError(t, err)
+	assert.Equal(t, expectedData, data)
+	assert.Equal(t, expectedBlocks, blocks)
+
+	// An override that leaves no data codewords is rejected
+	ec.SetECOverride(qrData.Version.GetTotalCodewords())
+	_, _, err = ec.CorrectCodewords(qrData)
+	assert.Error(t, err)
+
+	// Zero restores the standard value
+	ec.SetECOverride(0)
+	data, _, err = ec.CorrectCodewords(qrData)
+	require.NoError(t, err)
+	assert.Equal(t, expectedData, data)
+}
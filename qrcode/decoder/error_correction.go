@@ -4,14 +4,20 @@ import (
 	"fmt"
 
 	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
+	"github.com/jalphad/abstract_algebra/exercises/5-syndrome"
 	"github.com/jalphad/abstract_algebra/exercises/6-berlekamp"
 	"github.com/jalphad/abstract_algebra/exercises/7-chien"
 	"github.com/jalphad/abstract_algebra/exercises/8-forney"
 	"github.com/jalphad/abstract_algebra/qrcode/correction"
 	"github.com/jalphad/abstract_algebra/qrcode/types"
+	"github.com/makiuchi-d/gozxing"
 	"github.com/makiuchi-d/gozxing/qrcode/decoder"
 )
 
+// maxECCodewordsPerBlock is the largest number of EC codewords in a single block of any
+// QR code version and level (ISO/IEC 18004 Table 9), which bounds the syndromes per block
+const maxECCodewordsPerBlock = 30
+
 // ErrorCorrector handles Reed-Solomon error correction for QR codes
 //
 // QR codes use Reed-Solomon error correction over GF(256), which allows them to
@@ -34,8 +40,13 @@ import (
 //   - 7 error correction codewords
 //   - Can correct up to 3 symbol errors (7/2 = 3.5 → floor = 3)
 type ErrorCorrector struct {
-	field       gfpn.Field     // GF(256) field for QR code error correction
-	alphaPowers []gfpn.Element // Precomputed powers of α: [α^0, α^1, ..., α^7]
+	field         gfpn.Field     // GF(256) field for QR code error correction
+	alphaPowers   []gfpn.Element // Precomputed powers of α: [α^0, α^1, ..., α^7]
+	logToByte     []byte         // logToByte[k] is the byte representing α^k
+	syndromeRoots []gfpn.Element // Precomputed evaluation points: [α^b, α^(b+1), ..., α^(b+29)]
+	firstRoot     int            // Generator roots are α^firstRoot, ..., α^(firstRoot+numEC-1)
+	captureBlocks bool           // If true, BlockResults also keep the received block codewords
+	ecOverride    int            // If positive, EC codewords per block instead of the version's value
 }
 
 // NewErrorCorrector creates a new error corrector for QR codes
@@ -47,7 +58,24 @@ type ErrorCorrector struct {
 //   - It's irreducible over GF(2), meaning it can't be factored
 //   - It generates a primitive element that cycles through all 255 non-zero elements
 //   - It's standardized in the QR code specification (ISO/IEC 18004)
+//
+// QR generator polynomials have their first consecutive root at α^0, see NewErrorCorrectorFCR.
 func NewErrorCorrector() (*ErrorCorrector, error) {
+	return NewErrorCorrectorFCR(0)
+}
+
+// NewErrorCorrectorFCR creates an error corrector for Reed-Solomon codes over the
+// QR code field whose generator polynomial has the first consecutive root α^firstRoot
+//
+// The generator of a code with numEC EC codewords is then
+//
+//	g(x) = (x - α^b)(x - α^(b+1))···(x - α^(b+numEC-1)),  b = firstRoot
+//
+// QR codes use b = 0 (NewErrorCorrector), many other RS codes and textbooks b = 1.
+// The first root shifts the syndromes to S_i = r(α^(b+i)), which multiplies each
+// error's contribution by X^b. The error locator, and with it Chien search, does
+// not change; Forney's formula gains a factor X^(-b) on each magnitude.
+func NewErrorCorrectorFCR(firstRoot int) (*ErrorCorrector, error) {
 	// QR code irreducible polynomial: x^8 + x^4 + x^3 + x^2 + 1
 	// Coefficients: [constant, x^1, x^2, x^3, x^4, x^5, x^6, x^7, x^8]
 	//              = [1, 0, 1, 1, 1, 0, 0, 0, 1]
@@ -63,15 +91,46 @@ func NewErrorCorrector() (*ErrorCorrector, error) {
 	// QR codes interpret byte bits as polynomial coefficients
 	alpha := field.Primitive()
 	alphaPowers := make([]gfpn.Element, 8)
-	alphaPowers[0] = field.One() // α^0 = 1
-	for i := 1; i < 8; i++ {
-		alphaPowers[i] = field.Mul(alphaPowers[i-1], alpha)
+	for i := range alphaPowers {
+		alphaPowers[i] = alpha.Pow(i)
 	}
 
-	return &ErrorCorrector{
+	ec := &ErrorCorrector{
 		field:       field,
 		alphaPowers: alphaPowers,
-	}, nil
+		firstRoot:   firstRoot,
+	}
+
+	// Syndrome S_i evaluates the received polynomial at α^(b+i)
+	ec.syndromeRoots = make([]gfpn.Element, maxECCodewordsPerBlock)
+	for i := range ec.syndromeRoots {
+		ec.syndromeRoots[i] = alpha.Pow(firstRoot + i)
+	}
+
+	// Index every non-zero byte by the discrete logarithm of its element
+	ec.logToByte = make([]byte, field.Order()-1)
+	for b := 1; b < field.Order(); b++ {
+		power, err := field.Log(ec.byteToElement(byte(b)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build log table: %w", err)
+		}
+		ec.logToByte[power] = byte(b)
+	}
+
+	return ec, nil
+}
+
+// SetECOverride sets the number of EC codewords per block used for correction, bypassing the QR version tables
+//
+// This is for advanced and experimental use only, e.g. to run custom Reed-Solomon
+// parameters on codewords extracted from a QR code. The blocks are still split
+// according to the version and EC level; the override only changes how many
+// codewords at the end of each block correctBlock treats as EC codewords, and with
+// it the data length and correction capacity. A value that does not match how the
+// code was encoded makes correction fail or return wrong data. Zero (the default)
+// restores the standard value from gozxing's EC block tables.
+func (ec *ErrorCorrector) SetECOverride(n int) {
+	ec.ecOverride = n
 }
 
 // byteToElement converts a byte to a GF(256) element using QR code's convention
@@ -102,27 +161,15 @@ func (ec *ErrorCorrector) byteToElement(b byte) gfpn.Element {
 
 // elementToByte converts a GF(256) element back to a byte
 //
-// This is the reverse of byteToElement. We need to find which polynomial
-// coefficients are set, then build the corresponding byte.
-//
-// Since we're in GF(256) = GF(2)[x]/(irreducible), each element has a unique
-// polynomial representation with coefficients in {0, 1}.
+// This is the reverse of byteToElement. Every non-zero element is α^k for a unique
+// k in [0, 254], so its byte is read from the table built in NewErrorCorrectorFCR,
+// indexed by the discrete logarithm from the field's power tables.
 func (ec *ErrorCorrector) elementToByte(elem gfpn.Element) byte {
-	if elem.IsZero() {
+	power, err := ec.field.Log(elem)
+	if err != nil {
 		return 0
 	}
-
-	// Try all 256 possible bytes to find which one maps to this element
-	// This is a brute-force approach but works for GF(256)
-	// (More efficient implementations would use logarithm tables)
-	for b := byte(1); b != 0; b++ { // b++ will wrap from 255 to 0
-		if ec.byteToElement(b).String() == elem.String() {
-			return b
-		}
-	}
-
-	// This should never happen for valid GF(256) elements
-	return 0
+	return ec.logToByte[power]
 }
 
 // CorrectCodewords performs Reed-Solomon error correction on QR code data
@@ -130,7 +177,7 @@ func (ec *ErrorCorrector) elementToByte(elem gfpn.Element) byte {
 // This is the main entry point for error correction. It:
 //  1. De-interleaves the raw codewords into separate RS blocks
 //  2. Applies error correction to each block independently
-//  3. Re-interleaves the corrected blocks
+//  3. Concatenates the corrected data codewords in block order
 //  4. Returns the corrected data along with error statistics
 //
 // QR Code Block Structure:
@@ -149,104 +196,195 @@ func (ec *ErrorCorrector) elementToByte(elem gfpn.Element) byte {
 //   - qrData: Extracted QR code data from the extractor
 //
 // Returns:
-//   - Corrected data codewords (de-interleaved and error-corrected)
-//   - Block-by-block results showing where errors were found and corrected
-//   - Error if correction fails
+//   - Corrected data codewords in block order: all data codewords of block 1, then
+//     those of block 2, and so on. This is the order of the data bit stream, not the
+//     interleaved order the codewords have in the symbol; use codewordBlockMap to
+//     interleave them again (see RebuildBitMatrix)
+//   - Block-by-block results showing where errors were found and corrected. They are
+//     also returned when a block cannot be corrected: every block is still processed,
+//     and a failed block has CorrectionSucceeded false, its ReceivedCodewords set and
+//     no CorrectedCodewords
+//   - Error if the codeword count does not match the version or correction fails
+//     (for the first failed block)
 func (ec *ErrorCorrector) CorrectCodewords(qrData *types.QRCodeData) ([]byte, []BlockResult, error) {
 	version := qrData.Version
 	ecLevel := qrData.ECLevel
 	rawCodewords := qrData.RawCodewords
 
+	// A truncated read would make de-interleaving index past the end of rawCodewords
+	if expected := version.GetTotalCodewords(); len(rawCodewords) != expected {
+		return nil, nil, fmt.Errorf("version %d expects %d codewords, got %d",
+			version.GetVersionNumber(), expected, len(rawCodewords))
+	}
+
 	// Get the error correction block structure for this version and EC level
 	ecBlocks := version.GetECBlocksForLevel(ecLevel)
 
 	// De-interleave codewords into separate blocks
 	blocks := ec.deinterleaveBlocks(rawCodewords, ecBlocks)
 
+	numECCodewords := ecBlocks.GetECCodewordsPerBlock()
+	if ec.ecOverride > 0 {
+		numECCodewords = ec.ecOverride
+	}
+
 	// Correct each block independently
 	correctedBlocks := make([][]byte, len(blocks))
 	blockResults := make([]BlockResult, len(blocks))
 
+	var correctionErr error
 	for i, block := range blocks {
-		corrected, result, err := ec.correctBlock(block, ecBlocks, i)
+		if numECCodewords >= len(block) {
+			return nil, nil, fmt.Errorf("EC codeword count %d leaves no data in block %d of %d codewords",
+				numECCodewords, i, len(block))
+		}
+		corrected, result, err := ec.correctBlock(block, numECCodewords, i)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to correct block %d: %w", i, err)
+			// Keep going so the caller sees every block, with the received
+			// codewords of the failed ones for diagnosis (see WriteBlockDump)
+			if result.ReceivedCodewords == nil {
+				result.ReceivedCodewords = append([]byte(nil), block...)
+			}
+			if correctionErr == nil {
+				correctionErr = fmt.Errorf("failed to correct block %d: %w", i, err)
+			}
 		}
 		correctedBlocks[i] = corrected
 		blockResults[i] = result
 	}
+	if correctionErr != nil {
+		return nil, blockResults, correctionErr
+	}
 
-	// Re-interleave corrected blocks to get final data
-	correctedData := ec.reinterleaveBlocks(correctedBlocks, ecBlocks)
+	// Join the corrected data codewords in block order
+	correctedData := concatenateBlocks(correctedBlocks)
 
 	return correctedData, blockResults, nil
 }
 
-// deinterleaveBlocks splits interleaved codewords into separate RS blocks
+// BlockPos locates a raw codeword inside the Reed-Solomon blocks
+type BlockPos struct {
+	// Block is the index of the RS block the codeword belongs to
+	Block int
+
+	// Position is the index of the codeword within its block
+	// (data codewords first, then EC codewords)
+	Position int
+}
+
+// CodewordBlockMap returns where each raw codeword ends up after de-interleaving
 //
-// QR codes interleave codewords from multiple blocks to improve error resilience.
-// A localized physical damage will affect multiple blocks by a small amount,
-// rather than destroying one block completely.
+// The result is indexed by raw codeword index, so blockMap[r] tells that raw
+// codeword r is codeword blockMap[r].Position of block blockMap[r].Block. This is
+// the same mapping deinterleaveBlocks uses, exposed for error maps and teaching.
 //
-// This function reverses the interleaving process.
-func (ec *ErrorCorrector) deinterleaveBlocks(rawCodewords []byte, ecBlocks *decoder.ECBlocks) [][]byte {
-	// Get block structure
-	ecbArray := ecBlocks.GetECBlocks()
-	numBlocks := 0
-	for _, ecb := range ecbArray {
-		numBlocks += ecb.GetCount()
-	}
-
-	blocks := make([][]byte, numBlocks)
-	blockIndex := 0
+// Example (Version 5-H: 2 blocks of 11 data codewords, 2 blocks of 12, 22 EC each):
+//
+//	Raw index:  0      1      2      3      4      ...  44      45      46      ...
+//	(Block,Pos) (0,0)  (1,0)  (2,0)  (3,0)  (0,1)  ...  (2,11)  (3,11)  (0,11)  ...
+func (ec *ErrorCorrector) CodewordBlockMap(qrData *types.QRCodeData) []BlockPos {
+	return codewordBlockMap(qrData.Version.GetECBlocksForLevel(qrData.ECLevel))
+}
 
-	// Calculate codewords per block
-	for _, ecb := range ecbArray {
-		numDataCodewords := ecb.GetDataCodewords()
-		numECCodewords := ecBlocks.GetECCodewordsPerBlock()
-		totalCodewords := numDataCodewords + numECCodewords
+// codewordBlockMap computes the raw index to block position mapping for a block structure
+//
+// QR codes interleave codewords from multiple blocks to improve error resilience.
+// Data codewords come first: D1-B1, D1-B2, ..., D2-B1, D2-B2, ... Blocks with fewer
+// data codewords are skipped once they run out. EC codewords follow in the same
+// pattern: EC1-B1, EC1-B2, ..., EC2-B1, EC2-B2, ...
+func codewordBlockMap(ecBlocks *decoder.ECBlocks) []BlockPos {
+	numECCodewords := ecBlocks.GetECCodewordsPerBlock()
 
+	// Number of data codewords in each block
+	var blockData []int
+	maxDataCodewords := 0
+	for _, ecb := range ecBlocks.GetECBlocks() {
 		for i := 0; i < ecb.GetCount(); i++ {
-			blocks[blockIndex] = make([]byte, totalCodewords)
-			blockIndex++
+			blockData = append(blockData, ecb.GetDataCodewords())
+			maxDataCodewords = max(maxDataCodewords, ecb.GetDataCodewords())
 		}
 	}
 
-	// De-interleave data codewords
-	// Data codewords are interleaved: D1-B1, D1-B2, ..., D2-B1, D2-B2, ...
-	rawIndex := 0
-	maxDataCodewords := 0
-	for _, block := range blocks {
-		numData := len(block) - ecBlocks.GetECCodewordsPerBlock()
-		if numData > maxDataCodewords {
-			maxDataCodewords = numData
-		}
-	}
+	var blockMap []BlockPos
 
+	// Data codewords
 	for i := 0; i < maxDataCodewords; i++ {
-		for j := 0; j < len(blocks); j++ {
-			numData := len(blocks[j]) - ecBlocks.GetECCodewordsPerBlock()
+		for j, numData := range blockData {
 			if i < numData {
-				blocks[j][i] = rawCodewords[rawIndex]
-				rawIndex++
+				blockMap = append(blockMap, BlockPos{Block: j, Position: i})
 			}
 		}
 	}
 
-	// De-interleave EC codewords
-	// EC codewords are also interleaved: EC1-B1, EC1-B2, ..., EC2-B1, EC2-B2, ...
-	numECCodewords := ecBlocks.GetECCodewordsPerBlock()
+	// EC codewords
 	for i := 0; i < numECCodewords; i++ {
-		for j := 0; j < len(blocks); j++ {
-			numData := len(blocks[j]) - numECCodewords
-			blocks[j][numData+i] = rawCodewords[rawIndex]
-			rawIndex++
+		for j, numData := range blockData {
+			blockMap = append(blockMap, BlockPos{Block: j, Position: numData + i})
+		}
+	}
+
+	return blockMap
+}
+
+// deinterleaveBlocks splits interleaved codewords into separate RS blocks
+//
+// QR codes interleave codewords from multiple blocks to improve error resilience.
+// A localized physical damage will affect multiple blocks by a small amount,
+// rather than destroying one block completely.
+//
+// This function reverses the interleaving process using codewordBlockMap.
+func (ec *ErrorCorrector) deinterleaveBlocks(rawCodewords []byte, ecBlocks *decoder.ECBlocks) [][]byte {
+	// Allocate blocks
+	var blocks [][]byte
+	for _, ecb := range ecBlocks.GetECBlocks() {
+		totalCodewords := ecb.GetDataCodewords() + ecBlocks.GetECCodewordsPerBlock()
+		for i := 0; i < ecb.GetCount(); i++ {
+			blocks = append(blocks, make([]byte, totalCodewords))
 		}
 	}
 
+	// Place every raw codeword at its block position
+	for rawIndex, pos := range codewordBlockMap(ecBlocks) {
+		blocks[pos.Block][pos.Position] = rawCodewords[rawIndex]
+	}
+
 	return blocks
 }
 
+// CorrectSingleBlock runs Reed-Solomon error correction on one block of any size
+//
+// This exposes the per-block pipeline used by CorrectCodewords without tying it
+// to a QR code version: the block is simply data codewords followed by numEC EC
+// codewords, in QR's byte order (block[0] is the highest degree coefficient) and
+// with the generator polynomial's roots at α^0 ... α^(numEC-1), or starting at the
+// first root given to NewErrorCorrectorFCR. This makes it usable for experiments,
+// teaching and Reed-Solomon data outside of QR codes.
+//
+// Parameters:
+//   - block: Data codewords followed by EC codewords (at most 255 in total)
+//   - numEC: Number of EC codewords at the end of the block
+//
+// Returns:
+//   - The corrected data codewords (the EC codewords are dropped)
+//   - Result with the number and positions of errors found
+//   - Error if the sizes are invalid or the block has too many errors to correct
+//
+// Example:
+//
+//	// 3 data codewords + 4 EC codewords, second codeword damaged
+//	data, result, err := ec.CorrectSingleBlock(received, 4)
+//	fmt.Println(result.ErrorsFound) // 1
+func (ec *ErrorCorrector) CorrectSingleBlock(block []byte, numEC int) ([]byte, BlockResult, error) {
+	if numEC <= 0 || numEC >= len(block) {
+		return nil, BlockResult{}, fmt.Errorf("invalid EC codeword count %d for a block of %d codewords", numEC, len(block))
+	}
+	if len(block) > 255 {
+		return nil, BlockResult{}, fmt.Errorf("block of %d codewords exceeds the GF(256) maximum of 255", len(block))
+	}
+
+	return ec.correctBlock(block, numEC, 0)
+}
+
 // correctBlock performs Reed-Solomon error correction on a single block
 //
 // This implements the complete RS decoding pipeline:
@@ -261,8 +399,7 @@ func (ec *ErrorCorrector) deinterleaveBlocks(rawCodewords []byte, ecBlocks *deco
 // Educational Note:
 // This is exactly the same algorithm we implemented in the reference code,
 // now applied to real QR code data!
-func (ec *ErrorCorrector) correctBlock(block []byte, ecBlocks *decoder.ECBlocks, blockIndex int) ([]byte, BlockResult, error) {
-	numECCodewords := ecBlocks.GetECCodewordsPerBlock()
+func (ec *ErrorCorrector) correctBlock(block []byte, numECCodewords int, blockIndex int) ([]byte, BlockResult, error) {
 	numDataCodewords := len(block) - numECCodewords
 	codewordLength := len(block)
 
@@ -272,6 +409,10 @@ func (ec *ErrorCorrector) correctBlock(block []byte, ecBlocks *decoder.ECBlocks,
 		NumECCodewords:   numECCodewords,
 	}
 
+	if ec.captureBlocks {
+		result.ReceivedCodewords = append([]byte(nil), block...)
+	}
+
 	// Convert bytes to GF(256) elements
 	received := make([]gfpn.Element, len(block))
 	for i, b := range block {
@@ -281,7 +422,10 @@ func (ec *ErrorCorrector) correctBlock(block []byte, ecBlocks *decoder.ECBlocks,
 	// Step 1: Compute syndromes
 	// Syndromes are computed as S_i = r(α^i) where r(x) is the received polynomial
 	// If all syndromes are zero, there are no errors
-	syndromes := ec.computeSyndromes(received, numECCodewords)
+	syndromes, err := ec.computeSyndromes(received, numECCodewords)
+	if err != nil {
+		return nil, result, err
+	}
 
 	// Check if there are any errors
 	hasErrors := false
@@ -295,7 +439,8 @@ func (ec *ErrorCorrector) correctBlock(block []byte, ecBlocks *decoder.ECBlocks,
 	if !hasErrors {
 		// No errors detected - return original data
 		result.CorrectionSucceeded = true
-		return block[:numDataCodewords], result, nil
+		result.CorrectedCodewords = append([]byte(nil), block...)
+		return result.CorrectedCodewords[:numDataCodewords], result, nil
 	}
 
 	// Step 2: Berlekamp-Massey Algorithm
@@ -303,6 +448,14 @@ func (ec *ErrorCorrector) correctBlock(block []byte, ecBlocks *decoder.ECBlocks,
 	// Λ(x) has roots at X_i^{-1} where X_i are the error locators
 	lambda := berlekamp.BerlekampMassey(ec.field, syndromes)
 
+	// Λ(0) = 1 by construction; anything else means the locator is not normalized
+	// and its roots would not be the inverse error locators
+	if !berlekamp.IsValidLocator(lambda) {
+		result.CorrectionSucceeded = false
+		result.DetectedUncorrectable = true
+		return nil, result, fmt.Errorf("invalid error locator polynomial: constant term is not 1")
+	}
+
 	// Step 3: Compute error evaluator polynomial Ω(x)
 	// Used in Forney's formula to compute error magnitudes
 	omega := forney.ComputeOmega(ec.field, syndromes, lambda)
@@ -311,7 +464,7 @@ func (ec *ErrorCorrector) correctBlock(block []byte, ecBlocks *decoder.ECBlocks,
 	// Finds error positions by evaluating Λ(α^{-j}) for all j
 	// Positions where Λ(α^{-j}) = 0 are error positions
 	// Chien search returns positions in standard polynomial convention (position i = x^i)
-	standardPositions := chien.ChienSearch(ec.field, lambda, codewordLength)
+	standardPositions, chienErr := chien.ChienSearchChecked(ec.field, lambda, codewordLength)
 
 	result.ErrorsFound = len(standardPositions)
 	result.ErrorPositions = standardPositions
@@ -320,14 +473,31 @@ func (ec *ErrorCorrector) correctBlock(block []byte, ecBlocks *decoder.ECBlocks,
 	maxCorrectableErrors := numECCodewords / 2
 	if len(standardPositions) > maxCorrectableErrors {
 		result.CorrectionSucceeded = false
+		result.DetectedUncorrectable = true
 		return nil, result, fmt.Errorf("too many errors: found %d, can correct %d", len(standardPositions), maxCorrectableErrors)
 	}
 
+	// A locator of degree ν must have ν roots inside the codeword; fewer means
+	// the errors cannot be located, typically because there are more than t
+	if chienErr != nil {
+		result.CorrectionSucceeded = false
+		result.DetectedUncorrectable = true
+		return nil, result, fmt.Errorf("errors detected but not located: %w", chienErr)
+	}
+
 	// Step 5: Forney Algorithm
 	// Computes error magnitudes using Forney's formula:
 	// Y_i = X_i · Ω(X_i^{-1}) / Λ'(X_i^{-1})
 	magnitudes := forney.ComputeErrorMagnitudes(ec.field, lambda, omega, standardPositions)
 
+	// Forney's formula assumes syndromes from α^0; with S_i = r(α^(b+i)) it
+	// returns Y_i·X_i^b, so divide the X_i^b back out
+	if ec.firstRoot != 0 {
+		for i, pos := range standardPositions {
+			magnitudes[i] = ec.field.Mul(magnitudes[i], ec.field.Pow(ec.field.Primitive(), -pos*ec.firstRoot))
+		}
+	}
+
 	// Step 6: Translate positions from standard to QR's reverse convention
 	// In standard convention: position i means codeword[i] (x^i coefficient)
 	// In QR's reverse convention: codeword[0] is highest degree, so position i means codeword[n-1-i]
@@ -343,7 +513,10 @@ func (ec *ErrorCorrector) correctBlock(block []byte, ecBlocks *decoder.ECBlocks,
 	// Step 7: Verify correction
 	// Compute syndromes of corrected codeword - should all be zero
 	// We use our own computeSyndromes which uses QR's reverse polynomial evaluation
-	verifySyndromes := ec.computeSyndromes(corrected, numECCodewords)
+	verifySyndromes, err := ec.computeSyndromes(corrected, numECCodewords)
+	if err != nil {
+		return nil, result, err
+	}
 	isValid := true
 	for _, s := range verifySyndromes {
 		if !s.IsZero() {
@@ -353,19 +526,22 @@ func (ec *ErrorCorrector) correctBlock(block []byte, ecBlocks *decoder.ECBlocks,
 	}
 	if !isValid {
 		result.CorrectionSucceeded = false
+		result.DetectedUncorrectable = true
 		return nil, result, fmt.Errorf("correction verification failed")
 	}
 
 	result.CorrectionSucceeded = true
 
-	// Convert corrected elements back to bytes and return only data portion
-	correctedBytes := make([]byte, numDataCodewords)
-	for i := 0; i < numDataCodewords; i++ {
+	// Convert corrected elements back to bytes; the result keeps the full
+	// codeword (data + EC) and only the data portion is returned
+	correctedBytes := make([]byte, codewordLength)
+	for i := range corrected {
 		// Convert GF(256) element back to byte using reverse lookup
 		correctedBytes[i] = ec.elementToByte(corrected[i])
 	}
+	result.CorrectedCodewords = correctedBytes
 
-	return correctedBytes, result, nil
+	return correctedBytes[:numDataCodewords], result, nil
 }
 
 // computeSyndromes calculates syndrome values for error detection
@@ -373,68 +549,235 @@ func (ec *ErrorCorrector) correctBlock(block []byte, ecBlocks *decoder.ECBlocks,
 // Syndromes are computed by evaluating the received polynomial at consecutive
 // powers of α (the primitive element):
 //
-//	S_i = r(α^i) for i = 0, 1, ..., 2t-1
+//	S_i = r(α^(b+i)) for i = 0, 1, ..., NumSyndromes(numECCodewords)-1
+//
+// where b is the first root, 0 for QR codes (see NewErrorCorrectorFCR).
 //
-// where t is the error correction capability (number of errors that can be corrected).
+// QR codes compute one syndrome per EC codeword rather than 2t (see
+// correction.NumSyndromes).
 //
 // If all syndromes are zero, the received codeword is valid (no errors).
 // Otherwise, the syndrome values encode information about error positions and magnitudes.
-func (ec *ErrorCorrector) computeSyndromes(received []gfpn.Element, numSyndromes int) []gfpn.Element {
-	alpha := ec.field.Primitive()
-	syndromes := make([]gfpn.Element, numSyndromes)
+// An empty received word returns syndrome.ErrEmptyCodeword instead of all-zero syndromes.
+func (ec *ErrorCorrector) computeSyndromes(received []gfpn.Element, numECCodewords int) ([]gfpn.Element, error) {
+	if len(received) == 0 {
+		return nil, syndrome.ErrEmptyCodeword
+	}
 
+	numSyndromes := correction.NumSyndromes(numECCodewords)
+	syndromes := make([]gfpn.Element, numSyndromes)
 	for i := 0; i < numSyndromes; i++ {
-		// Compute α^i
-		alphaToI := ec.field.One()
-		for j := 0; j < i; j++ {
-			alphaToI = ec.field.Mul(alphaToI, alpha)
-		}
+		syndromes[i] = ec.syndromeAt(received, i)
+	}
 
-		// Evaluate received polynomial at α^i using Horner's method
-		// QR codes treat received[0] as the highest degree coefficient
-		// r(α^i) = received[0]·α^i^(n-1) + received[1]·α^i^(n-2) + ... + received[n-1]
-		syndrome := ec.field.Zero()
-		for j := 0; j < len(received); j++ {
-			syndrome = ec.field.Mul(syndrome, alphaToI)
-			syndrome = ec.field.Add(syndrome, received[j])
-		}
+	return syndromes, nil
+}
+
+// syndromeAt evaluates the received polynomial at α^(b+i), giving syndrome S_i
+// The evaluation point comes from the precomputed syndromeRoots; only blocks with
+// more EC codewords than any QR version (see SetECOverride) compute it on the fly.
+func (ec *ErrorCorrector) syndromeAt(received []gfpn.Element, i int) gfpn.Element {
+	var alphaToI gfpn.Element
+	if i < len(ec.syndromeRoots) {
+		alphaToI = ec.syndromeRoots[i]
+	} else {
+		alphaToI = ec.field.Primitive().Pow(ec.firstRoot + i)
+	}
 
-		syndromes[i] = syndrome
+	// Evaluate received polynomial at α^i using Horner's method
+	// QR codes treat received[0] as the highest degree coefficient
+	// r(α^i) = received[0]·α^i^(n-1) + received[1]·α^i^(n-2) + ... + received[n-1]
+	value := ec.field.Zero()
+	for j := 0; j < len(received); j++ {
+		value = ec.field.Mul(value, alphaToI)
+		value = ec.field.Add(value, received[j])
 	}
+	return value
+}
 
-	return syndromes
+// HasErrors reports whether a received word contains errors, without computing all syndromes
+//
+// Detection only needs one non-zero syndrome, so the syndromes are evaluated one at a
+// time and the check stops at the first non-zero one. Most corrupted words are caught
+// by S_0; only clean words (and error patterns whose leading syndromes happen to
+// vanish) pay for all NumSyndromes(numEC) evaluations. An empty word is not a valid
+// codeword and counts as erroneous, as in computeSyndromes.
+//
+// Parameters:
+//   - received: The received word, received[0] being the highest degree coefficient
+//   - numEC: Number of EC codewords at the end of the word
+//
+// Returns:
+//   - true if any syndrome is non-zero
+func (ec *ErrorCorrector) HasErrors(received []gfpn.Element, numEC int) bool {
+	if len(received) == 0 {
+		return true
+	}
+	return ec.firstNonzeroSyndrome(received, numEC) >= 0
 }
 
-// reinterleaveBlocks combines corrected blocks back into a single data stream
+// firstNonzeroSyndrome returns the index of the first non-zero syndrome, or -1 if all are zero
+func (ec *ErrorCorrector) firstNonzeroSyndrome(received []gfpn.Element, numEC int) int {
+	for i := 0; i < correction.NumSyndromes(numEC); i++ {
+		if !ec.syndromeAt(received, i).IsZero() {
+			return i
+		}
+	}
+	return -1
+}
+
+// concatenateBlocks joins the data codewords of the blocks into a single data stream
 //
-// This reverses the de-interleaving process, but only returns the data codewords
-// (not the error correction codewords, which are no longer needed).
-func (ec *ErrorCorrector) reinterleaveBlocks(blocks [][]byte, ecBlocks *decoder.ECBlocks) []byte {
-	// Calculate total data codewords
-	totalDataCodewords := 0
+// Interleaving only concerns the order codewords are placed in the symbol. The
+// data bit stream itself is the data codewords of block 1, then those of block 2,
+// and so on, so after de-interleaving the blocks are simply concatenated.
+// (Interleaving them again would mix segments of different blocks.)
+func concatenateBlocks(blocks [][]byte) []byte {
+	var data []byte
 	for _, block := range blocks {
-		totalDataCodewords += len(block)
+		data = append(data, block...)
 	}
+	return data
+}
 
-	data := make([]byte, totalDataCodewords)
-	dataIndex := 0
+// encodeBlock appends the Reed-Solomon EC codewords to one block of data codewords
+//
+// The EC codewords are computed by correction.RSEncoder with the error corrector's
+// generator, whose roots are α^b, ..., α^(b+numEC-1) for the first root b (0 for
+// QR codes), so every syndrome of the result is zero. RSEncoder works on vectors
+// lowest degree first, while data[0] is the highest degree coefficient, so the
+// block is reversed on the way in and its parity on the way out.
+func (ec *ErrorCorrector) encodeBlock(data []byte, numEC int) ([]byte, error) {
+	rsEncoder, err := correction.NewRSEncoderFCR(ec.field, numEC, ec.firstRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RS encoder: %w", err)
+	}
 
-	// Re-interleave: alternate taking one byte from each block
-	maxBlockSize := 0
-	for _, block := range blocks {
-		if len(block) > maxBlockSize {
-			maxBlockSize = len(block)
-		}
+	message := make([]gfpn.Element, len(data))
+	for i, b := range data {
+		message[len(data)-1-i] = ec.byteToElement(b)
+	}
+
+	// Parity entry k is the coefficient of x^k, so EC codeword i is entry numEC-1-i
+	parity := rsEncoder.Parity(message)
+	block := append(make([]byte, 0, len(data)+numEC), data...)
+	for i := numEC - 1; i >= 0; i-- {
+		block = append(block, ec.elementToByte(parity[i]))
 	}
 
-	for i := 0; i < maxBlockSize; i++ {
-		for j := 0; j < len(blocks); j++ {
-			if i < len(blocks[j]) {
-				data[dataIndex] = blocks[j][i]
-				dataIndex++
+	return block, nil
+}
+
+// EncodeCodewords computes the EC codewords for the data codewords of a symbol and
+// interleaves both into the raw codeword sequence, as placed in the module grid
+//
+// This is the encoding counterpart of CorrectCodewords:
+//  1. Split the data (block 1's data codewords, then block 2's, ...) into RS blocks
+//  2. Compute the EC codewords of every block (see encodeBlock)
+//  3. Interleave data and EC codewords (D1-B1, D1-B2, ..., EC1-B1, EC1-B2, ...)
+//
+// The generator has the error corrector's first consecutive root, so codewords
+// from NewErrorCorrectorFCR(b) are corrected by the same error corrector. Only
+// b = 0 (NewErrorCorrector) gives codewords that QR readers accept.
+//
+// Parameters:
+//   - data: The data codewords of the whole symbol in block order
+//   - version: QR code version
+//   - ecLevel: Error correction level
+//
+// Returns:
+//   - The interleaved data and EC codewords (version.GetTotalCodewords() bytes)
+//   - Error if the data length does not match the version and level
+func (ec *ErrorCorrector) EncodeCodewords(data []byte, version *decoder.Version, ecLevel decoder.ErrorCorrectionLevel) ([]byte, error) {
+	ecBlocks := version.GetECBlocksForLevel(ecLevel)
+	numECCodewords := ecBlocks.GetECCodewordsPerBlock()
+
+	totalDataCodewords := version.GetTotalCodewords() - ecBlocks.GetTotalECCodewords()
+	if len(data) != totalDataCodewords {
+		return nil, fmt.Errorf("version %d-%s expects %d data codewords, got %d",
+			version.GetVersionNumber(), ecLevel, totalDataCodewords, len(data))
+	}
+
+	// Steps 1 and 2: Split the data into blocks and append their EC codewords
+	var blocks [][]byte
+	dataIndex := 0
+	for _, ecb := range ecBlocks.GetECBlocks() {
+		for i := 0; i < ecb.GetCount(); i++ {
+			block, err := ec.encodeBlock(data[dataIndex:dataIndex+ecb.GetDataCodewords()], numECCodewords)
+			if err != nil {
+				return nil, err
 			}
+			blocks = append(blocks, block)
+			dataIndex += ecb.GetDataCodewords()
 		}
 	}
 
-	return data
+	// Step 3: Interleave, see codewordBlockMap
+	rawCodewords := make([]byte, version.GetTotalCodewords())
+	for rawIndex, pos := range codewordBlockMap(ecBlocks) {
+		rawCodewords[rawIndex] = blocks[pos.Block][pos.Position]
+	}
+
+	return rawCodewords, nil
+}
+
+// RebuildBitMatrix writes corrected data codewords back into a QR code module grid
+//
+// This is the inverse of extraction, useful for rendering a "cleaned" version of
+// a damaged QR code:
+//  1. Split the corrected data (block 1's data codewords, then block 2's, ...) into RS blocks
+//  2. Re-encode the EC codewords of every block
+//  3. Interleave data and EC codewords into the raw codeword sequence (steps 1-3
+//     are EncodeCodewords)
+//  4. Place the codeword bits on the data modules in reading order (remainder bits are 0)
+//  5. Re-apply the data mask
+//
+// Function patterns (finders, timing, format and version information) are taken
+// from qrData.BitMatrix. The extractor unmasks the whole matrix in place, so
+// applying the mask again restores them along with the data modules.
+//
+// Parameters:
+//   - qrData: Extracted QR code data (provides version, EC level, mask and function patterns)
+//   - correctedData: Corrected data codewords, as returned by CorrectCodewords
+//
+// Returns:
+//   - The masked module grid as it appears in the QR code symbol
+//   - Error if the data length does not match the version or no matrix is available
+func (ec *ErrorCorrector) RebuildBitMatrix(qrData *types.QRCodeData, correctedData []byte) (*gozxing.BitMatrix, error) {
+	if qrData.Version == nil || qrData.BitMatrix == nil {
+		return nil, fmt.Errorf("QR code data has no version or bit matrix")
+	}
+
+	version := qrData.Version
+
+	// Steps 1-3: Re-encode and interleave
+	rawCodewords, err := ec.EncodeCodewords(correctedData, version, qrData.ECLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 4: Place codeword bits (MSB first) on the data modules
+	matrix, err := gozxing.NewBitMatrix(qrData.BitMatrix.GetWidth(), qrData.BitMatrix.GetHeight())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bit matrix: %w", err)
+	}
+	if err := matrix.Xor(qrData.BitMatrix); err != nil {
+		return nil, fmt.Errorf("failed to copy bit matrix: %w", err)
+	}
+	for bitIndex, pos := range types.DataModulePositions(version) {
+		dark := false
+		if bitIndex < len(rawCodewords)*8 {
+			dark = rawCodewords[bitIndex/8]&(0x80>>(bitIndex%8)) != 0
+		}
+		if dark {
+			matrix.Set(pos.Col, pos.Row)
+		} else {
+			matrix.Unset(pos.Col, pos.Row)
+		}
+	}
+
+	// Step 5: Re-apply the data mask (masking is an XOR, so unmasking twice restores it)
+	decoder.DataMaskValues[qrData.DataMask].UnmaskBitMatrix(matrix, matrix.GetHeight())
+
+	return matrix, nil
 }
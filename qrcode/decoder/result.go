@@ -1,5 +1,12 @@
 package decoder
 
+import (
+	"fmt"
+	"io"
+
+	"github.com/jalphad/abstract_algebra/qrcode/correction"
+)
+
 // DecodeResult contains the result of QR code decoding
 //
 // This structure provides detailed information about the decoding process,
@@ -24,6 +31,28 @@ type DecodeResult struct {
 	// BlockResults contains detailed results for each RS block
 	// QR codes use multiple blocks for higher versions/error correction levels
 	BlockResults []BlockResult
+
+	// DataMask and ECLevel are the mask pattern and error correction level the
+	// message was decoded with. Decode uses those from the format information;
+	// DecodeCandidates reports the combination each candidate was found with.
+	DataMask byte
+	ECLevel  string
+
+	// Confidence is the probability that none of the blocks was miscorrected,
+	// i.e. the product of 1 - correction.MiscorrectionRisk over all blocks.
+	// It is 1 for a clean code and drops as more errors are corrected.
+	Confidence float64
+}
+
+// decodeConfidence combines the miscorrection risk of every block into a confidence in [0, 1]
+func decodeConfidence(blockResults []BlockResult) float64 {
+	confidence := 1.0
+	for _, block := range blockResults {
+		risk := correction.MiscorrectionRisk(block.ErrorsFound, block.NumECCodewords,
+			block.NumDataCodewords+block.NumECCodewords)
+		confidence *= 1 - risk
+	}
+	return confidence
 }
 
 // BlockResult contains error correction details for a single Reed-Solomon block
@@ -46,4 +75,69 @@ type BlockResult struct {
 	// CorrectionSucceeded indicates if correction worked for this block
 	// Correction fails when errors exceed the correction capacity
 	CorrectionSucceeded bool
+
+	// DetectedUncorrectable is set when the syndromes show errors that could not
+	// be located or corrected, i.e. more errors than the block can handle. It is
+	// false for clean blocks and for successful corrections.
+	DetectedUncorrectable bool
+
+	// ReceivedCodewords holds the block's data codewords followed by its EC
+	// codewords as read, before correction. It is set when block capture is
+	// enabled (see Decoder.SetCaptureBlocks), and always for a block whose
+	// correction failed.
+	ReceivedCodewords []byte
+
+	// CorrectedCodewords holds the full corrected codeword, data followed by EC
+	// codewords, e.g. for re-encoding checks. Its data prefix is what
+	// CorrectCodewords returns for this block. Nil when correction fails.
+	CorrectedCodewords []byte
+}
+
+// WriteBlockDump writes the captured codewords of each block in hex, before and after correction
+//
+// Blocks decoded without block capture only show their corrected codewords.
+// Useful for debugging extraction and interleaving problems: a correctly read
+// code shows identical received and corrected bytes.
+//
+// Example output (Version 1-L "Hello" with one corrupted codeword):
+//
+//	Block 0 (19 data + 7 EC codewords, 1 error(s) corrected):
+//	  Received data:  40 56 86 56 c6 c6 f0 ec 11 ec 11 ec 11 ec 11 ec 11 ec 11
+//	  Received EC:    ad 7a 96 3d 85 93 5e
+//	  Corrected data: 40 54 86 56 c6 c6 f0 ec 11 ec 11 ec 11 ec 11 ec 11 ec 11
+//	  Corrected EC:   ad 7a 96 3d 85 93 5e
+func WriteBlockDump(w io.Writer, blocks []BlockResult) error {
+	for _, block := range blocks {
+		if _, err := fmt.Fprintf(w, "Block %d (%d data + %d EC codewords, %d error(s) corrected):\n",
+			block.BlockIndex, block.NumDataCodewords, block.NumECCodewords, block.ErrorsFound); err != nil {
+			return err
+		}
+
+		if err := writeCodewordLines(w, "Received", block.ReceivedCodewords, block.NumDataCodewords); err != nil {
+			return err
+		}
+
+		if block.ReceivedCodewords != nil && block.CorrectedCodewords == nil {
+			if _, err := fmt.Fprintln(w, "  Corrected:      (correction failed)"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeCodewordLines(w, "Corrected", block.CorrectedCodewords, block.NumDataCodewords); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCodewordLines writes the data and EC portions of a block's codewords on separate lines
+func writeCodewordLines(w io.Writer, label string, codewords []byte, numData int) error {
+	if codewords == nil {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "  %-15s % x\n", label+" data:", codewords[:numData]); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "  %-15s % x\n", label+" EC:", codewords[numData:])
+	return err
 }
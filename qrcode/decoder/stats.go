@@ -0,0 +1,125 @@
+package decoder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jalphad/abstract_algebra/qrcode/types"
+)
+
+// Stats aggregates decoding statistics across many QR codes
+//
+// This is intended for batch processing, e.g. decoding a directory of images:
+//
+//	stats := decoder.NewStats()
+//	for _, path := range paths {
+//	    qrData, _ := extractor.ExtractFromImage(path)
+//	    result, _ := dec.Decode(qrData)
+//	    stats.Observe(result, qrData)
+//	}
+//	fmt.Print(stats.Report())
+type Stats struct {
+	// Decoded is the number of QR codes that decoded successfully
+	Decoded int
+
+	// Failed is the number of QR codes that could not be decoded
+	Failed int
+
+	// TotalErrorsCorrected is the sum of corrected symbol errors over all successful decodes
+	TotalErrorsCorrected int
+
+	// MaxErrorsCorrected is the largest number of errors corrected in a single QR code
+	MaxErrorsCorrected int
+
+	// ECLevels counts observed QR codes by error correction level ("L", "M", "Q", "H")
+	ECLevels map[string]int
+
+	// Versions counts observed QR codes by version number
+	Versions map[int]int
+}
+
+// NewStats creates an empty statistics aggregator
+func NewStats() *Stats {
+	return &Stats{
+		ECLevels: make(map[string]int),
+		Versions: make(map[int]int),
+	}
+}
+
+// Observe records the outcome of one decode
+//
+// A nil or unsuccessful result counts as a failure. The version and EC level
+// distributions are taken from qrData and include failed decodes, as long as
+// extraction got far enough to produce qrData.
+//
+// Parameters:
+//   - result: The result returned by Decoder.Decode (may be nil)
+//   - qrData: The extracted QR code data (may be nil)
+func (s *Stats) Observe(result *DecodeResult, qrData *types.QRCodeData) {
+	if s.ECLevels == nil {
+		s.ECLevels = make(map[string]int)
+	}
+	if s.Versions == nil {
+		s.Versions = make(map[int]int)
+	}
+
+	if qrData != nil && qrData.Version != nil {
+		s.Versions[qrData.Version.GetVersionNumber()]++
+		s.ECLevels[qrData.ECLevel.String()]++
+	}
+
+	if result == nil || !result.CorrectionSuccessful {
+		s.Failed++
+		return
+	}
+
+	s.Decoded++
+	s.TotalErrorsCorrected += result.NumErrorsCorrected
+	s.MaxErrorsCorrected = max(s.MaxErrorsCorrected, result.NumErrorsCorrected)
+}
+
+// Total returns the number of observed QR codes
+func (s *Stats) Total() int {
+	return s.Decoded + s.Failed
+}
+
+// String returns a one-line summary of the statistics
+func (s *Stats) String() string {
+	return fmt.Sprintf("%d decoded, %d failed, %d errors corrected",
+		s.Decoded, s.Failed, s.TotalErrorsCorrected)
+}
+
+// Report returns a multi-line summary including the version and EC level distributions
+func (s *Stats) Report() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "QR codes observed: %d\n", s.Total())
+	fmt.Fprintf(&sb, "  Decoded: %d\n", s.Decoded)
+	fmt.Fprintf(&sb, "  Failed: %d\n", s.Failed)
+	fmt.Fprintf(&sb, "Errors corrected: %d total, %d max per code", s.TotalErrorsCorrected, s.MaxErrorsCorrected)
+	if s.Decoded > 0 {
+		fmt.Fprintf(&sb, ", %.2f average", float64(s.TotalErrorsCorrected)/float64(s.Decoded))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("EC levels:\n")
+	for _, level := range []string{"L", "M", "Q", "H"} {
+		if count := s.ECLevels[level]; count > 0 {
+			fmt.Fprintf(&sb, "  %s: %d\n", level, count)
+		}
+	}
+
+	versions := make([]int, 0, len(s.Versions))
+	for v := range s.Versions {
+		versions = append(versions, v)
+	}
+	sort.Ints(versions)
+
+	sb.WriteString("Versions:\n")
+	for _, v := range versions {
+		fmt.Fprintf(&sb, "  %d: %d\n", v, s.Versions[v])
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,232 @@
+package qrcode
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/jalphad/abstract_algebra/qrcode/decoder"
+	"github.com/jalphad/abstract_algebra/qrcode/types"
+	"github.com/makiuchi-d/gozxing"
+	zxingdecoder "github.com/makiuchi-d/gozxing/qrcode/decoder"
+	"github.com/makiuchi-d/gozxing/qrcode/encoder"
+)
+
+// quietZoneModules is the light border around the symbol required by the specification
+const quietZoneModules = 4
+
+// Segment headers written by encodeData (ISO/IEC 18004, sections 7.4.2 and 7.4.5)
+const (
+	eciModeIndicator  = 0x7  // 0111: ECI designator follows
+	byteModeIndicator = 0x4  // 0100: 8-bit byte data follows
+	utf8ECIAssignment = 26   // ECI assignment number of UTF-8
+	padCodeword1      = 0xEC // Pad codewords fill unused data capacity, alternating
+	padCodeword2      = 0x11
+)
+
+// EncodeToImage encodes a message as a QR code and renders it as an image
+//
+// This closes the loop with DecodeImage: the image can be written with image/png
+// and read back by the extractor. The symbol is built from this repository's own
+// pieces:
+//  1. The message is written as a single byte mode segment (encodeData), in the
+//     smallest version that fits; messages that are not pure ASCII are marked
+//     with a UTF-8 ECI designator
+//  2. The EC codewords are computed with correction.RSEncoder and interleaved
+//     with the data (decoder.ErrorCorrector.EncodeCodewords)
+//  3. The data mask is chosen with types.BestMaskByPenalty
+//
+// gozxing is only used to place the function patterns, format and version
+// information and codeword bits in the module grid (encoder.MatrixUtil_buildMatrix).
+//
+// Parameters:
+//   - message: Text to encode
+//   - ecLevel: Error correction level ("L", "M", "Q" or "H")
+//   - moduleSize: Side length of one module in pixels (at least 1)
+//
+// Returns:
+//   - A grayscale image of the symbol surrounded by a 4-module quiet zone
+//   - Error if the level or module size is invalid or the message does not fit
+//
+// Example:
+//
+//	img, err := qrcode.EncodeToImage("Hello, World!", "M", 8)
+//	if err != nil {
+//	    return err
+//	}
+//	err = png.Encode(file, img)
+func EncodeToImage(message string, ecLevel string, moduleSize int) (image.Image, error) {
+	if moduleSize < 1 {
+		return nil, fmt.Errorf("module size must be at least 1 pixel, got %d", moduleSize)
+	}
+	level, err := zxingdecoder.ErrorCorrectionLevel_ValueOf(ecLevel)
+	if err != nil {
+		return nil, fmt.Errorf("invalid error correction level %q", ecLevel)
+	}
+
+	version, data, err := encodeData(message, level)
+	if err != nil {
+		return nil, err
+	}
+
+	errorCorrector, err := decoder.NewErrorCorrector()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create error corrector: %w", err)
+	}
+	rawCodewords, err := errorCorrector.EncodeCodewords(data, version, level)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute EC codewords: %w", err)
+	}
+
+	bits := gozxing.NewEmptyBitArray()
+	for _, codeword := range rawCodewords {
+		if err := bits.AppendBits(int(codeword), 8); err != nil {
+			return nil, fmt.Errorf("failed to append codeword bits: %w", err)
+		}
+	}
+
+	mask, err := chooseMask(bits, level, version)
+	if err != nil {
+		return nil, err
+	}
+
+	dimension := version.GetDimensionForVersion()
+	matrix := encoder.NewByteMatrix(dimension, dimension)
+	if err := encoder.MatrixUtil_buildMatrix(bits, level, version, mask, matrix); err != nil {
+		return nil, fmt.Errorf("failed to place modules: %w", err)
+	}
+
+	size := (dimension + 2*quietZoneModules) * moduleSize
+
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	for y := 0; y < dimension; y++ {
+		for x := 0; x < dimension; x++ {
+			if matrix.Get(x, y) != 1 {
+				continue
+			}
+			left := (x + quietZoneModules) * moduleSize
+			top := (y + quietZoneModules) * moduleSize
+			for py := top; py < top+moduleSize; py++ {
+				for px := left; px < left+moduleSize; px++ {
+					img.SetGray(px, py, color.Gray{0})
+				}
+			}
+		}
+	}
+
+	return img, nil
+}
+
+// encodeData writes a message as the data codewords of the smallest version that
+// holds it at the given level
+//
+// The message is a single byte mode segment: the mode indicator, the character
+// count (8 bits up to version 9, 16 bits from version 10) and the bytes of the
+// message, preceded by a UTF-8 ECI designator if the message is not pure ASCII.
+// Up to 4 zero bits terminate the data, zero bits pad it to a codeword boundary
+// and the pad codewords 0xEC and 0x11 fill the remaining capacity.
+func encodeData(message string, level zxingdecoder.ErrorCorrectionLevel) (*zxingdecoder.Version, []byte, error) {
+	headerBits := 0
+	if !isASCII(message) {
+		headerBits = 4 + 8
+	}
+
+	var version *zxingdecoder.Version
+	var countBits, capacity int
+	for number := 1; number <= 40 && version == nil; number++ {
+		candidate, err := zxingdecoder.Version_GetVersionForNumber(number)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get version %d: %w", number, err)
+		}
+		countBits = 8
+		if number >= 10 {
+			countBits = 16
+		}
+		capacity = candidate.GetTotalCodewords() - candidate.GetECBlocksForLevel(level).GetTotalECCodewords()
+		if headerBits+4+countBits+8*len(message) <= 8*capacity {
+			version = candidate
+		}
+	}
+	if version == nil {
+		return nil, nil, fmt.Errorf("message of %d bytes does not fit in a version 40-%s symbol", len(message), level)
+	}
+
+	bits := gozxing.NewEmptyBitArray()
+	appendBits := func(value, numBits int) {
+		// Values are masked to numBits, which AppendBits accepts up to 32
+		_ = bits.AppendBits(value&(1<<numBits-1), numBits)
+	}
+
+	if headerBits > 0 {
+		appendBits(eciModeIndicator, 4)
+		appendBits(utf8ECIAssignment, 8)
+	}
+	appendBits(byteModeIndicator, 4)
+	appendBits(len(message), countBits)
+	for i := 0; i < len(message); i++ {
+		appendBits(int(message[i]), 8)
+	}
+
+	appendBits(0, min(4, 8*capacity-bits.GetSize()))
+	if rest := bits.GetSize() % 8; rest != 0 {
+		appendBits(0, 8-rest)
+	}
+	for pad := padCodeword1; bits.GetSize() < 8*capacity; pad ^= padCodeword1 ^ padCodeword2 {
+		appendBits(pad, 8)
+	}
+
+	data := make([]byte, capacity)
+	bits.ToBytes(0, data, 0, capacity)
+	return version, data, nil
+}
+
+// chooseMask picks the data mask for the interleaved codeword bits of a symbol
+//
+// The modules are placed once with mask 0, which is then undone on the data
+// modules only, leaving function patterns and format information intact as
+// types.BestMaskByPenalty requires.
+func chooseMask(bits *gozxing.BitArray, level zxingdecoder.ErrorCorrectionLevel, version *zxingdecoder.Version) (int, error) {
+	dimension := version.GetDimensionForVersion()
+	placed := encoder.NewByteMatrix(dimension, dimension)
+	if err := encoder.MatrixUtil_buildMatrix(bits, level, version, 0, placed); err != nil {
+		return 0, fmt.Errorf("failed to place modules: %w", err)
+	}
+
+	grid, err := gozxing.NewBitMatrix(dimension, dimension)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create bit matrix: %w", err)
+	}
+	for y := 0; y < dimension; y++ {
+		for x := 0; x < dimension; x++ {
+			if placed.Get(x, y) == 1 {
+				grid.Set(x, y)
+			}
+		}
+	}
+
+	// Mask 0 darkens modules with (row + column) mod 2 == 0
+	for _, pos := range types.DataModulePositions(version) {
+		if (pos.Row+pos.Col)%2 == 0 {
+			grid.Flip(pos.Col, pos.Row)
+		}
+	}
+
+	mask, err := types.BestMaskByPenalty(grid)
+	if err != nil {
+		return 0, fmt.Errorf("failed to choose mask: %w", err)
+	}
+	return mask, nil
+}
+
+// isASCII reports whether a string only contains 7-bit characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package qrcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jalphad/abstract_algebra/qrcode/decoder"
+	"github.com/jalphad/abstract_algebra/qrcode/types"
+)
+
+// DecodeImage reads a QR code image file and returns the decoded message
+//
+// This wires together the complete pipeline in one call:
+//  1. Extraction: locate the code and read the raw codewords (types.QRExtractor)
+//  2. Decoding: Reed-Solomon error correction and data decoding (decoder.Decoder)
+//
+// Example:
+//
+//	message, err := qrcode.DecodeImage("qr_code.png")
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(message)
+func DecodeImage(path string) (string, error) {
+	qrData, err := types.NewQRExtractor().ExtractFromImage(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract QR code: %w", err)
+	}
+
+	return decode(qrData)
+}
+
+// DecodeImageReader reads a QR code image (PNG or JPEG) from a reader and returns the decoded message
+//
+// This is the streaming counterpart of DecodeImage, useful for HTTP request
+// bodies or embedded resources where there is no file on disk.
+func DecodeImageReader(r io.Reader) (string, error) {
+	qrData, err := types.NewQRExtractor().ExtractFromReader(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract QR code: %w", err)
+	}
+
+	return decode(qrData)
+}
+
+// DecodeImageBase64 decodes a QR code image (PNG or JPEG) given as base64 and returns the decoded message
+//
+// Web APIs often receive images as data URIs, so an optional prefix such as
+// "data:image/png;base64," is stripped before decoding. The image is decoded in
+// memory; no intermediate file is written.
+//
+// Example:
+//
+//	message, err := qrcode.DecodeImageBase64("data:image/png;base64,iVBORw0KGgo...")
+func DecodeImageBase64(data string) (string, error) {
+	data = strings.TrimSpace(data)
+
+	if strings.HasPrefix(data, "data:") {
+		_, payload, ok := strings.Cut(data, ";base64,")
+		if !ok {
+			return "", fmt.Errorf("data URI is not base64-encoded")
+		}
+		data = payload
+	}
+
+	content, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode base64: %w", err)
+	}
+
+	return DecodeImageReader(bytes.NewReader(content))
+}
+
+// decode runs error correction and data decoding on extracted QR code data
+func decode(qrData *types.QRCodeData) (string, error) {
+	dec, err := decoder.NewDecoder()
+	if err != nil {
+		return "", fmt.Errorf("failed to create decoder: %w", err)
+	}
+
+	result, err := dec.Decode(qrData)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode QR code: %w", err)
+	}
+
+	return result.Message, nil
+}
@@ -0,0 +1,176 @@
+package qrcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jalphad/abstract_algebra/qrcode/types"
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/makiuchi-d/gozxing/qrcode/encoder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeImage(t *testing.T) {
+	// Arrange
+	testMessage := "Hello, pipeline!"
+	path := filepath.Join(t.TempDir(), "qr.png")
+	require.NoError(t, os.WriteFile(path, createTestQRPNG(t, testMessage), 0644))
+
+	// Act
+	message, err := DecodeImage(path)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, testMessage, message)
+}
+
+func TestDecodeImage_NotAnImage(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "invalid.txt")
+	require.NoError(t, os.WriteFile(path, []byte("This is not an image"), 0644))
+
+	// Act
+	message, err := DecodeImage(path)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Empty(t, message)
+}
+
+func TestDecodeImageReader(t *testing.T) {
+	// Arrange
+	testMessage := "Hello, reader!"
+	data := createTestQRPNG(t, testMessage)
+
+	// Act
+	message, err := DecodeImageReader(bytes.NewReader(data))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, testMessage, message)
+
+	// Act & Assert: garbage input errors cleanly
+	_, err = DecodeImageReader(strings.NewReader("This is not an image"))
+	assert.Error(t, err)
+}
+
+func TestDecodeImageBase64(t *testing.T) {
+	// Arrange
+	testMessage := "Hello, base64!"
+	encoded := base64.StdEncoding.EncodeToString(createTestQRPNG(t, testMessage))
+
+	// Act & Assert: plain base64 and a data URI both decode
+	for _, input := range []string{encoded, "data:image/png;base64," + encoded} {
+		message, err := DecodeImageBase64(input)
+		require.NoError(t, err)
+		assert.Equal(t, testMessage, message)
+	}
+
+	// Act & Assert: invalid input errors cleanly
+	_, err := DecodeImageBase64("data:image/png," + encoded)
+	assert.Error(t, err)
+	_, err = DecodeImageBase64("not base64!")
+	assert.Error(t, err)
+}
+
+// createTestQRPNG encodes a message as a QR code and returns it as PNG bytes
+func createTestQRPNG(t *testing.T, content string) []byte {
+	bitMatrix, err := qrcode.NewQRCodeWriter().Encode(content, gozxing.BarcodeFormat_QR_CODE, 256, 256, nil)
+	require.NoError(t, err)
+
+	img := image.NewGray(image.Rect(0, 0, bitMatrix.GetWidth(), bitMatrix.GetHeight()))
+	for y := 0; y < bitMatrix.GetHeight(); y++ {
+		for x := 0; x < bitMatrix.GetWidth(); x++ {
+			if bitMatrix.Get(x, y) {
+				img.Set(x, y, color.Gray{0}) // Black
+			} else {
+				img.Set(x, y, color.Gray{255}) // White
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestEncodeToImage_RoundTrip(t *testing.T) {
+	tests := []struct {
+		message    string
+		level      string
+		moduleSize int
+	}{
+		{"Hello, World!", "M", 8},
+		{"Closing the loop: encode, extract, correct, decode", "H", 4},
+		{"Grüße, 世界", "Q", 6},
+		{strings.Repeat("Version 10 needs a 16-bit count. ", 8), "L", 2},
+	}
+	for _, tt := range tests {
+		// Arrange
+		img, err := EncodeToImage(tt.message, tt.level, tt.moduleSize)
+		require.NoError(t, err, tt.message)
+
+		// The symbol plus its quiet zone is a whole number of modules
+		width := img.Bounds().Dx()
+		assert.Equal(t, width, img.Bounds().Dy())
+		assert.Zero(t, width%tt.moduleSize)
+		dimension := width/tt.moduleSize - 8
+		assert.Equal(t, 1, dimension%4, "dimension %d is not 17 + 4v", dimension)
+
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, img))
+
+		// Act
+		message, err := DecodeImageReader(&buf)
+
+		// Assert
+		require.NoError(t, err, tt.message)
+		assert.Equal(t, tt.message, message)
+	}
+
+	_, err := EncodeToImage("Hello", "X", 4)
+	assert.Error(t, err)
+	_, err = EncodeToImage("Hello", "M", 0)
+	assert.Error(t, err)
+	_, err = EncodeToImage(strings.Repeat("x", 3000), "L", 1)
+	assert.Error(t, err)
+}
+
+func TestEncodeToImage_MaskByPenalty(t *testing.T) {
+	for _, message := range []string{"Hello", "Mask me", "Reed-Solomon", "Grüße, 世界"} {
+		// Arrange
+		img, err := EncodeToImage(message, "M", 4)
+		require.NoError(t, err, message)
+
+		extractor := types.NewQRExtractor()
+		extractor.KeepMatrices = true
+		qrData, err := extractor.ExtractFromGoImage(img)
+		require.NoError(t, err, message)
+
+		// Undo the mask on the data modules only, keeping function patterns intact
+		unmasked := qrData.MaskedMatrix
+		for _, pos := range types.DataModulePositions(qrData.Version) {
+			dark, err := encoder.MaskUtil_getDataMaskBit(int(qrData.DataMask), pos.Col, pos.Row)
+			require.NoError(t, err)
+			if dark {
+				unmasked.Flip(pos.Col, pos.Row)
+			}
+		}
+
+		// Act
+		mask, err := types.BestMaskByPenalty(unmasked)
+
+		// Assert: the symbol carries the mask with the lowest penalty
+		require.NoError(t, err, message)
+		assert.Equal(t, mask, int(qrData.DataMask), message)
+	}
+}
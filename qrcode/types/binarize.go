@@ -0,0 +1,196 @@
+package types
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/makiuchi-d/gozxing"
+)
+
+// Binarizer converts an image into a black/white matrix (true = dark module)
+//
+// Different capture conditions call for different strategies: a fixed global
+// threshold for clean renders, Otsu's method for evenly lit but low-contrast
+// prints, and a local (adaptive) threshold for uneven lighting. Set
+// QRExtractor.Binarizer to choose one.
+type Binarizer interface {
+	Binarize(img image.Image) (*gozxing.BitMatrix, error)
+}
+
+// defaultGlobalThreshold is the luminance cutoff used when GlobalThresholdBinarizer.Threshold is zero
+const defaultGlobalThreshold = 128
+
+// GlobalThresholdBinarizer marks every pixel darker than a fixed luminance as dark
+type GlobalThresholdBinarizer struct {
+	// Threshold is the luminance (1-255) below which a pixel counts as dark.
+	// Zero selects 128.
+	Threshold int
+}
+
+// Binarize implements Binarizer
+func (b GlobalThresholdBinarizer) Binarize(img image.Image) (*gozxing.BitMatrix, error) {
+	threshold := b.Threshold
+	if threshold <= 0 {
+		threshold = defaultGlobalThreshold
+	}
+	return thresholdBinarize(img, threshold)
+}
+
+// OtsuBinarizer chooses a global threshold with Otsu's method
+//
+// Otsu's method picks the luminance that best splits the histogram into two
+// classes, i.e. maximizes the variance between the dark and the light pixels.
+// Unlike a fixed cutoff it adapts to the actual ink and paper values, so a
+// washed-out print whose modules only differ by a few grey levels is still
+// separated cleanly, as long as the lighting is even.
+type OtsuBinarizer struct{}
+
+// Binarize implements Binarizer
+func (OtsuBinarizer) Binarize(img image.Image) (*gozxing.BitMatrix, error) {
+	source := gozxing.NewLuminanceSourceFromImage(img)
+	luminances := source.GetMatrix()
+
+	var histogram [256]int
+	for _, lum := range luminances {
+		histogram[lum]++
+	}
+
+	return thresholdBinarize(img, otsuThreshold(histogram, len(luminances)))
+}
+
+// otsuThreshold returns the threshold t maximizing the between-class variance of
+// the pixels below t and the pixels at or above t
+func otsuThreshold(histogram [256]int, total int) int {
+	sumAll := 0.0
+	for lum, count := range histogram {
+		sumAll += float64(lum * count)
+	}
+
+	best, bestVariance := defaultGlobalThreshold, -1.0
+	weightDark, sumDark := 0, 0.0
+	for t := 1; t < 256; t++ {
+		weightDark += histogram[t-1]
+		sumDark += float64((t - 1) * histogram[t-1])
+		weightLight := total - weightDark
+		if weightDark == 0 || weightLight == 0 {
+			continue
+		}
+
+		meanDark := sumDark / float64(weightDark)
+		meanLight := (sumAll - sumDark) / float64(weightLight)
+		variance := float64(weightDark) * float64(weightLight) * (meanDark - meanLight) * (meanDark - meanLight)
+		if variance > bestVariance {
+			best, bestVariance = t, variance
+		}
+	}
+
+	return best
+}
+
+// thresholdBinarize marks every pixel with luminance below threshold as dark
+func thresholdBinarize(img image.Image, threshold int) (*gozxing.BitMatrix, error) {
+	source := gozxing.NewLuminanceSourceFromImage(img)
+	width := source.GetWidth()
+	height := source.GetHeight()
+	luminances := source.GetMatrix()
+
+	matrix, err := gozxing.NewBitMatrix(width, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bit matrix: %w", err)
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if int(luminances[y*width+x]) < threshold {
+				matrix.Set(x, y)
+			}
+		}
+	}
+
+	return matrix, nil
+}
+
+// AdaptiveBinarizer thresholds each pixel against the mean of its neighbourhood
+// (see adaptiveBinarize); it is what QRExtractor.AdaptiveThreshold selects
+type AdaptiveBinarizer struct {
+	// Window is the side length of the local window in pixels (0 selects a default)
+	Window int
+
+	// Offset is how much darker than the local mean a pixel must be to count as dark
+	Offset int
+}
+
+// Binarize implements Binarizer
+func (b AdaptiveBinarizer) Binarize(img image.Image) (*gozxing.BitMatrix, error) {
+	return adaptiveBinarize(img, b.Window, b.Offset)
+}
+
+// defaultThresholdWindowDivisor sets the default local window to 1/8 of the
+// smaller image side, which spans several modules for typical QR code images
+const defaultThresholdWindowDivisor = 8
+
+// minThresholdWindow is the smallest local window used for adaptive thresholding
+const minThresholdWindow = 15
+
+// adaptiveBinarize converts an image to a black/white matrix using a local mean threshold
+//
+// Each pixel is compared against the mean luminance of the window x window square
+// centered on it. A pixel is dark when its luminance is more than offset below
+// that local mean. Because the threshold follows the neighbourhood, uneven lighting
+// and compression artifacts that shift the overall brightness of a region do not
+// flip whole areas of the code, which is where a single global threshold fails.
+//
+// The local means are computed in constant time per pixel using an integral image
+// (summed-area table).
+//
+// Parameters:
+//   - img: The source image (converted to luminance internally)
+//   - window: Side length of the local window in pixels (0 selects a default)
+//   - offset: How much darker than the local mean a pixel must be to count as dark
+func adaptiveBinarize(img image.Image, window, offset int) (*gozxing.BitMatrix, error) {
+	source := gozxing.NewLuminanceSourceFromImage(img)
+	width := source.GetWidth()
+	height := source.GetHeight()
+	luminances := source.GetMatrix()
+
+	if window <= 0 {
+		window = min(width, height) / defaultThresholdWindowDivisor
+		window = max(window, minThresholdWindow)
+	}
+	half := window / 2
+
+	// integral[y][x] holds the sum of all luminances above and to the left of (x, y)
+	stride := width + 1
+	integral := make([]int64, stride*(height+1))
+	for y := 0; y < height; y++ {
+		rowSum := int64(0)
+		for x := 0; x < width; x++ {
+			rowSum += int64(luminances[y*width+x])
+			integral[(y+1)*stride+x+1] = integral[y*stride+x+1] + rowSum
+		}
+	}
+
+	matrix, err := gozxing.NewBitMatrix(width, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bit matrix: %w", err)
+	}
+
+	for y := 0; y < height; y++ {
+		top := max(y-half, 0)
+		bottom := min(y+half+1, height)
+		for x := 0; x < width; x++ {
+			left := max(x-half, 0)
+			right := min(x+half+1, width)
+
+			sum := integral[bottom*stride+right] - integral[top*stride+right] -
+				integral[bottom*stride+left] + integral[top*stride+left]
+			count := int64((bottom - top) * (right - left))
+
+			if int64(luminances[y*width+x])*count < sum-int64(offset)*count {
+				matrix.Set(x, y)
+			}
+		}
+	}
+
+	return matrix, nil
+}
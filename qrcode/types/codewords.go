@@ -0,0 +1,48 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/makiuchi-d/gozxing/qrcode/decoder"
+)
+
+// CodewordCounts returns the number of codewords in a QR version at an error correction level
+//
+// The counts are derived from the Reed-Solomon block structure: every block
+// contributes its data codewords and the level's EC codewords per block. The total
+// does not depend on the level; the split between data and EC does.
+//
+// Parameters:
+//   - version: QR version 1-40
+//   - ecLevel: Error correction level "L", "M", "Q" or "H"
+//
+// Returns:
+//   - total, data, ec: Total, data and error correction codewords, total = data + ec
+//   - err: Error if the version or level is invalid
+//
+// Example:
+//
+//	total, data, ec, _ := types.CodewordCounts(1, "L") // 26, 19, 7
+func CodewordCounts(version int, ecLevel string) (total, data, ec int, err error) {
+	v, err := decoder.Version_GetVersionForNumber(version)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid version %d: %w", version, err)
+	}
+	level, err := decoder.ErrorCorrectionLevel_ValueOf(ecLevel)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid error correction level %q", ecLevel)
+	}
+
+	total, data, ec = codewordCounts(v, level)
+	return total, data, ec, nil
+}
+
+// codewordCounts sums the codewords over the blocks of a version and level
+func codewordCounts(version *decoder.Version, ecLevel decoder.ErrorCorrectionLevel) (total, data, ec int) {
+	ecBlocks := version.GetECBlocksForLevel(ecLevel)
+	for _, block := range ecBlocks.GetECBlocks() {
+		data += block.GetCount() * block.GetDataCodewords()
+		ec += block.GetCount() * ecBlocks.GetECCodewordsPerBlock()
+	}
+	return data + ec, data, ec
+}
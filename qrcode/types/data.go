@@ -15,4 +15,14 @@ type QRCodeData struct {
 	ECLevel       decoder.ErrorCorrectionLevel
 	DataMask      byte
 	BitMatrix     *gozxing.BitMatrix
+
+	// MaskedMatrix and UnmaskedMatrix are copies of the sampled module grid
+	// before and after removing the data mask. They are only set when
+	// QRExtractor.KeepMatrices is enabled.
+	MaskedMatrix   *gozxing.BitMatrix
+	UnmaskedMatrix *gozxing.BitMatrix
+
+	// Diagnostics collects notes about how the code was read, such as
+	// fallbacks the extractor had to use
+	Diagnostics []string
 }
@@ -1,19 +1,34 @@
 package types
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"image"
 	"image/jpeg"
 	"image/png"
+	"io"
 	"os"
 	"path/filepath"
 
 	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/common"
 	"github.com/makiuchi-d/gozxing/qrcode"
 	"github.com/makiuchi-d/gozxing/qrcode/decoder"
 	"github.com/makiuchi-d/gozxing/qrcode/detector"
 )
 
+// ErrImageTooLarge is returned when an image has more pixels than QRExtractor.MaxImagePixels
+var ErrImageTooLarge = errors.New("image too large")
+
+// ErrModuleCountMismatch is returned when the number of data modules found in the
+// grid differs from the number the specification gives for the version, a sign
+// that function modules were misidentified
+var ErrModuleCountMismatch = errors.New("data module count does not match version")
+
+// ErrInvalidDimension is returned when a module grid is not 17+4v modules wide for a version v in 1..40
+var ErrInvalidDimension = errors.New("invalid QR code dimension")
+
 // NewQRExtractor creates a new QR code extractor
 func NewQRExtractor() *QRExtractor {
 	return &QRExtractor{
@@ -24,16 +39,140 @@ func NewQRExtractor() *QRExtractor {
 // QRExtractor handles the extraction of raw QR code data
 type QRExtractor struct {
 	reader gozxing.Reader
+
+	// Binarizer converts images to black/white matrices before detection. When
+	// nil, AdaptiveThreshold selects the binarizer, falling back to gozxing's
+	// built-in one.
+	Binarizer Binarizer
+
+	// AdaptiveThreshold binarizes images with a local mean threshold instead of
+	// gozxing's built-in binarizer. This helps with antialiased, unevenly lit or
+	// JPEG-compressed codes where a single threshold cannot separate the modules.
+	AdaptiveThreshold bool
+
+	// ThresholdWindow is the side length in pixels of the local window used by
+	// adaptive thresholding. Zero selects 1/8 of the smaller image side.
+	ThresholdWindow int
+
+	// ThresholdOffset is how much darker (0-255) than its local mean a pixel must
+	// be to count as a dark module when adaptive thresholding is enabled.
+	ThresholdOffset int
+
+	// MaxImagePixels limits the width x height of images the extractor will decode.
+	// The limit is checked against the image header before the pixel data is
+	// decoded, so a small file declaring huge dimensions cannot exhaust memory.
+	// Zero means no limit.
+	MaxImagePixels int
+
+	// Inverted reads the code as light-on-dark (white modules on a black background)
+	Inverted bool
+
+	// TryInverted retries detection on the inverted image when the normal
+	// (dark-on-light) attempt fails. QRCodeData.Diagnostics notes when the
+	// inverted attempt was the one that succeeded.
+	TryInverted bool
+
+	// PureBarcode treats the whole image as an upright, cropped QR code (with at
+	// most a light quiet zone around it) and samples its modules directly instead
+	// of locating finder patterns, like gozxing's DecodeHintType_PURE_BARCODE.
+	// This is faster and also reads codes whose finder patterns touch the image
+	// edge, but fails on rotated, skewed or cluttered images.
+	PureBarcode bool
+
+	// KeepMatrices stores copies of the module grid before and after unmasking
+	// on QRCodeData (MaskedMatrix, UnmaskedMatrix), e.g. to show the effect of
+	// the data mask when teaching
+	KeepMatrices bool
+
+	// headerOnly stops extraction after the format and version information (see Classify)
+	headerOnly bool
 }
 
 // ExtractFromImage loads an image file and extracts QR code data
 func (qe *QRExtractor) ExtractFromImage(imagePath string) (*QRCodeData, error) {
+	if qe.MaxImagePixels > 0 {
+		if err := checkImageFile(imagePath, qe.MaxImagePixels); err != nil {
+			return nil, err
+		}
+	}
+
 	// Load image
 	img, err := loadImage(imagePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load image: %w", err)
 	}
 
+	return qe.ExtractFromGoImage(img)
+}
+
+// Classify reads only the version, error correction level and mask pattern of a QR code image
+//
+// This is for scanners that sort or filter codes without needing their content.
+// The image is loaded, binarized and the code detected exactly as in
+// ExtractFromImage, honouring all options, but extraction stops once the format
+// and version information are read: the data mask is not removed and no codewords
+// are read, let alone corrected. Damage to the data region therefore does not
+// affect the classification.
+//
+// Example:
+//
+//	version, ecLevel, mask, err := types.NewQRExtractor().Classify("qr_code.png")
+//	fmt.Printf("version %d-%s, mask %d\n", version, ecLevel, mask)
+func (qe *QRExtractor) Classify(path string) (version int, ecLevel string, mask byte, err error) {
+	classifier := *qe
+	classifier.headerOnly = true
+
+	qrData, err := classifier.ExtractFromImage(path)
+	if err != nil {
+		return 0, "", 0, err
+	}
+	return qrData.Version.GetVersionNumber(), qrData.ECLevel.String(), qrData.DataMask, nil
+}
+
+// ExtractFromReader decodes an image (PNG or JPEG) from a reader and extracts QR code data
+func (qe *QRExtractor) ExtractFromReader(r io.Reader) (*QRCodeData, error) {
+	if qe.MaxImagePixels > 0 {
+		// Keep the header bytes consumed by the check so the full decode sees them again
+		var header bytes.Buffer
+		if err := checkImageHeader(io.TeeReader(r, &header), qe.MaxImagePixels); err != nil {
+			return nil, err
+		}
+		r = io.MultiReader(&header, r)
+	}
+
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	return qe.ExtractFromGoImage(img)
+}
+
+// ExtractFromGoImage extracts QR code data from a decoded image
+//
+// The image is binarized with Binarizer when set, with a local mean threshold
+// when AdaptiveThreshold is set, and with gozxing's default binarizer otherwise.
+func (qe *QRExtractor) ExtractFromGoImage(img image.Image) (*QRCodeData, error) {
+	if qe.MaxImagePixels > 0 {
+		bounds := img.Bounds()
+		if err := checkPixelCount(bounds.Dx(), bounds.Dy(), qe.MaxImagePixels); err != nil {
+			return nil, err
+		}
+	}
+
+	binarizer := qe.Binarizer
+	if binarizer == nil && qe.AdaptiveThreshold {
+		binarizer = AdaptiveBinarizer{Window: qe.ThresholdWindow, Offset: qe.ThresholdOffset}
+	}
+
+	if binarizer != nil {
+		matrix, err := binarizer.Binarize(img)
+		if err != nil {
+			return nil, fmt.Errorf("failed to binarize image: %w", err)
+		}
+		return qe.extractFromBlackMatrix(matrix)
+	}
+
 	// Convert to gozxing format
 	bmp, err := gozxing.NewBinaryBitmapFromImage(img)
 	if err != nil {
@@ -50,17 +189,112 @@ func (qe *QRExtractor) ExtractFromBitmap(bmp *gozxing.BinaryBitmap) (*QRCodeData
 	if err != nil {
 		return nil, err
 	}
-	detect := detector.NewDetector(matrix)
-	detectorResult, err := detect.Detect(nil)
+
+	return qe.extractFromBlackMatrix(matrix)
+}
+
+// ExtractFromDetectorResult extracts QR code data from the result of gozxing's detector
+//
+// Callers that already ran detection, e.g. with their own hints or on a tuned
+// binarization, can pass the result here instead of having ExtractFromBitmap
+// detect the code again. The detector's sampled grid (GetBits) is read directly,
+// so PureBarcode, Inverted and TryInverted have no effect.
+//
+// gozxing's qrcode/detector package returns a *common.DetectorResult.
+func (qe *QRExtractor) ExtractFromDetectorResult(result *common.DetectorResult) (*QRCodeData, error) {
+	if result == nil || result.GetBits() == nil {
+		return nil, fmt.Errorf("detector result has no sampled bits")
+	}
+
+	qrData, err := qe.extractRawData(result.GetBits())
 	if err != nil {
-		return nil, fmt.Errorf("failed to detect QR code: %w", err)
+		return nil, fmt.Errorf("failed to extract raw data: %w", err)
+	}
+	return qrData, nil
+}
+
+// extractFromBlackMatrix extracts QR code data from a binarized image, honouring
+// the Inverted and TryInverted options
+//
+// Inverting the binarized matrix is equivalent to inverting the grayscale image
+// before thresholding, and is how ZXing itself handles light-on-dark codes.
+func (qe *QRExtractor) extractFromBlackMatrix(matrix *gozxing.BitMatrix) (*QRCodeData, error) {
+	if qe.Inverted {
+		inverted, err := invertMatrix(matrix)
+		if err != nil {
+			return nil, err
+		}
+		return qe.detectAndExtract(inverted)
+	}
+
+	qrData, err := qe.detectAndExtract(matrix)
+	if err == nil || !qe.TryInverted {
+		return qrData, err
 	}
 
-	// Extract the bit matrix (this is the sampled QR code grid)
-	bitMatrix := detectorResult.GetBits()
+	inverted, invertErr := invertMatrix(matrix)
+	if invertErr != nil {
+		return nil, invertErr
+	}
+	qrData, invertedErr := qe.detectAndExtract(inverted)
+	if invertedErr != nil {
+		return nil, fmt.Errorf("%w (inverted: %v)", err, invertedErr)
+	}
+
+	qrData.Diagnostics = append(qrData.Diagnostics,
+		fmt.Sprintf("read as inverted (light-on-dark) code after normal detection failed: %v", err))
+	return qrData, nil
+}
+
+// invertMatrix returns a copy of a binarized image with dark and light swapped
+//
+// The black matrix of a BinaryBitmap is cached, so it is copied rather than flipped in place.
+func invertMatrix(matrix *gozxing.BitMatrix) (*gozxing.BitMatrix, error) {
+	inverted, err := copyMatrix(matrix)
+	if err != nil {
+		return nil, err
+	}
+	inverted.FlipAll()
+	return inverted, nil
+}
+
+// copyMatrix returns an independent copy of a bit matrix
+func copyMatrix(matrix *gozxing.BitMatrix) (*gozxing.BitMatrix, error) {
+	clone, err := gozxing.NewBitMatrix(matrix.GetWidth(), matrix.GetHeight())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bit matrix: %w", err)
+	}
+	if err := clone.Xor(matrix); err != nil {
+		return nil, fmt.Errorf("failed to copy bit matrix: %w", err)
+	}
+	return clone, nil
+}
+
+// detectAndExtract detects the QR code in a binarized image and extracts its data
+func (qe *QRExtractor) detectAndExtract(matrix *gozxing.BitMatrix) (*QRCodeData, error) {
+	var bitMatrix *gozxing.BitMatrix
+	var resample gridResampler
+	if qe.PureBarcode {
+		pureBits, err := extractPureBits(matrix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample pure barcode: %w", err)
+		}
+		bitMatrix = pureBits
+	} else {
+		detect := detector.NewDetector(matrix)
+		detectorResult, err := detect.Detect(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect QR code: %w", err)
+		}
+
+		// Extract the bit matrix (this is the sampled QR code grid), and keep what
+		// is needed to sample it again should its dimension turn out to be wrong
+		bitMatrix = detectorResult.GetBits()
+		resample = newGridResampler(matrix, detectorResult.GetPoints())
+	}
 
 	// Create a custom decoder to extract raw data
-	qrData, err := qe.extractRawData(bitMatrix)
+	qrData, err := qe.extractGrid(bitMatrix, resample)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract raw data: %w", err)
 	}
@@ -70,6 +304,23 @@ func (qe *QRExtractor) ExtractFromBitmap(bmp *gozxing.BinaryBitmap) (*QRCodeData
 
 // extractRawData extracts the raw codewords from the QR code bit matrix
 func (qe *QRExtractor) extractRawData(bitMatrix *gozxing.BitMatrix) (*QRCodeData, error) {
+	return qe.extractGrid(bitMatrix, nil)
+}
+
+// extractGrid extracts the raw codewords from a sampled module grid
+//
+// If the version information of a version 7+ code disagrees with the grid's
+// dimension and resample is not nil, the grid is sampled again at the dimension
+// of the version it really has (see resampleForVersion) and extraction restarts
+// on the new grid. Without a resampler (pure barcodes, grids from
+// ExtractFromDetectorResult) or a consistent version, the grid is read as it is
+// with the version from reconcileVersion.
+func (qe *QRExtractor) extractGrid(bitMatrix *gozxing.BitMatrix, resample gridResampler) (*QRCodeData, error) {
+	// Reject grids that cannot be a QR code before interpreting any of their modules
+	if err := validateDimension(bitMatrix.GetWidth(), bitMatrix.GetHeight()); err != nil {
+		return nil, err
+	}
+
 	// Read format information (contains error correction level and mask pattern)
 	formatInfo, err := qe.readFormatInformation(bitMatrix)
 	if err != nil {
@@ -82,10 +333,58 @@ func (qe *QRExtractor) extractRawData(bitMatrix *gozxing.BitMatrix) (*QRCodeData
 		return nil, fmt.Errorf("failed to determine version: %w", err)
 	}
 
+	// Versions 7 and up also encode their number in BCH-protected version
+	// information, which survives a dimension that is slightly off
+	var diagnostics []string
+	if version.GetVersionNumber() >= 7 {
+		if resample != nil {
+			if grid, notes := qe.resampleForVersion(bitMatrix, version, resample); grid != nil {
+				qrData, err := qe.extractGrid(grid, nil)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read the resampled grid: %w", err)
+				}
+				qrData.Diagnostics = append(notes, qrData.Diagnostics...)
+				return qrData, nil
+			}
+		}
+		version, diagnostics = qe.reconcileVersion(bitMatrix, version)
+	}
+
+	// The dark module is never read as data, but a light one hints at a damaged
+	// or misread bottom-left corner. It is not masked, so check it before unmasking.
+	if darkRow, darkCol := darkModulePosition(version); !bitMatrix.Get(darkCol, darkRow) {
+		diagnostics = append(diagnostics,
+			fmt.Sprintf("dark module at row %d, column %d is light", darkRow, darkCol))
+	}
+
+	if qe.headerOnly {
+		return &QRCodeData{
+			Version:     version,
+			FormatInfo:  formatInfo,
+			ECLevel:     formatInfo.GetErrorCorrectionLevel(),
+			DataMask:    formatInfo.GetDataMask(),
+			BitMatrix:   bitMatrix,
+			Diagnostics: diagnostics,
+		}, nil
+	}
+
+	var maskedMatrix, unmaskedMatrix *gozxing.BitMatrix
+	if qe.KeepMatrices {
+		if maskedMatrix, err = copyMatrix(bitMatrix); err != nil {
+			return nil, err
+		}
+	}
+
 	// Remove the data mask
 	dataMask := decoder.DataMaskValues[formatInfo.GetDataMask()]
 	dataMask.UnmaskBitMatrix(bitMatrix, bitMatrix.GetHeight())
 
+	if qe.KeepMatrices {
+		if unmaskedMatrix, err = copyMatrix(bitMatrix); err != nil {
+			return nil, err
+		}
+	}
+
 	// Read the raw codewords
 	rawCodewords, err := qe.readCodewords(bitMatrix, version, formatInfo.GetErrorCorrectionLevel())
 	if err != nil {
@@ -93,7 +392,10 @@ func (qe *QRExtractor) extractRawData(bitMatrix *gozxing.BitMatrix) (*QRCodeData
 	}
 
 	// Split into data and error correction codewords
-	dataCodewords, ecCodewords := qe.splitCodewords(rawCodewords, version, formatInfo.GetErrorCorrectionLevel())
+	dataCodewords, ecCodewords, err := qe.splitCodewords(rawCodewords, version, formatInfo.GetErrorCorrectionLevel())
+	if err != nil {
+		return nil, err
+	}
 
 	return &QRCodeData{
 		Version:       version,
@@ -104,9 +406,136 @@ func (qe *QRExtractor) extractRawData(bitMatrix *gozxing.BitMatrix) (*QRCodeData
 		ECLevel:       formatInfo.GetErrorCorrectionLevel(),
 		DataMask:      formatInfo.GetDataMask(),
 		BitMatrix:     bitMatrix,
+
+		MaskedMatrix:   maskedMatrix,
+		UnmaskedMatrix: unmaskedMatrix,
+
+		Diagnostics: diagnostics,
 	}, nil
 }
 
+// reconcileVersion checks the dimension-derived version against the version information
+//
+// The version information blocks (next to the top-right and bottom-left finder
+// patterns) are protected by a BCH(18,6) code that corrects up to 3 bit errors,
+// whereas a single module too many or too few in the sampled grid changes the
+// dimension-derived version. When the version information decodes and disagrees
+// with the dimension, it wins, as long as that version fits in the grid; the
+// discrepancy is reported as a diagnostic either way.
+//
+// The grid itself is not changed, see resampleForVersion for grids that can be
+// sampled again.
+func (qe *QRExtractor) reconcileVersion(bitMatrix *gozxing.BitMatrix, dimensionVersion *decoder.Version) (*decoder.Version, []string) {
+	infoVersion, err := qe.readVersionInformation(bitMatrix)
+	if err != nil || infoVersion.GetVersionNumber() == dimensionVersion.GetVersionNumber() {
+		return dimensionVersion, nil
+	}
+
+	dimension := bitMatrix.GetHeight()
+	if infoVersion.GetDimensionForVersion() > dimension {
+		return dimensionVersion, []string{fmt.Sprintf(
+			"version information says version %d, which does not fit the %dx%d grid; using version %d from the dimension",
+			infoVersion.GetVersionNumber(), dimension, dimension, dimensionVersion.GetVersionNumber())}
+	}
+
+	return infoVersion, []string{fmt.Sprintf(
+		"dimension %d implies version %d but version information says %d; using version information",
+		dimension, dimensionVersion.GetVersionNumber(), infoVersion.GetVersionNumber())}
+}
+
+// resampleForVersion rebuilds a grid whose dimension disagrees with its version information
+//
+// On a grid sampled at the wrong dimension every module away from the finder
+// patterns is read at the wrong place, the version information blocks included,
+// so what they decode to cannot be trusted either. Instead the grid is sampled
+// again for the version they claim and for the versions next to the dimension's,
+// and the first grid whose own version information matches its dimension is
+// returned, along with diagnostics. It returns nil if the grid's version
+// information already agrees with its dimension, or no candidate is consistent.
+func (qe *QRExtractor) resampleForVersion(bitMatrix *gozxing.BitMatrix, dimensionVersion *decoder.Version,
+	resample gridResampler) (*gozxing.BitMatrix, []string) {
+	infoVersion, err := qe.readVersionInformation(bitMatrix)
+	if err == nil && infoVersion.GetVersionNumber() == dimensionVersion.GetVersionNumber() {
+		return nil, nil
+	}
+
+	estimate := dimensionVersion.GetVersionNumber()
+	candidates := []int{estimate - 1, estimate + 1}
+	if err == nil {
+		candidates = append([]int{infoVersion.GetVersionNumber()}, candidates...)
+	}
+
+	tried := map[int]bool{estimate: true}
+	for _, candidate := range candidates {
+		if candidate < 7 || candidate > 40 || tried[candidate] {
+			continue
+		}
+		tried[candidate] = true
+
+		dimension := 17 + 4*candidate
+		grid, err := resample(dimension)
+		if err != nil {
+			continue
+		}
+		if version, err := qe.readVersionInformation(grid); err != nil || version.GetVersionNumber() != candidate {
+			continue
+		}
+
+		return grid, []string{fmt.Sprintf(
+			"dimension %d implies version %d but version information says %d; resampled the module grid at %dx%d",
+			bitMatrix.GetHeight(), estimate, candidate, dimension, dimension)}
+	}
+	return nil, nil
+}
+
+// readVersionInformation reads the 18-bit version information (versions 7 and up)
+//
+// The top-right block is tried first and the bottom-left copy second, like the
+// format information. Unlike gozxing's parser, the decoded version is not
+// required to match the dimension, so that the two can be compared.
+func (qe *QRExtractor) readVersionInformation(bitMatrix *gozxing.BitMatrix) (*decoder.Version, error) {
+	dimension := bitMatrix.GetHeight()
+
+	// Top-right block: 3 wide by 6 tall
+	versionBits := 0
+	for row := 5; row >= 0; row-- {
+		for col := dimension - 9; col >= dimension-11; col-- {
+			versionBits = qe.copyBit(bitMatrix, col, row, versionBits)
+		}
+	}
+	if version, err := decoder.Version_decodeVersionInformation(versionBits); err == nil && version != nil {
+		return version, nil
+	}
+
+	// Bottom-left block: 6 wide by 3 tall
+	versionBits = 0
+	for col := 5; col >= 0; col-- {
+		for row := dimension - 9; row >= dimension-11; row-- {
+			versionBits = qe.copyBit(bitMatrix, col, row, versionBits)
+		}
+	}
+	version, err := decoder.Version_decodeVersionInformation(versionBits)
+	if err != nil || version == nil {
+		return nil, fmt.Errorf("failed to read version information")
+	}
+	return version, nil
+}
+
+// validateDimension checks that a width x height module grid is square and
+// 17+4v modules wide for some version 1 <= v <= 40
+func validateDimension(width, height int) error {
+	if width != height {
+		return fmt.Errorf("%w: %dx%d is not square", ErrInvalidDimension, width, height)
+	}
+	if (height-17)%4 != 0 {
+		return fmt.Errorf("%w: %d is not 17+4v for any version v", ErrInvalidDimension, height)
+	}
+	if v := (height - 17) / 4; v < 1 || v > 40 {
+		return fmt.Errorf("%w: %d implies version %d, outside 1-40", ErrInvalidDimension, height, v)
+	}
+	return nil
+}
+
 // readFormatInformation reads the format information from the QR code
 func (qe *QRExtractor) readFormatInformation(bitMatrix *gozxing.BitMatrix) (*decoder.FormatInformation, error) {
 	formatInfo1 := qe.readFormatInformationBits1(bitMatrix)
@@ -169,16 +598,28 @@ func (qe *QRExtractor) copyBit(bitMatrix *gozxing.BitMatrix, i, j, result int) i
 	return (result << 1) | bit
 }
 
-// readCodewords reads all codewords from the QR code matrix
-func (qe *QRExtractor) readCodewords(bitMatrix *gozxing.BitMatrix, version *decoder.Version, ecLevel decoder.ErrorCorrectionLevel) ([]byte, error) {
-	// Calculate total number of codewords
-	totalCodewords := version.GetTotalCodewords()
-	codewords := make([]byte, totalCodewords)
-	codewordIndex := 0
-	currentByte := 0
-	bitsRead := 0
+// ModulePosition identifies a module in the QR code grid
+type ModulePosition struct {
+	Row int
+	Col int
+}
 
-	dimension := bitMatrix.GetHeight()
+// DataModulePositions returns the positions of all data modules in reading order
+//
+// Codeword bits are placed in two-module-wide columns, starting at the bottom-right
+// corner and zig-zagging up and down towards the left, skipping the vertical timing
+// pattern and every function module. The first 8 positions hold the most significant
+// to least significant bit of the first codeword, and so on. Positions beyond
+// 8 * totalCodewords are remainder bits.
+func DataModulePositions(version *decoder.Version) []ModulePosition {
+	return dataModulePositions(version, isFunctionModule)
+}
+
+// dataModulePositions walks the zig-zag placement path, keeping the modules that
+// isFunction does not claim
+func dataModulePositions(version *decoder.Version, isFunction func(row, col int, version *decoder.Version) bool) []ModulePosition {
+	dimension := version.GetDimensionForVersion()
+	positions := make([]ModulePosition, 0, dimension*dimension)
 
 	// Read in the zig-zag pattern starting from bottom-right
 	readingUp := true
@@ -197,39 +638,88 @@ func (qe *QRExtractor) readCodewords(bitMatrix *gozxing.BitMatrix, version *deco
 
 			for colOffset := 0; colOffset < 2; colOffset++ {
 				currentCol := col - colOffset
-				if !qe.isFunctionModule(bitMatrix, row, currentCol, version) {
-					bitsRead++
-					currentByte <<= 1
-					if bitMatrix.Get(currentCol, row) {
-						currentByte |= 1
-					}
-
-					if bitsRead == 8 {
-						codewords[codewordIndex] = byte(currentByte)
-						codewordIndex++
-						bitsRead = 0
-						currentByte = 0
-
-						if codewordIndex >= totalCodewords {
-							return codewords, nil
-						}
-					}
+				if !isFunction(row, currentCol, version) {
+					positions = append(positions, ModulePosition{Row: row, Col: currentCol})
 				}
 			}
 		}
 		readingUp = !readingUp
 	}
 
-	if codewordIndex != totalCodewords {
-		return nil, fmt.Errorf("read %d codewords but expected %d", codewordIndex, totalCodewords)
+	return positions
+}
+
+// readCodewords reads all codewords from the QR code matrix
+func (qe *QRExtractor) readCodewords(bitMatrix *gozxing.BitMatrix, version *decoder.Version, ecLevel decoder.ErrorCorrectionLevel) ([]byte, error) {
+	return qe.readCodewordsAt(bitMatrix, version, DataModulePositions(version))
+}
+
+// readCodewordsAt assembles the codewords from the modules at positions, in order,
+// after checking that there are exactly as many positions as the version has data modules
+func (qe *QRExtractor) readCodewordsAt(bitMatrix *gozxing.BitMatrix, version *decoder.Version, positions []ModulePosition) ([]byte, error) {
+	if err := checkModuleCount(version, len(positions)); err != nil {
+		return nil, err
+	}
+
+	// Calculate total number of codewords
+	totalCodewords := version.GetTotalCodewords()
+	codewords := make([]byte, totalCodewords)
+	codewordIndex := 0
+	currentByte := 0
+	bitsRead := 0
+
+	for _, pos := range positions {
+		bitsRead++
+		currentByte <<= 1
+		if bitMatrix.Get(pos.Col, pos.Row) {
+			currentByte |= 1
+		}
+
+		if bitsRead == 8 {
+			codewords[codewordIndex] = byte(currentByte)
+			codewordIndex++
+			bitsRead = 0
+			currentByte = 0
+
+			// The remaining modules are remainder bits
+			if codewordIndex >= totalCodewords {
+				return codewords, nil
+			}
+		}
 	}
 
 	return codewords, nil
 }
 
+// remainderBits returns the number of data modules left over after the last full
+// codeword, which are always zero (ISO/IEC 18004 Table 1)
+func remainderBits(versionNumber int) int {
+	switch {
+	case versionNumber >= 2 && versionNumber <= 6:
+		return 7
+	case versionNumber >= 14 && versionNumber <= 20, versionNumber >= 28 && versionNumber <= 34:
+		return 3
+	case versionNumber >= 21 && versionNumber <= 27:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// checkModuleCount compares a data module count with the specification's
+// 8 * totalCodewords + remainder bits for the version
+func checkModuleCount(version *decoder.Version, numModules int) error {
+	expected := version.GetTotalCodewords()*8 + remainderBits(version.GetVersionNumber())
+	if numModules != expected {
+		return fmt.Errorf("%w: found %d data modules, version %d has %d",
+			ErrModuleCountMismatch, numModules, version.GetVersionNumber(), expected)
+	}
+	return nil
+}
+
 // isFunctionModule checks if a module is a function pattern (finder, timing, etc.)
-func (qe *QRExtractor) isFunctionModule(bitMatrix *gozxing.BitMatrix, row, col int, version *decoder.Version) bool {
-	dimension := bitMatrix.GetHeight()
+func isFunctionModule(row, col int, version *decoder.Version) bool {
+	dimension := version.GetDimensionForVersion()
 
 	// Finder patterns (top-left, top-right, bottom-left)
 	if (row <= 8 && col <= 8) || // Top-left
@@ -244,11 +734,27 @@ func (qe *QRExtractor) isFunctionModule(bitMatrix *gozxing.BitMatrix, row, col i
 		return true
 	}
 
-	// Dark module
-	if row == ((4*version.GetVersionNumber())+9) && col == 8 {
+	// Dark module (also inside the bottom-left region above, so it is excluded
+	// whatever its value)
+	if darkRow, darkCol := darkModulePosition(version); row == darkRow && col == darkCol {
 		return true
 	}
 
+	// Alignment patterns: 5x5 squares around every pair of centre coordinates,
+	// except the three that would overlap the finder patterns
+	centers := version.GetAlignmentPatternCenters()
+	last := len(centers) - 1
+	for i, centerRow := range centers {
+		for j, centerCol := range centers {
+			if (i == 0 && (j == 0 || j == last)) || (i == last && j == 0) {
+				continue
+			}
+			if row >= centerRow-2 && row <= centerRow+2 && col >= centerCol-2 && col <= centerCol+2 {
+				return true
+			}
+		}
+	}
+
 	// Version information (for versions 7 and above)
 	if version.GetVersionNumber() >= 7 {
 		if (row >= dimension-11 && row < dimension-8 && col >= 0 && col <= 5) ||
@@ -260,20 +766,59 @@ func (qe *QRExtractor) isFunctionModule(bitMatrix *gozxing.BitMatrix, row, col i
 	return false
 }
 
+// darkModulePosition returns the row and column of the dark module, which is always
+// dark in a valid code and sits just above the bottom-left format information
+func darkModulePosition(version *decoder.Version) (row, col int) {
+	return 4*version.GetVersionNumber() + 9, 8
+}
+
 // splitCodewords splits raw codewords into data and error correction portions
-func (qe *QRExtractor) splitCodewords(rawCodewords []byte, version *decoder.Version, ecLevel decoder.ErrorCorrectionLevel) ([]byte, []byte) {
-	ecBlocks := version.GetECBlocksForLevel(ecLevel)
-	totalDataCodewords := version.GetTotalCodewords() - ecBlocks.GetTotalECCodewords()
+func (qe *QRExtractor) splitCodewords(rawCodewords []byte, version *decoder.Version, ecLevel decoder.ErrorCorrectionLevel) ([]byte, []byte, error) {
+	total, totalDataCodewords, totalECCodewords := codewordCounts(version, ecLevel)
+	if len(rawCodewords) != total {
+		return nil, nil, fmt.Errorf("version %d-%s has %d codewords, read %d",
+			version.GetVersionNumber(), ecLevel, total, len(rawCodewords))
+	}
 
 	dataCodewords := make([]byte, totalDataCodewords)
-	ecCodewords := make([]byte, ecBlocks.GetTotalECCodewords())
+	ecCodewords := make([]byte, totalECCodewords)
 
 	// For simplicity, we'll assume interleaving happens later
 	// In practice, QR codes interleave data and EC codewords in a complex pattern
 	copy(dataCodewords, rawCodewords[:totalDataCodewords])
 	copy(ecCodewords, rawCodewords[totalDataCodewords:])
 
-	return dataCodewords, ecCodewords
+	return dataCodewords, ecCodewords, nil
+}
+
+// checkImageFile reads the header of an image file and rejects it if it is too large
+func checkImageFile(path string, maxPixels int) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to load image: %w", err)
+	}
+	defer file.Close()
+
+	return checkImageHeader(file, maxPixels)
+}
+
+// checkImageHeader decodes only the image header (dimensions and format) from r
+// and returns ErrImageTooLarge if the image has more than maxPixels pixels
+func checkImageHeader(r io.Reader, maxPixels int) error {
+	config, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return fmt.Errorf("failed to read image header: %w", err)
+	}
+
+	return checkPixelCount(config.Width, config.Height, maxPixels)
+}
+
+// checkPixelCount returns ErrImageTooLarge if width x height exceeds maxPixels
+func checkPixelCount(width, height, maxPixels int) error {
+	if int64(width)*int64(height) > int64(maxPixels) {
+		return fmt.Errorf("%w: %dx%d exceeds the limit of %d pixels", ErrImageTooLarge, width, height, maxPixels)
+	}
+	return nil
 }
 
 // loadImage loads an image from file
@@ -316,4 +861,11 @@ func (qrData *QRCodeData) PrintQRData() {
 		fmt.Printf("%02x ", b)
 	}
 	fmt.Printf("\n")
+
+	if len(qrData.Diagnostics) > 0 {
+		fmt.Printf("\nDiagnostics:\n")
+		for _, note := range qrData.Diagnostics {
+			fmt.Printf("  - %s\n", note)
+		}
+	}
 }
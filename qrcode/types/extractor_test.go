@@ -1,15 +1,23 @@
 package types
 
 import (
+	"bytes"
+	"fmt"
 	"image"
 	"image/color"
+	"image/jpeg"
 	"image/png"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/makiuchi-d/gozxing"
 	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/makiuchi-d/gozxing/qrcode/decoder"
+	"github.com/makiuchi-d/gozxing/qrcode/detector"
+	"github.com/makiuchi-d/gozxing/qrcode/encoder"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -115,3 +123,652 @@ func createTestQRCode(filename, content string) error {
 
 	return png.Encode(file, img)
 }
+
+func TestQRExtractor_AdaptiveThreshold(t *testing.T) {
+	// Arrange: a code lit from one side and saved as a lossy JPEG
+	matrix, err := qrcode.NewQRCodeWriter().Encode("Adaptive", gozxing.BarcodeFormat_QR_CODE, 300, 300, nil)
+	require.NoError(t, err)
+
+	img := image.NewGray(image.Rect(0, 0, matrix.GetWidth(), matrix.GetHeight()))
+	for y := 0; y < matrix.GetHeight(); y++ {
+		for x := 0; x < matrix.GetWidth(); x++ {
+			// Illumination falls off from right to left
+			light := 0.15 + 0.85*float64(x)/float64(matrix.GetWidth())
+			value := 255.0
+			if matrix.Get(x, y) {
+				value = 40.0
+			}
+			img.Set(x, y, color.Gray{Y: uint8(value * light)})
+		}
+	}
+
+	jpegPath := filepath.Join(t.TempDir(), "shaded.jpg")
+	file, err := os.Create(jpegPath)
+	require.NoError(t, err)
+	require.NoError(t, jpeg.Encode(file, img, &jpeg.Options{Quality: 60}))
+	require.NoError(t, file.Close())
+
+	loaded, err := loadImage(jpegPath)
+	require.NoError(t, err)
+
+	// Act & Assert: a single global threshold cannot separate the modules
+	globalBitmap, err := gozxing.NewBinaryBitmap(gozxing.NewGlobalHistgramBinarizer(gozxing.NewLuminanceSourceFromImage(loaded)))
+	require.NoError(t, err)
+	_, err = NewQRExtractor().ExtractFromBitmap(globalBitmap)
+	assert.Error(t, err)
+
+	// Act & Assert: the local mean threshold follows the lighting
+	extractor := NewQRExtractor()
+	extractor.AdaptiveThreshold = true
+	qrData, err := extractor.ExtractFromImage(jpegPath)
+	require.NoError(t, err)
+
+	cleanBitmap, err := gozxing.NewBinaryBitmapFromImage(bitMatrixToGray(matrix))
+	require.NoError(t, err)
+	cleanData, err := NewQRExtractor().ExtractFromBitmap(cleanBitmap)
+	require.NoError(t, err)
+
+	assert.Equal(t, cleanData.Version.GetVersionNumber(), qrData.Version.GetVersionNumber())
+	assert.Equal(t, cleanData.ECLevel, qrData.ECLevel)
+	assert.Equal(t, cleanData.RawCodewords, qrData.RawCodewords)
+}
+
+// bitMatrixToGray renders a bit matrix as a black-on-white grayscale image
+func bitMatrixToGray(matrix *gozxing.BitMatrix) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, matrix.GetWidth(), matrix.GetHeight()))
+	for y := 0; y < matrix.GetHeight(); y++ {
+		for x := 0; x < matrix.GetWidth(); x++ {
+			if matrix.Get(x, y) {
+				img.Set(x, y, color.Gray{0}) // Black
+			} else {
+				img.Set(x, y, color.Gray{255}) // White
+			}
+		}
+	}
+	return img
+}
+
+func TestQRExtractor_MaxImagePixels(t *testing.T) {
+	// Arrange: a 256x256 PNG
+	testFilePath := filepath.Join(t.TempDir(), "limit.png")
+	require.NoError(t, createTestQRCode(testFilePath, "Size limit"))
+	content, err := os.ReadFile(testFilePath)
+	require.NoError(t, err)
+
+	limited := NewQRExtractor()
+	limited.MaxImagePixels = 100 * 100
+
+	// Act & Assert: every entry point rejects the over-limit image
+	_, err = limited.ExtractFromImage(testFilePath)
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+
+	_, err = limited.ExtractFromReader(bytes.NewReader(content))
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+
+	_, err = limited.ExtractFromGoImage(image.NewGray(image.Rect(0, 0, 256, 256)))
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+
+	// A limit above the image size lets the normal decode through
+	allowed := NewQRExtractor()
+	allowed.MaxImagePixels = 256 * 256
+
+	qrData, err := allowed.ExtractFromImage(testFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, qrData.Version.GetVersionNumber())
+
+	qrData, err = allowed.ExtractFromReader(bytes.NewReader(content))
+	require.NoError(t, err)
+	assert.Equal(t, 1, qrData.Version.GetVersionNumber())
+}
+
+func TestQRExtractor_Inverted(t *testing.T) {
+	// Arrange: light modules on a dark background
+	matrix, err := qrcode.NewQRCodeWriter().Encode("Inverted", gozxing.BarcodeFormat_QR_CODE, 256, 256, nil)
+	require.NoError(t, err)
+	normalImg := bitMatrixToGray(matrix)
+
+	invertedImg := image.NewGray(normalImg.Bounds())
+	for i, v := range normalImg.Pix {
+		invertedImg.Pix[i] = 255 - v
+	}
+
+	expected, err := NewQRExtractor().ExtractFromGoImage(normalImg)
+	require.NoError(t, err)
+
+	// Act & Assert: the default extractor does not see the code
+	_, err = NewQRExtractor().ExtractFromGoImage(invertedImg)
+	assert.Error(t, err)
+
+	// Auto-try falls back to the inverted image and says so
+	tryInverted := NewQRExtractor()
+	tryInverted.TryInverted = true
+	qrData, err := tryInverted.ExtractFromGoImage(invertedImg)
+	require.NoError(t, err)
+	assert.Equal(t, expected.RawCodewords, qrData.RawCodewords)
+	require.Len(t, qrData.Diagnostics, 1)
+	assert.Contains(t, qrData.Diagnostics[0], "inverted")
+
+	// A normal code is read on the first attempt without diagnostics
+	qrData, err = tryInverted.ExtractFromGoImage(normalImg)
+	require.NoError(t, err)
+	assert.Empty(t, qrData.Diagnostics)
+
+	// Forcing inversion reads the light-on-dark code directly
+	inverted := NewQRExtractor()
+	inverted.Inverted = true
+	qrData, err = inverted.ExtractFromGoImage(invertedImg)
+	require.NoError(t, err)
+	assert.Equal(t, expected.RawCodewords, qrData.RawCodewords)
+}
+
+func TestQRExtractor_KeepMatrices(t *testing.T) {
+	// Arrange
+	matrix, err := qrcode.NewQRCodeWriter().Encode("Mask me", gozxing.BarcodeFormat_QR_CODE, 256, 256, nil)
+	require.NoError(t, err)
+	img := bitMatrixToGray(matrix)
+
+	// Act
+	extractor := NewQRExtractor()
+	extractor.KeepMatrices = true
+	qrData, err := extractor.ExtractFromGoImage(img)
+	require.NoError(t, err)
+
+	// Assert: the two matrices differ exactly where the mask pattern flips modules
+	require.NotNil(t, qrData.MaskedMatrix)
+	require.NotNil(t, qrData.UnmaskedMatrix)
+
+	dimension := qrData.BitMatrix.GetHeight()
+	pattern, err := gozxing.NewBitMatrix(dimension, dimension)
+	require.NoError(t, err)
+	decoder.DataMaskValues[qrData.DataMask].UnmaskBitMatrix(pattern, dimension)
+
+	flipped := 0
+	for y := 0; y < dimension; y++ {
+		for x := 0; x < dimension; x++ {
+			differs := qrData.MaskedMatrix.Get(x, y) != qrData.UnmaskedMatrix.Get(x, y)
+			assert.Equal(t, pattern.Get(x, y), differs, "module (%d, %d)", x, y)
+			if differs {
+				flipped++
+			}
+		}
+	}
+	assert.Positive(t, flipped)
+
+	// Without the flag nothing extra is kept
+	qrData, err = NewQRExtractor().ExtractFromGoImage(img)
+	require.NoError(t, err)
+	assert.Nil(t, qrData.MaskedMatrix)
+	assert.Nil(t, qrData.UnmaskedMatrix)
+}
+
+func TestQRExtractor_OtsuBinarizer(t *testing.T) {
+	// Arrange: a washed-out print whose modules differ by only 20 grey levels
+	matrix, err := qrcode.NewQRCodeWriter().Encode("Low contrast", gozxing.BarcodeFormat_QR_CODE, 256, 256, nil)
+	require.NoError(t, err)
+
+	img := image.NewGray(image.Rect(0, 0, matrix.GetWidth(), matrix.GetHeight()))
+	for y := 0; y < matrix.GetHeight(); y++ {
+		for x := 0; x < matrix.GetWidth(); x++ {
+			value := uint8(180)
+			if matrix.Get(x, y) {
+				value = 160
+			}
+			img.Set(x, y, color.Gray{Y: value})
+		}
+	}
+
+	// Act & Assert: the default binarizer treats the low dynamic range as blank
+	_, err = NewQRExtractor().ExtractFromGoImage(img)
+	assert.Error(t, err)
+
+	// Act & Assert: a fixed mid-grey threshold sees everything as light
+	global := NewQRExtractor()
+	global.Binarizer = GlobalThresholdBinarizer{}
+	_, err = global.ExtractFromGoImage(img)
+	assert.Error(t, err)
+
+	// Act & Assert: Otsu's method finds the cutoff between the two grey levels
+	otsu := NewQRExtractor()
+	otsu.Binarizer = OtsuBinarizer{}
+	qrData, err := otsu.ExtractFromGoImage(img)
+	require.NoError(t, err)
+
+	expected, err := NewQRExtractor().ExtractFromGoImage(bitMatrixToGray(matrix))
+	require.NoError(t, err)
+	assert.Equal(t, expected.RawCodewords, qrData.RawCodewords)
+
+	// A global threshold placed between the grey levels works too
+	global.Binarizer = GlobalThresholdBinarizer{Threshold: 170}
+	qrData, err = global.ExtractFromGoImage(img)
+	require.NoError(t, err)
+	assert.Equal(t, expected.RawCodewords, qrData.RawCodewords)
+}
+
+func TestQRExtractor_InvalidDimension(t *testing.T) {
+	extractor := NewQRExtractor()
+
+	tests := []struct {
+		name          string
+		width, height int
+		wantInMessage string
+	}{
+		{name: "not 17+4v", width: 23, height: 23, wantInMessage: "23"},
+		{name: "version 0", width: 17, height: 17, wantInMessage: "17"},
+		{name: "version 41", width: 181, height: 181, wantInMessage: "181"},
+		{name: "not square", width: 21, height: 25, wantInMessage: "21x25"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matrix, err := gozxing.NewBitMatrix(tt.width, tt.height)
+			require.NoError(t, err)
+
+			_, err = extractor.extractRawData(matrix)
+			require.ErrorIs(t, err, ErrInvalidDimension)
+			assert.Contains(t, err.Error(), tt.wantInMessage)
+		})
+	}
+}
+
+func TestQRExtractor_VersionInformationWins(t *testing.T) {
+	// Arrange: the module grid of a version 7 code (45x45)
+	code, writerErr := encoder.Encoder_encode("Version information", decoder.ErrorCorrectionLevel_L,
+		map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_QR_VERSION: 7})
+	require.NoError(t, writerErr)
+	modules := code.GetMatrix()
+	size := modules.GetWidth()
+	require.Equal(t, 45, size)
+
+	original, err := gozxing.NewSquareBitMatrix(size)
+	require.NoError(t, err)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if modules.Get(x, y) == 1 {
+				original.Set(x, y)
+			}
+		}
+	}
+
+	// Tamper with the dimension: pad the grid to 49x49 (version 8), moving both
+	// version information blocks along with the edges so they still read cleanly
+	tampered, err := gozxing.NewSquareBitMatrix(size + 4)
+	require.NoError(t, err)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if original.Get(x, y) {
+				tampered.Set(x, y)
+			}
+		}
+	}
+	// (the blocks land on the original finder patterns, which are not read)
+	copyModule := func(fromX, fromY, toX, toY int) {
+		if original.Get(fromX, fromY) {
+			tampered.Set(toX, toY)
+		} else {
+			tampered.Unset(toX, toY)
+		}
+	}
+	for i := 0; i < 6; i++ {
+		for j := 0; j < 3; j++ {
+			copyModule(size-11+j, i, size+4-11+j, i) // top-right block
+			copyModule(i, size-11+j, i, size+4-11+j) // bottom-left block
+		}
+	}
+
+	extractor := NewQRExtractor()
+	expected, err := extractor.extractRawData(original)
+	require.NoError(t, err)
+
+	// Act
+	qrData, err := extractor.extractRawData(tampered)
+
+	// Assert: the version information wins over the dimension and is reported
+	require.NoError(t, err)
+	assert.Equal(t, 7, qrData.Version.GetVersionNumber())
+	assert.Equal(t, expected.RawCodewords, qrData.RawCodewords)
+	require.Len(t, qrData.Diagnostics, 1)
+	assert.Contains(t, qrData.Diagnostics[0], "implies version 8 but version information says 7")
+
+	// The untampered grid agrees with its version information and needs no note
+	assert.Empty(t, expected.Diagnostics)
+}
+
+func TestQRExtractor_VersionInformationResamples(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		version int
+		offBy   int
+	}{
+		{name: "estimate one version too large", version: 7, offBy: 1},
+		{name: "estimate one version too small", version: 8, offBy: -1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange: an image of the code at 6 pixels per module
+			hints := map[gozxing.EncodeHintType]interface{}{
+				gozxing.EncodeHintType_QR_VERSION: tt.version,
+				gozxing.EncodeHintType_MARGIN:     4,
+			}
+			code, err := qrcode.NewQRCodeWriter().Encode("Resample at the right dimension", gozxing.BarcodeFormat_QR_CODE,
+				(17+4*tt.version+8)*6, (17+4*tt.version+8)*6, hints)
+			require.NoError(t, err)
+
+			extractor := NewQRExtractor()
+			expected, err := extractor.detectAndExtract(code)
+			require.NoError(t, err)
+			require.Equal(t, tt.version, expected.Version.GetVersionNumber())
+			assert.Empty(t, expected.Diagnostics)
+
+			// Sample the grid as if the detector had misjudged the dimension by 4 modules
+			detected, err := detector.NewDetector(code).Detect(nil)
+			require.NoError(t, err)
+			resample := newGridResampler(code, detected.GetPoints())
+			wrongDimension := 17 + 4*(tt.version+tt.offBy)
+			misjudged, err := resample(wrongDimension)
+			require.NoError(t, err)
+
+			// Act
+			qrData, err := extractor.extractGrid(misjudged, resample)
+
+			// Assert: the version information wins and the grid is sampled again for it
+			require.NoError(t, err)
+			assert.Equal(t, tt.version, qrData.Version.GetVersionNumber())
+			assert.Equal(t, 17+4*tt.version, qrData.BitMatrix.GetHeight())
+			assert.Equal(t, expected.RawCodewords, qrData.RawCodewords)
+			require.Len(t, qrData.Diagnostics, 1)
+			assert.Contains(t, qrData.Diagnostics[0], fmt.Sprintf("implies version %d but version information says %d",
+				tt.version+tt.offBy, tt.version))
+			assert.Contains(t, qrData.Diagnostics[0], "resampled the module grid")
+		})
+	}
+}
+
+func TestQRExtractor_PureBarcode(t *testing.T) {
+	// Arrange: an upright code at 8 pixels per module, once intact and once cropped to
+	// its modules with the centre of the top-right finder pattern erased
+	code, err := qrcode.NewQRCodeWriter().Encode("Pure barcode", gozxing.BarcodeFormat_QR_CODE, 0, 0,
+		map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_MARGIN: 0})
+	require.NoError(t, err)
+	dimension := code.GetWidth()
+
+	const scale = 8
+	intact, err := gozxing.NewBitMatrix((dimension+8)*scale, (dimension+8)*scale)
+	require.NoError(t, err)
+	damaged, err := gozxing.NewBitMatrix(dimension*scale, dimension*scale)
+	require.NoError(t, err)
+	for y := 0; y < dimension; y++ {
+		for x := 0; x < dimension; x++ {
+			if !code.Get(x, y) {
+				continue
+			}
+			intact.SetRegion((x+4)*scale, (y+4)*scale, scale, scale)
+
+			finderX := x - (dimension - 7)
+			if finderX >= 2 && finderX <= 4 && y >= 2 && y <= 4 {
+				continue
+			}
+			damaged.SetRegion(x*scale, y*scale, scale, scale)
+		}
+	}
+	intactImg, damagedImg := bitMatrixToGray(intact), bitMatrixToGray(damaged)
+
+	expected, err := NewQRExtractor().ExtractFromGoImage(intactImg)
+	require.NoError(t, err)
+
+	// Act & Assert: the detector needs all three finder patterns
+	_, err = NewQRExtractor().ExtractFromGoImage(damagedImg)
+	assert.Error(t, err)
+
+	// Sampling the image as a pure barcode only relies on the top-left one
+	pure := NewQRExtractor()
+	pure.PureBarcode = true
+	for name, img := range map[string]image.Image{"intact": intactImg, "damaged": damagedImg} {
+		qrData, err := pure.ExtractFromGoImage(img)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected.RawCodewords, qrData.RawCodewords, name)
+	}
+
+	// An image without dark modules is not a pure barcode
+	blank := image.NewGray(image.Rect(0, 0, 50, 50))
+	for i := range blank.Pix {
+		blank.Pix[i] = 255
+	}
+	_, err = pure.ExtractFromGoImage(blank)
+	assert.ErrorIs(t, err, ErrNotPureBarcode)
+}
+
+func TestQRExtractor_FlippedDarkModule(t *testing.T) {
+	// Arrange: the module grid of a version 2 code, once as encoded and once with
+	// the dark module (row 4·2+9 = 17, column 8) flipped to light
+	code, writerErr := encoder.Encoder_encode("Dark module", decoder.ErrorCorrectionLevel_M,
+		map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_QR_VERSION: 2})
+	require.NoError(t, writerErr)
+	modules := code.GetMatrix()
+	size := modules.GetWidth()
+
+	newGrid := func() *gozxing.BitMatrix {
+		grid, err := gozxing.NewSquareBitMatrix(size)
+		require.NoError(t, err)
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				if modules.Get(x, y) == 1 {
+					grid.Set(x, y)
+				}
+			}
+		}
+		return grid
+	}
+	original, flipped := newGrid(), newGrid()
+	require.True(t, original.Get(8, 17), "encoder should set the dark module")
+	flipped.Unset(8, 17)
+
+	extractor := NewQRExtractor()
+	expected, err := extractor.extractRawData(original)
+	require.NoError(t, err)
+	assert.Empty(t, expected.Diagnostics)
+
+	// Act
+	qrData, err := extractor.extractRawData(flipped)
+
+	// Assert: the codewords are unaffected and the light dark module is reported
+	require.NoError(t, err)
+	assert.Equal(t, expected.RawCodewords, qrData.RawCodewords)
+	require.Len(t, qrData.Diagnostics, 1)
+	assert.Contains(t, qrData.Diagnostics[0], "dark module at row 17, column 8 is light")
+}
+
+func TestDataModulePositions_CountMatchesSpec(t *testing.T) {
+	for v := 1; v <= 40; v++ {
+		version, err := decoder.Version_GetVersionForNumber(v)
+		require.NoError(t, err)
+		assert.NoError(t, checkModuleCount(version, len(DataModulePositions(version))), "version %d", v)
+	}
+}
+
+func TestQRExtractor_ModuleCountMismatch(t *testing.T) {
+	// Version 2 has one alignment pattern; a function-module test that forgets it
+	// treats its 25 modules as data
+	version, err := decoder.Version_GetVersionForNumber(2)
+	require.NoError(t, err)
+	withoutAlignment := func(row, col int, version *decoder.Version) bool {
+		if row >= 16 && row <= 20 && col >= 16 && col <= 20 {
+			return false
+		}
+		return isFunctionModule(row, col, version)
+	}
+	positions := dataModulePositions(version, withoutAlignment)
+	require.Len(t, positions, len(DataModulePositions(version))+25)
+
+	grid, err := gozxing.NewSquareBitMatrix(version.GetDimensionForVersion())
+	require.NoError(t, err)
+
+	// Act
+	_, err = NewQRExtractor().readCodewordsAt(grid, version, positions)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrModuleCountMismatch)
+	assert.Contains(t, err.Error(), "found 384 data modules, version 2 has 359")
+
+	// The correct positions read a full set of codewords
+	codewords, err := NewQRExtractor().readCodewordsAt(grid, version, DataModulePositions(version))
+	require.NoError(t, err)
+	assert.Len(t, codewords, version.GetTotalCodewords())
+}
+
+func TestQRCodeData_RenderASCII(t *testing.T) {
+	qrData, err := GenerateDamagedQR("Render me", "M", 0, 1)
+	require.NoError(t, err)
+	dimension := qrData.Version.GetDimensionForVersion()
+
+	for _, annotate := range []bool{false, true} {
+		lines := strings.Split(strings.TrimSuffix(qrData.RenderASCII(annotate), "\n"), "\n")
+		require.Len(t, lines, dimension, "annotate=%v", annotate)
+		for _, line := range lines {
+			assert.Equal(t, dimension, utf8.RuneCountInString(line), "annotate=%v", annotate)
+		}
+
+		// The finder patterns' outer corners are dark
+		solid := '█'
+		if annotate {
+			solid = '▓'
+		}
+		for _, corner := range [][2]int{{0, 0}, {0, dimension - 1}, {dimension - 1, 0}} {
+			assert.Equal(t, solid, []rune(lines[corner[0]])[corner[1]], "annotate=%v corner %v", annotate, corner)
+		}
+	}
+
+	// Without annotation only the two module characters appear
+	plain := qrData.RenderASCII(false)
+	assert.Equal(t, "", strings.Trim(plain, "█ \n"))
+	assert.Contains(t, qrData.RenderASCII(true), "░")
+}
+
+func TestQRExtractor_ExtractFromDetectorResult(t *testing.T) {
+	matrix, err := qrcode.NewQRCodeWriter().Encode("Detected once", gozxing.BarcodeFormat_QR_CODE, 256, 256, nil)
+	require.NoError(t, err)
+	bmp, err := gozxing.NewBinaryBitmapFromImage(bitMatrixToGray(matrix))
+	require.NoError(t, err)
+
+	expected, err := NewQRExtractor().ExtractFromBitmap(bmp)
+	require.NoError(t, err)
+
+	// Run gozxing's detector once and hand over its result
+	blackMatrix, err := bmp.GetBlackMatrix()
+	require.NoError(t, err)
+	detectorResult, err := detector.NewDetector(blackMatrix).Detect(nil)
+	require.NoError(t, err)
+
+	qrData, err := NewQRExtractor().ExtractFromDetectorResult(detectorResult)
+	require.NoError(t, err)
+	assert.Equal(t, expected.RawCodewords, qrData.RawCodewords)
+	assert.Equal(t, expected.Version.GetVersionNumber(), qrData.Version.GetVersionNumber())
+	assert.Equal(t, expected.ECLevel, qrData.ECLevel)
+	assert.Equal(t, expected.DataMask, qrData.DataMask)
+
+	_, err = NewQRExtractor().ExtractFromDetectorResult(nil)
+	assert.Error(t, err)
+}
+
+func TestCodewordCounts(t *testing.T) {
+	tests := []struct {
+		version         int
+		level           string
+		total, data, ec int
+	}{
+		{version: 1, level: "L", total: 26, data: 19, ec: 7},
+		{version: 10, level: "M", total: 346, data: 216, ec: 130},
+		{version: 40, level: "H", total: 3706, data: 1276, ec: 2430},
+	}
+	for _, tt := range tests {
+		total, data, ec, err := CodewordCounts(tt.version, tt.level)
+		require.NoError(t, err)
+		assert.Equal(t, []int{tt.total, tt.data, tt.ec}, []int{total, data, ec},
+			"version %d-%s", tt.version, tt.level)
+	}
+
+	// The block structure always adds up to the version's total
+	for v := 1; v <= 40; v++ {
+		version, err := decoder.Version_GetVersionForNumber(v)
+		require.NoError(t, err)
+		for _, level := range []string{"L", "M", "Q", "H"} {
+			total, data, ec, err := CodewordCounts(v, level)
+			require.NoError(t, err)
+			assert.Equal(t, version.GetTotalCodewords(), total, "version %d-%s", v, level)
+			assert.Equal(t, total, data+ec, "version %d-%s", v, level)
+		}
+	}
+
+	_, _, _, err := CodewordCounts(41, "L")
+	assert.Error(t, err)
+	_, _, _, err = CodewordCounts(1, "X")
+	assert.Error(t, err)
+}
+
+func TestQRExtractor_Classify(t *testing.T) {
+	// A long message needs a large version, where reading the codewords is a
+	// noticeable part of the extraction
+	path := filepath.Join(t.TempDir(), "classify.png")
+	require.NoError(t, createTestQRCode(path, strings.Repeat("Classify without decoding. ", 20)))
+
+	qe := NewQRExtractor()
+	expected, err := qe.ExtractFromImage(path)
+	require.NoError(t, err)
+
+	version, ecLevel, mask, err := qe.Classify(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected.Version.GetVersionNumber(), version)
+	assert.Equal(t, "L", ecLevel) // the gozxing writer's default level
+	assert.Equal(t, expected.ECLevel.String(), ecLevel)
+	assert.Equal(t, expected.DataMask, mask)
+
+	// Classification stops after the header: no codewords are read (timing in
+	// BenchmarkQRExtractor_Classify)
+	keep := NewQRExtractor()
+	keep.KeepMatrices = true
+	kept, err := keep.ExtractFromImage(path)
+	require.NoError(t, err)
+	grid, err := copyMatrix(kept.MaskedMatrix)
+	require.NoError(t, err)
+	header, err := (&QRExtractor{headerOnly: true}).extractRawData(grid)
+	require.NoError(t, err)
+	assert.Empty(t, header.RawCodewords)
+	assert.NotEmpty(t, expected.RawCodewords)
+
+	_, _, _, err = qe.Classify(filepath.Join(t.TempDir(), "missing.png"))
+	assert.Error(t, err)
+}
+
+// BenchmarkQRExtractor_Classify compares the part of extraction after detection,
+// which loading and detection do not affect: classification skips unmasking and
+// reading the codewords
+func BenchmarkQRExtractor_Classify(b *testing.B) {
+	// A long message needs a large version, where reading the codewords is a
+	// noticeable part of the extraction
+	path := filepath.Join(b.TempDir(), "classify.png")
+	require.NoError(b, createTestQRCode(path, strings.Repeat("Classify without decoding. ", 20)))
+
+	keep := NewQRExtractor()
+	keep.KeepMatrices = true
+	kept, err := keep.ExtractFromImage(path)
+	require.NoError(b, err)
+
+	for _, bm := range []struct {
+		name      string
+		extractor *QRExtractor
+	}{
+		{"full", NewQRExtractor()},
+		{"header-only", &QRExtractor{headerOnly: true}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				grid, err := copyMatrix(kept.MaskedMatrix)
+				require.NoError(b, err)
+				b.StartTimer()
+
+				if _, err := bm.extractor.extractRawData(grid); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
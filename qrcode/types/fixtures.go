@@ -0,0 +1,94 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/makiuchi-d/gozxing/qrcode/decoder"
+)
+
+// fixtureScale and fixtureQuietZone are the pixels per module and the quiet zone
+// width in modules of the images GenerateDamagedQR extracts from
+const (
+	fixtureScale     = 4
+	fixtureQuietZone = 4
+)
+
+// GenerateDamagedQR encodes a message, flips a percentage of its data modules and extracts the result
+//
+// This produces test fixtures with a controlled amount of damage, e.g. to check that
+// codes below the error correction capacity always decode and codes above it never
+// decode to a wrong message. Only data modules (see DataModulePositions) are flipped,
+// so the finder patterns, timing patterns and format information stay intact and
+// extraction itself succeeds; whether the codewords can be corrected is up to the
+// decoder. Each flipped module changes one bit of one codeword, so the number of
+// damaged codewords is at most the number of flipped modules.
+//
+// Parameters:
+//   - message: Text to encode, in byte mode as chosen by the gozxing writer
+//   - ecLevel: Error correction level "L", "M", "Q" or "H"
+//   - damagePercent: Percentage of data modules to flip, from 0 to 100
+//   - seed: Seed for choosing the modules, so fixtures are reproducible
+//
+// Returns:
+//   - QR code data extracted from the damaged code
+//   - Error if the message cannot be encoded or the damaged code cannot be extracted
+//
+// Example:
+//
+//	qrData, err := types.GenerateDamagedQR("Hello, World!", "M", 5, 42)
+//	if err != nil {
+//	    return err
+//	}
+//	result, err := dec.Decode(qrData)
+func GenerateDamagedQR(message, ecLevel string, damagePercent float64, seed int64) (*QRCodeData, error) {
+	if damagePercent < 0 || damagePercent > 100 {
+		return nil, fmt.Errorf("damage percentage %v out of range [0, 100]", damagePercent)
+	}
+
+	// One pixel per module and no quiet zone, so that x, y are module coordinates
+	hints := map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_ERROR_CORRECTION: ecLevel,
+		gozxing.EncodeHintType_MARGIN:           0,
+	}
+	code, err := qrcode.NewQRCodeWriter().Encode(message, gozxing.BarcodeFormat_QR_CODE, 0, 0, hints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	dimension := code.GetWidth()
+	version, err := decoder.Version_GetProvisionalVersionForDimension(dimension)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine version: %w", err)
+	}
+
+	positions := DataModulePositions(version)
+	numDamaged := int(math.Round(damagePercent / 100 * float64(len(positions))))
+	rng := rand.New(rand.NewSource(seed))
+	for _, i := range rng.Perm(len(positions))[:numDamaged] {
+		code.Flip(positions[i].Col, positions[i].Row)
+	}
+
+	size := (dimension + 2*fixtureQuietZone) * fixtureScale
+	scaled, err := gozxing.NewBitMatrix(size, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bit matrix: %w", err)
+	}
+	for y := 0; y < dimension; y++ {
+		for x := 0; x < dimension; x++ {
+			if code.Get(x, y) {
+				scaled.SetRegion((x+fixtureQuietZone)*fixtureScale, (y+fixtureQuietZone)*fixtureScale,
+					fixtureScale, fixtureScale)
+			}
+		}
+	}
+
+	qrData, err := NewQRExtractor().extractFromBlackMatrix(scaled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract damaged code: %w", err)
+	}
+	return qrData, nil
+}
@@ -0,0 +1,282 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode/decoder"
+)
+
+// Penalty weights from the QR code specification (ISO/IEC 18004, section 7.8.3)
+const (
+	penaltyN1 = 3  // Rule 1: each run of 5+ same-colored modules, plus 1 per extra module
+	penaltyN2 = 3  // Rule 2: each 2x2 block of same-colored modules
+	penaltyN3 = 40 // Rule 3: each finder-like 1:1:3:1:1 pattern next to 4 light modules
+	penaltyN4 = 10 // Rule 4: each 5% deviation of the dark-module ratio from 50%
+)
+
+// BestMaskByPenalty guesses the data mask of a QR code from its unmasked module grid
+//
+// An encoder tries all eight mask patterns and keeps the one whose masked symbol
+// scores the lowest penalty under the four rules of the specification (long runs,
+// 2x2 blocks, finder-like patterns and dark/light imbalance). Repeating that choice
+// on the unmasked grid recovers the mask an encoder would have picked, which is a
+// useful guess when the format information is too damaged to read. It complements
+// brute-forcing the masks by decode success: it needs no error correction, but it
+// is only a heuristic and encoders are free to choose any mask.
+//
+// Only data modules (see DataModulePositions) are masked. Like an encoder, each
+// candidate is scored with the format information for that mask written in; this
+// needs the error correction level, which is read from the format information in
+// the matrix (tolerating a few bit errors). If it cannot be read, the format
+// modules are scored as they appear in the matrix, which makes the guess less
+// reliable.
+//
+// The function patterns must be intact. Note that QRCodeData.UnmaskedMatrix does
+// not qualify, because removing the mask there flips function modules as well;
+// start from QRCodeData.MaskedMatrix and undo the mask on the data modules only.
+//
+// Parameters:
+//   - matrix: The unmasked module grid, one bit per module (dimension x dimension)
+//
+// Returns:
+//   - The mask pattern (0-7) with the lowest penalty, the lowest index on ties
+//   - Error if the matrix dimension does not correspond to a QR code version
+func BestMaskByPenalty(matrix *gozxing.BitMatrix) (int, error) {
+	if matrix == nil {
+		return 0, fmt.Errorf("nil matrix")
+	}
+
+	if err := validateDimension(matrix.GetWidth(), matrix.GetHeight()); err != nil {
+		return 0, err
+	}
+	dimension := matrix.GetHeight()
+
+	version, err := decoder.Version_GetVersionForNumber((dimension - 17) / 4)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get version: %w", err)
+	}
+
+	grid := make([][]bool, dimension)
+	for row := range grid {
+		grid[row] = make([]bool, dimension)
+		for col := range grid[row] {
+			grid[row][col] = matrix.Get(col, row)
+		}
+	}
+
+	// A nil format information leaves the format modules untouched
+	formatInfo, _ := NewQRExtractor().readFormatInformation(matrix)
+
+	positions := DataModulePositions(version)
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		if formatInfo != nil {
+			writeFormatBits(grid, formatBits(formatInfo.GetErrorCorrectionLevel(), mask))
+		}
+
+		// Masking is an involution, so apply, score and undo in place
+		applyMask(grid, positions, mask)
+		penalty := maskPenalty(grid)
+		applyMask(grid, positions, mask)
+
+		if bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+	}
+
+	return bestMask, nil
+}
+
+// formatBits computes the 15 format information bits for an EC level and mask:
+// the 5 data bits followed by their BCH(15,5) check bits, XORed with 0x5412
+func formatBits(ecLevel decoder.ErrorCorrectionLevel, mask int) int {
+	const generator = 0x537 // x^10 + x^8 + x^5 + x^4 + x^2 + x + 1
+
+	data := ecLevel.GetBits()<<3 | mask
+	remainder := data << 10
+	for bit := 14; bit >= 10; bit-- {
+		if remainder&(1<<bit) != 0 {
+			remainder ^= generator << (bit - 10)
+		}
+	}
+
+	return (data<<10 | remainder) ^ 0x5412
+}
+
+// writeFormatBits places both copies of the format information bits, least
+// significant bit first, as the extractor's readFormatInformationBits1/2 read them
+func writeFormatBits(grid [][]bool, bits int) {
+	dimension := len(grid)
+
+	// First copy around the top-left finder pattern, as (row, col)
+	topLeft := [15][2]int{
+		{0, 8}, {1, 8}, {2, 8}, {3, 8}, {4, 8}, {5, 8}, {7, 8}, {8, 8},
+		{8, 7}, {8, 5}, {8, 4}, {8, 3}, {8, 2}, {8, 1}, {8, 0},
+	}
+
+	for i := 0; i < 15; i++ {
+		bit := (bits>>i)&1 == 1
+		grid[topLeft[i][0]][topLeft[i][1]] = bit
+
+		// Second copy: bits 0-7 below the top-right finder, 8-14 beside the bottom-left one
+		if i < 8 {
+			grid[8][dimension-1-i] = bit
+		} else {
+			grid[dimension-7+(i-8)][8] = bit
+		}
+	}
+}
+
+// applyMask flips every data module for which the mask condition holds
+func applyMask(grid [][]bool, positions []ModulePosition, mask int) {
+	for _, pos := range positions {
+		if maskCondition(mask, pos.Row, pos.Col) {
+			grid[pos.Row][pos.Col] = !grid[pos.Row][pos.Col]
+		}
+	}
+}
+
+// maskCondition reports whether mask pattern 0-7 flips the module at (row, col)
+func maskCondition(mask, row, col int) bool {
+	switch mask {
+	case 0:
+		return (row+col)%2 == 0
+	case 1:
+		return row%2 == 0
+	case 2:
+		return col%3 == 0
+	case 3:
+		return (row+col)%3 == 0
+	case 4:
+		return (row/2+col/3)%2 == 0
+	case 5:
+		return (row*col)%2+(row*col)%3 == 0
+	case 6:
+		return ((row*col)%2+(row*col)%3)%2 == 0
+	case 7:
+		return ((row+col)%2+(row*col)%3)%2 == 0
+	default:
+		return false
+	}
+}
+
+// maskPenalty returns the total penalty score of a masked symbol
+func maskPenalty(grid [][]bool) int {
+	return penaltyRuns(grid) + penaltyBlocks(grid) + penaltyFinderLike(grid) + penaltyBalance(grid)
+}
+
+// penaltyRuns scores rule 1: runs of five or more same-colored modules in a row or column
+func penaltyRuns(grid [][]bool) int {
+	dimension := len(grid)
+	penalty := 0
+	for i := 0; i < dimension; i++ {
+		for _, horizontal := range []bool{true, false} {
+			run := 0
+			var prev bool
+			for j := 0; j < dimension; j++ {
+				cell := grid[i][j]
+				if !horizontal {
+					cell = grid[j][i]
+				}
+				if j > 0 && cell == prev {
+					run++
+				} else {
+					if run >= 5 {
+						penalty += penaltyN1 + run - 5
+					}
+					run = 1
+					prev = cell
+				}
+			}
+			if run >= 5 {
+				penalty += penaltyN1 + run - 5
+			}
+		}
+	}
+	return penalty
+}
+
+// penaltyBlocks scores rule 2: 2x2 blocks of same-colored modules (overlaps count separately)
+func penaltyBlocks(grid [][]bool) int {
+	penalty := 0
+	for row := 0; row < len(grid)-1; row++ {
+		for col := 0; col < len(grid)-1; col++ {
+			cell := grid[row][col]
+			if grid[row][col+1] == cell && grid[row+1][col] == cell && grid[row+1][col+1] == cell {
+				penalty += penaltyN2
+			}
+		}
+	}
+	return penalty
+}
+
+// penaltyFinderLike scores rule 3: dark-light-dark-dark-dark-light-dark sequences
+// preceded or followed by four light modules, which could be mistaken for a finder
+// pattern. Modules outside the symbol count as light (the quiet zone).
+func penaltyFinderLike(grid [][]bool) int {
+	dimension := len(grid)
+	finderLike := []bool{true, false, true, true, true, false, true}
+
+	// at returns module k of line i, horizontally or vertically, light outside the symbol
+	at := func(i, k int, horizontal bool) bool {
+		if k < 0 || k >= dimension {
+			return false
+		}
+		if horizontal {
+			return grid[i][k]
+		}
+		return grid[k][i]
+	}
+	lightRun := func(i, from, to int, horizontal bool) bool {
+		for k := from; k < to; k++ {
+			if at(i, k, horizontal) {
+				return false
+			}
+		}
+		return true
+	}
+
+	penalty := 0
+	for i := 0; i < dimension; i++ {
+		for _, horizontal := range []bool{true, false} {
+			for start := 0; start+len(finderLike) <= dimension; start++ {
+				matches := true
+				for k, dark := range finderLike {
+					if at(i, start+k, horizontal) != dark {
+						matches = false
+						break
+					}
+				}
+				if !matches {
+					continue
+				}
+				end := start + len(finderLike)
+				if lightRun(i, start-4, start, horizontal) || lightRun(i, end, end+4, horizontal) {
+					penalty += penaltyN3
+				}
+			}
+		}
+	}
+	return penalty
+}
+
+// penaltyBalance scores rule 4: deviation of the proportion of dark modules from 50%
+func penaltyBalance(grid [][]bool) int {
+	dark := 0
+	for _, row := range grid {
+		for _, cell := range row {
+			if cell {
+				dark++
+			}
+		}
+	}
+	total := len(grid) * len(grid)
+
+	// Number of whole 5% steps between the dark ratio and 50%
+	deviation := dark*2 - total
+	if deviation < 0 {
+		deviation = -deviation
+	}
+	return deviation * 10 / total * penaltyN4
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBestMaskByPenalty(t *testing.T) {
+	// Version 1 codes that the encoder masks with different patterns
+	messages := []string{"Hello", "Mask me", "xyz", "GF(256)", "Reed-Solomon", "12345"}
+
+	for _, message := range messages {
+		t.Run(message, func(t *testing.T) {
+			// Arrange: encode and read back the masked module grid
+			matrix, err := qrcode.NewQRCodeWriter().Encode(message, gozxing.BarcodeFormat_QR_CODE, 256, 256, nil)
+			require.NoError(t, err)
+
+			extractor := NewQRExtractor()
+			extractor.KeepMatrices = true
+			qrData, err := extractor.ExtractFromGoImage(bitMatrixToGray(matrix))
+			require.NoError(t, err)
+			require.Equal(t, 1, qrData.Version.GetVersionNumber())
+
+			// Undo the mask on the data modules only, keeping function patterns intact
+			unmasked, err := copyMatrix(qrData.MaskedMatrix)
+			require.NoError(t, err)
+			for _, pos := range DataModulePositions(qrData.Version) {
+				if maskCondition(int(qrData.DataMask), pos.Row, pos.Col) {
+					unmasked.Flip(pos.Col, pos.Row)
+				}
+			}
+
+			// Act
+			mask, err := BestMaskByPenalty(unmasked)
+
+			// Assert: the penalty rules pick the mask the encoder chose
+			require.NoError(t, err)
+			assert.Equal(t, int(qrData.DataMask), mask)
+		})
+	}
+}
+
+func TestBestMaskByPenalty_InvalidDimension(t *testing.T) {
+	matrix, err := gozxing.NewSquareBitMatrix(20)
+	require.NoError(t, err)
+
+	_, err = BestMaskByPenalty(matrix)
+	assert.ErrorIs(t, err, ErrInvalidDimension)
+
+	_, err = BestMaskByPenalty(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,112 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/makiuchi-d/gozxing"
+)
+
+// ErrNotPureBarcode is returned in PureBarcode mode when the image is not an axis-aligned,
+// evenly scaled QR code
+var ErrNotPureBarcode = errors.New("not a pure barcode")
+
+// extractPureBits samples the module grid of an image that contains nothing but an
+// upright QR code (and optionally a light quiet zone)
+//
+// This is what gozxing's QRCodeReader does for DecodeHintType_PURE_BARCODE; the hint
+// has no effect on the detector itself, and the reader's sampler is unexported. The
+// code's extent is the bounding box of the dark pixels, and the module size is taken
+// from the top-left finder pattern's 1:1:3:1:1 run along the diagonal. Each module
+// is then read at its centre, without searching for finder or alignment patterns.
+func extractPureBits(image *gozxing.BitMatrix) (*gozxing.BitMatrix, error) {
+	leftTop := image.GetTopLeftOnBit()
+	rightBottom := image.GetBottomRightOnBit()
+	if leftTop == nil || rightBottom == nil {
+		return nil, fmt.Errorf("%w: image has no dark modules", ErrNotPureBarcode)
+	}
+
+	moduleSize, err := pureModuleSize(leftTop, image)
+	if err != nil {
+		return nil, err
+	}
+
+	left, top := leftTop[0], leftTop[1]
+	right, bottom := rightBottom[0], rightBottom[1]
+	if left >= right || top >= bottom {
+		return nil, fmt.Errorf("%w: empty bounding box", ErrNotPureBarcode)
+	}
+
+	// The bottom-right module may be light, in which case the box is too narrow.
+	// The code is square, so use its height as the width.
+	if bottom-top != right-left {
+		right = left + (bottom - top)
+		if right >= image.GetWidth() {
+			return nil, fmt.Errorf("%w: code extends past the right edge", ErrNotPureBarcode)
+		}
+	}
+
+	dimension := int(math.Round(float64(right-left+1) / moduleSize))
+	if dimension <= 0 || dimension != int(math.Round(float64(bottom-top+1)/moduleSize)) {
+		return nil, fmt.Errorf("%w: code is not square", ErrNotPureBarcode)
+	}
+	if err := validateDimension(dimension, dimension); err != nil {
+		return nil, err
+	}
+
+	// Sample the middle of each module, but not past the code's last pixel
+	nudge := int(moduleSize / 2)
+	left += nudge
+	top += nudge
+	if tooFar := left + int(float64(dimension-1)*moduleSize) - right; tooFar > 0 {
+		if tooFar > nudge {
+			return nil, fmt.Errorf("%w: modules do not fit horizontally", ErrNotPureBarcode)
+		}
+		left -= tooFar
+	}
+	if tooFar := top + int(float64(dimension-1)*moduleSize) - bottom; tooFar > 0 {
+		if tooFar > nudge {
+			return nil, fmt.Errorf("%w: modules do not fit vertically", ErrNotPureBarcode)
+		}
+		top -= tooFar
+	}
+
+	bits, err := gozxing.NewBitMatrix(dimension, dimension)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bit matrix: %w", err)
+	}
+	for y := 0; y < dimension; y++ {
+		py := top + int(float64(y)*moduleSize)
+		for x := 0; x < dimension; x++ {
+			if image.Get(left+int(float64(x)*moduleSize), py) {
+				bits.Set(x, y)
+			}
+		}
+	}
+	return bits, nil
+}
+
+// pureModuleSize measures the module size in pixels along the diagonal of the top-left
+// finder pattern, which crosses 7 modules (dark, light, dark, light, dark) before
+// leaving it
+func pureModuleSize(leftTop []int, image *gozxing.BitMatrix) (float64, error) {
+	x, y := leftTop[0], leftTop[1]
+	inDark := true
+	transitions := 0
+	for x < image.GetWidth() && y < image.GetHeight() {
+		if inDark != image.Get(x, y) {
+			transitions++
+			if transitions == 5 {
+				break
+			}
+			inDark = !inDark
+		}
+		x++
+		y++
+	}
+	if x == image.GetWidth() || y == image.GetHeight() {
+		return 0, fmt.Errorf("%w: no finder pattern at the top-left corner", ErrNotPureBarcode)
+	}
+	return float64(x-leftTop[0]) / 7, nil
+}
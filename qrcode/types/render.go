@@ -0,0 +1,42 @@
+package types
+
+import "strings"
+
+// RenderASCII draws the module grid as text, one line per row and one character per module
+//
+// Dark modules are drawn as █ and light modules as a space. With annotate set,
+// function modules (finder and alignment patterns, separators, timing patterns,
+// format and version information, as classified by isFunctionModule) are drawn as
+// ▓ when dark and ░ when light instead, so the data region stands out.
+//
+// qrData.BitMatrix has the data mask removed from every module, function patterns
+// included, so the mask is applied again to draw the symbol as printed. Terminal
+// characters are about twice as tall as wide, so the output looks stretched.
+//
+// Example (top-left corner of a code, annotated):
+//
+//	▓▓▓▓▓▓▓░▓██ █░
+//	▓░░░░░▓░▓███ ░
+//	▓░▓▓▓░▓░▓█ █ ░
+func (qrData *QRCodeData) RenderASCII(annotate bool) string {
+	matrix := qrData.BitMatrix
+	var sb strings.Builder
+	for row := 0; row < matrix.GetHeight(); row++ {
+		for col := 0; col < matrix.GetWidth(); col++ {
+			dark := matrix.Get(col, row) != maskCondition(int(qrData.DataMask), row, col)
+			function := annotate && qrData.Version != nil && isFunctionModule(row, col, qrData.Version)
+			switch {
+			case function && dark:
+				sb.WriteRune('▓')
+			case function:
+				sb.WriteRune('░')
+			case dark:
+				sb.WriteRune('█')
+			default:
+				sb.WriteRune(' ')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
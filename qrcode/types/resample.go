@@ -0,0 +1,82 @@
+package types
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode/detector"
+)
+
+// gridResampler samples the module grid of a detected code again at a given dimension
+//
+// The detector estimates the dimension from the distance between the finder
+// patterns and samples the image once. When the version information later shows
+// that estimate to be off (see reconcileVersion), the grid has to be rebuilt from
+// the image rather than reinterpreted: every module is sampled at the wrong place.
+type gridResampler func(dimension int) (*gozxing.BitMatrix, error)
+
+// newGridResampler returns a resampler for a code found by the detector in image
+//
+// points are the detector result's points: bottom-left, top-left and top-right
+// finder pattern centres, in that order (any alignment pattern is searched for
+// again, since where it is expected depends on the dimension).
+func newGridResampler(image *gozxing.BitMatrix, points []gozxing.ResultPoint) gridResampler {
+	if len(points) < 3 {
+		return nil
+	}
+	bottomLeft, topLeft, topRight := points[0], points[1], points[2]
+
+	return func(dimension int) (*gozxing.BitMatrix, error) {
+		// The finder pattern centres are 3.5 modules in from the edges
+		modulesBetweenFPCenters := float64(dimension - 7)
+		moduleSize := (gozxing.ResultPoint_Distance(topLeft, topRight) +
+			gozxing.ResultPoint_Distance(topLeft, bottomLeft)) / 2 / modulesBetweenFPCenters
+		if moduleSize < 1 {
+			return nil, fmt.Errorf("module size %.2f for dimension %d is below one pixel", moduleSize, dimension)
+		}
+
+		// Every version from 2 up has an alignment pattern 3 modules in from where a
+		// bottom-right finder pattern would be; without one the corner is extrapolated
+		var alignment *detector.AlignmentPattern
+		if dimension > 21 {
+			alignment = findBottomRightAlignment(image, topLeft, topRight, bottomLeft, moduleSize, modulesBetweenFPCenters)
+		}
+
+		transform := detector.Detector_createTransform(topLeft, topRight, bottomLeft, alignment, dimension)
+		bits, err := detector.Detector_sampleGrid(image, transform, dimension)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resample grid at dimension %d: %w", dimension, err)
+		}
+		return bits, nil
+	}
+}
+
+// findBottomRightAlignment looks for the bottom-right alignment pattern where a code
+// with the given finder pattern spacing has it, widening the search like gozxing's
+// detector; nil if there is none
+func findBottomRightAlignment(image *gozxing.BitMatrix, topLeft, topRight, bottomLeft gozxing.ResultPoint,
+	moduleSize, modulesBetweenFPCenters float64) *detector.AlignmentPattern {
+	bottomRightX := topRight.GetX() - topLeft.GetX() + bottomLeft.GetX()
+	bottomRightY := topRight.GetY() - topLeft.GetY() + bottomLeft.GetY()
+	correctionToTopLeft := 1 - 3/modulesBetweenFPCenters
+	estX := topLeft.GetX() + correctionToTopLeft*(bottomRightX-topLeft.GetX())
+	estY := topLeft.GetY() + correctionToTopLeft*(bottomRightY-topLeft.GetY())
+
+	for allowanceFactor := 4.0; allowanceFactor <= 16; allowanceFactor *= 2 {
+		allowance := allowanceFactor * moduleSize
+		left := int(math.Max(estX-allowance, 0))
+		right := int(math.Min(estX+allowance, float64(image.GetWidth()-1)))
+		top := int(math.Max(estY-allowance, 0))
+		bottom := int(math.Min(estY+allowance, float64(image.GetHeight()-1)))
+		if float64(right-left) < 3*moduleSize || float64(bottom-top) < 3*moduleSize {
+			return nil
+		}
+
+		finder := detector.NewAlignmentPatternFinder(image, left, top, right-left, bottom-top, moduleSize, nil)
+		if alignment, err := finder.Find(); err == nil {
+			return alignment
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package testutil
+
+import (
+	"fmt"
+
+	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
+)
+
+// ElementsFromIndices converts a slice of element indices to field elements
+// Each index is passed to field.Element, so the result follows the field's own indexing
+func ElementsFromIndices(field gfpn.Field, indices []int) []gfpn.Element {
+	elements := make([]gfpn.Element, len(indices))
+	for i, idx := range indices {
+		elements[i] = field.Element(idx)
+	}
+	return elements
+}
+
+// ElementsToStrings converts a slice of field elements to their string representations
+// An empty input gives an empty (non-nil) slice so it encodes as [] rather than null in JSON
+func ElementsToStrings(elements []gfpn.Element) []string {
+	result := make([]string, len(elements))
+	for i, e := range elements {
+		result[i] = e.String()
+	}
+	return result
+}
+
+// ParseElements converts string representations back to field elements
+// The lookup table is built once from field.Elements(), so parsing m strings costs
+// O(order + m) instead of scanning the whole field for every string
+//
+// Returns an error naming the first string that is not an element of the field
+func ParseElements(field gfpn.Field, values []string) ([]gfpn.Element, error) {
+	lookup := make(map[string]gfpn.Element, field.Order())
+	for _, e := range field.Elements() {
+		lookup[e.String()] = e
+	}
+
+	elements := make([]gfpn.Element, len(values))
+	for i, s := range values {
+		e, ok := lookup[s]
+		if !ok {
+			return nil, fmt.Errorf("value %d (%q) is not an element of the field", i, s)
+		}
+		elements[i] = e
+	}
+	return elements, nil
+}
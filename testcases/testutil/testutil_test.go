@@ -0,0 +1,63 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/jalphad/abstract_algebra/exercises/3-gfpn"
+)
+
+// qrField creates GF(256) with the QR code polynomial x^8 + x^4 + x^3 + x^2 + 1
+func qrField(t testing.TB) gfpn.Field {
+	t.Helper()
+	field, err := gfpn.NewField(2, 8, []int{1, 0, 1, 1, 1, 0, 0, 0, 1})
+	if err != nil {
+		t.Fatalf("Failed to create GF(256): %v", err)
+	}
+	return field
+}
+
+func TestRoundTrip(t *testing.T) {
+	field := qrField(t)
+
+	indices := make([]int, field.Order())
+	for i := range indices {
+		indices[i] = i
+	}
+
+	elements := ElementsFromIndices(field, indices)
+	strings := ElementsToStrings(elements)
+	parsed, err := ParseElements(field, strings)
+	if err != nil {
+		t.Fatalf("ParseElements failed: %v", err)
+	}
+
+	seen := make(map[string]bool, len(strings))
+	for i := range indices {
+		if !parsed[i].Equal(elements[i]) {
+			t.Errorf("index %d: parsed %s, want %s", i, parsed[i], elements[i])
+		}
+		if seen[strings[i]] {
+			t.Errorf("index %d: duplicate string %q", i, strings[i])
+		}
+		seen[strings[i]] = true
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	field := qrField(t)
+
+	if got := ElementsToStrings(nil); got == nil || len(got) != 0 {
+		t.Errorf("ElementsToStrings(nil) = %#v, want empty non-nil slice", got)
+	}
+	if got := ElementsFromIndices(field, nil); len(got) != 0 {
+		t.Errorf("ElementsFromIndices(nil) = %v, want empty", got)
+	}
+}
+
+func TestParseElementsUnknown(t *testing.T) {
+	field := qrField(t)
+
+	if _, err := ParseElements(field, []string{field.One().String(), "not-an-element"}); err == nil {
+		t.Errorf("expected an error for an unknown element string")
+	}
+}